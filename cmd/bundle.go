@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/bundle"
+	"github.com/qaware/minikube-support/pkg/github"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/lock"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+)
+
+// DefaultBundle is the default path of the created bundle.
+const DefaultBundle = "minikube-support-bundle.tar.gz"
+
+var createBundle = bundle.Create
+
+// bundleOptions contains the flag to install the plugins from a bundle.
+type bundleOptions struct {
+	bundle  *bundle.Bundle
+	handler kubernetes.ContextHandler
+	path    string
+}
+
+// addFlags adds the bundle flag to the given command.
+func (o *bundleOptions) addFlags(command *cobra.Command) {
+	command.PersistentFlags().StringVar(&o.path, "bundle", "", "Install the charts, the CoreDNS release and the images from the given bundle instead of downloading them.\nSee \"minikube-support bundle create\".")
+}
+
+// run opens the bundle, loads its images into the cluster and runs the function. Without bundle the function
+// runs as usual.
+func (o *bundleOptions) run(f func() error) error {
+	if o.path == "" || o.bundle == nil {
+		return f()
+	}
+	if e := o.bundle.Open(o.path); e != nil {
+		return e
+	}
+	defer o.bundle.Close()
+
+	provider, e := o.handler.GetClusterProvider()
+	if e != nil {
+		return fmt.Errorf("can not detect cluster: %s", e)
+	}
+	if provider == nil {
+		return fmt.Errorf("can not load images of bundle %s: the cluster is neither minikube nor kind", o.path)
+	}
+	if e := o.bundle.LoadImages(provider); e != nil {
+		return fmt.Errorf("can not load images of bundle %s: %s", o.path, e)
+	}
+	return f()
+}
+
+type bundleCreateOptions struct {
+	registry apis.InstallablePluginRegistry
+	manager  helm.Manager
+	client   github.Client
+	locks    lockOptions
+	output   string
+	platform apis.Platform
+}
+
+// NewBundleCommand creates the command to create bundles for offline installations.
+func NewBundleCommand(registry apis.InstallablePluginRegistry, l *lock.Lock, manager helm.Manager, client github.Client) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manages bundles to install the plugins without internet access.",
+	}
+	command.AddCommand(newBundleCreateCommand(registry, l, manager, client))
+	return command
+}
+
+func newBundleCreateCommand(registry apis.InstallablePluginRegistry, l *lock.Lock, manager helm.Manager, client github.Client) *cobra.Command {
+	options := &bundleCreateOptions{
+		registry: registry,
+		manager:  manager,
+		client:   client,
+		locks:    lockOptions{lock: l},
+	}
+	command := &cobra.Command{
+		Use:   "create",
+		Short: "Downloads everything the cluster plugins need into a bundle.",
+		Long: "The create command downloads the helm charts, the CoreDNS release and the container images used by the " +
+			"cluster plugins into a bundle. It is written as archive if the output ends with .tar.gz or .tgz, otherwise " +
+			"into a directory. Install the bundle with \"minikube-support install --bundle <bundle>\". The versions are " +
+			"taken from the lock file. The images are pulled using docker. Use --os and --arch to create the bundle for " +
+			"another machine.",
+		Args: cobra.NoArgs,
+		RunE: options.run,
+	}
+	local := apis.LocalPlatform()
+	command.Flags().StringVarP(&options.output, "output", "o", DefaultBundle, "The bundle archive or directory to create.")
+	command.Flags().StringVar(&options.platform.OS, "os", local.OS, "The os of the machine the bundle is installed on.")
+	command.Flags().StringVar(&options.platform.Arch, "arch", local.Arch, "The architecture of the machine the bundle is installed on and of the cluster nodes.")
+	options.locks.addFlags(command)
+	return command
+}
+
+func (o *bundleCreateOptions) run(_ *cobra.Command, _ []string) error {
+	var plugins []apis.BundlablePlugin
+	for _, plugin := range o.registry.ListPlugins() {
+		if bundlable, ok := plugin.(apis.BundlablePlugin); ok {
+			plugins = append(plugins, bundlable)
+		}
+	}
+
+	var err error
+	e := o.locks.run(lock.Honour, func() {
+		err = createBundle(o.output, o.platform, plugins, o.manager, o.client)
+	})
+	if err != nil {
+		return err
+	}
+	return e
+}
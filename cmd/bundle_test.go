@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/bundle"
+	"github.com/qaware/minikube-support/pkg/github"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	"github.com/qaware/minikube-support/pkg/plugins"
+)
+
+type dummyBundlablePlugin struct {
+	DummyPlugin
+}
+
+func (*dummyBundlablePlugin) Artifacts() apis.Artifacts {
+	return apis.Artifacts{}
+}
+
+func Test_bundleCreateOptions_run(t *testing.T) {
+	defer func() { createBundle = bundle.Create }()
+	var got []apis.BundlablePlugin
+	createBundle = func(path string, platform apis.Platform, plugins []apis.BundlablePlugin, _ helm.Manager, _ github.Client) error {
+		assert.Equal(t, DefaultBundle, path)
+		assert.Equal(t, apis.Platform{OS: "darwin", Arch: "arm64"}, platform)
+		got = plugins
+		return errors.New("failed")
+	}
+	registry := plugins.NewInstallablePluginRegistry()
+	bundlable := &dummyBundlablePlugin{DummyPlugin{name: "bundlable"}}
+	registry.AddPlugins(&DummyPlugin{}, bundlable)
+
+	command := newBundleCreateCommand(registry, nil, nil, nil)
+	command.SetArgs([]string{"--os", "darwin", "--arch", "arm64"})
+	command.SilenceUsage = true
+	command.SilenceErrors = true
+
+	assert.EqualError(t, command.Execute(), "failed")
+	assert.Equal(t, []apis.BundlablePlugin{bundlable}, got)
+}
+
+func Test_bundleOptions_run(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+		wantRun bool
+	}{
+		{"without bundle", "", false, true},
+		{"missing bundle", filepath.Join(t.TempDir(), "missing"), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &bundleOptions{bundle: bundle.New(), handler: fake.NewContextHandler(nil, nil), path: tt.path}
+			run := false
+
+			e := o.run(func() error {
+				run = true
+				return nil
+			})
+
+			assert.Equal(t, tt.wantErr, e != nil)
+			assert.Equal(t, tt.wantRun, run)
+			assert.False(t, o.bundle.IsOpen())
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/plugins/certmanager"
+	"github.com/qaware/minikube-support/pkg/plugins/mkcert"
+)
+
+// CaOptions contains the options to rotate and retire the CA.
+type CaOptions struct {
+	rotator        certmanager.Rotator
+	installTrust   func(dir string) (string, error)
+	uninstallTrust func(dir string) (string, error)
+	wait           time.Duration
+	pollInterval   time.Duration
+	force          bool
+}
+
+// NewCaOptions creates the options to rotate the CA of the cluster of the given context handler.
+func NewCaOptions(handler kubernetes.ContextHandler) *CaOptions {
+	return &CaOptions{
+		rotator:        certmanager.NewRotator(handler),
+		installTrust:   mkcert.InstallTrust,
+		uninstallTrust: mkcert.UninstallTrust,
+		wait:           5 * time.Minute,
+		pollInterval:   5 * time.Second,
+	}
+}
+
+// NewCaCommand creates the ca command with the sub commands to rotate and retire the CA.
+func NewCaCommand(handler kubernetes.ContextHandler) *cobra.Command {
+	options := NewCaOptions(handler)
+
+	command := &cobra.Command{
+		Use:   "ca",
+		Short: "Manage the CA of the ca-issuer.",
+	}
+	rotate := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the CA with a new one and re-issue all certificates of the ca-issuer.",
+		Long: "The rotate command generates a new CA and installs it in the local trust store alongside the previous one. " +
+			"Then it updates the secret of the ca-issuer and deletes the secrets of all certificates issued by the ca-issuer, " +
+			"so that cert-manager issues them again. As soon as all certificates are signed by the new CA, the previous one " +
+			"is retired. If this takes longer than --wait, run the retire command later. If the ca-issuer does not use " +
+			"the current CA yet, an interrupted rotation is resumed without generating another CA.",
+		Run: options.RunRotate,
+	}
+	rotate.Flags().DurationVar(&options.wait, "wait", options.wait, "The time to wait for the re-issued certificates before the previous CA is retired.")
+	retire := &cobra.Command{
+		Use:   "retire",
+		Short: "Remove the previous CAs once no certificate of the ca-issuer is signed by them.",
+		Run:   options.RunRetire,
+	}
+	retire.Flags().BoolVar(&options.force, "force", false, "Retire the previous CAs even if certificates are not re-issued yet.")
+	command.AddCommand(rotate, retire)
+	return command
+}
+
+// RunRotate rotates the CA and retires the previous one after all certificates are re-issued.
+func (o *CaOptions) RunRotate(_ *cobra.Command, _ []string) {
+	authority, e := o.rotate()
+	if e != nil {
+		logrus.Errorf("Unable to rotate the CA: %s", e)
+		return
+	}
+	logrus.Info("Run `minikube-support update registry` to trust the new CA in the minikube nodes.")
+
+	pending, e := o.waitForReissue(authority)
+	if e != nil {
+		logrus.Errorf("Unable to check the re-issued certificates: %s", e)
+		return
+	}
+	if len(pending) > 0 {
+		logrus.Warnf("The previous CA is kept, because these certificates are not re-issued yet: %s\nRun `minikube-support ca retire` later.", strings.Join(pending, ", "))
+		return
+	}
+	o.retire()
+}
+
+// RunRetire retires the previous CAs if no certificate is signed by them anymore.
+func (o *CaOptions) RunRetire(_ *cobra.Command, _ []string) {
+	if !o.force {
+		authority, e := ca.Default()
+		if e != nil {
+			logrus.Errorf("Unable to load the RootCA: %s", e)
+			return
+		}
+		pending, e := o.rotator.Pending(authority)
+		if e != nil {
+			logrus.Errorf("Unable to check the re-issued certificates: %s", e)
+			return
+		}
+		if len(pending) > 0 {
+			logrus.Errorf("These certificates are not re-issued yet: %s\nUse --force to retire the previous CAs anyway.", strings.Join(pending, ", "))
+			return
+		}
+	}
+	o.retire()
+}
+
+// rotate replaces the CA, trusts the new one locally, updates the ca-issuer and triggers the re-issue of
+// all certificates. If the ca-issuer does not use the current CA yet, a previous rotation was interrupted.
+// Then it is resumed with the current CA instead of replacing it again.
+func (o *CaOptions) rotate() (*ca.CA, error) {
+	dir, e := ca.DefaultDir()
+	if e != nil {
+		return nil, e
+	}
+	authority, e := ca.LoadOrCreate(dir)
+	if e != nil {
+		return nil, e
+	}
+	applied, e := o.rotator.Applied(authority)
+	if e != nil {
+		return nil, fmt.Errorf("can not check the ca-issuer: %s", e)
+	}
+	if applied {
+		var previousDir string
+		authority, previousDir, e = ca.Rotate(dir)
+		if e != nil {
+			return nil, e
+		}
+		logrus.Infof("Created a new root CA. The previous one is kept in %s until it is retired.", previousDir)
+	} else {
+		logrus.Info("The ca-issuer does not use the current root CA yet. Resuming the rotation with it.")
+	}
+
+	if output, e := o.installTrust(dir); e != nil {
+		logrus.Warnf("Can not install the new Root CA in the local trust store. Error: %s\nOutput: %s", e, output)
+	}
+	if e := o.rotator.ApplySecret(); e != nil {
+		return nil, fmt.Errorf("can not update the ca-issuer: %s", e)
+	}
+	names, e := o.rotator.Reissue()
+	if e != nil {
+		return nil, fmt.Errorf("can not re-issue the certificates: %s", e)
+	}
+	logrus.Infof("Re-issuing %d certificates: %s", len(names), strings.Join(names, ", "))
+	return authority, nil
+}
+
+// waitForReissue waits until all certificates are signed by the given CA or the wait time is over. It returns
+// the certificates which are still not re-issued.
+func (o *CaOptions) waitForReissue(authority *ca.CA) ([]string, error) {
+	deadline := time.Now().Add(o.wait)
+	for {
+		pending, e := o.rotator.Pending(authority)
+		if e != nil || len(pending) == 0 || !time.Now().Before(deadline) {
+			return pending, e
+		}
+		logrus.Debugf("Waiting for the certificates: %s", strings.Join(pending, ", "))
+		time.Sleep(o.pollInterval)
+	}
+}
+
+// retire removes all previous CAs from the local trust store and deletes them.
+func (o *CaOptions) retire() {
+	dir, e := ca.DefaultDir()
+	if e != nil {
+		logrus.Errorf("Unable to retire the previous CAs: %s", e)
+		return
+	}
+	previousDirs, e := ca.Retired(dir)
+	if e != nil {
+		logrus.Errorf("Unable to retire the previous CAs: %s", e)
+		return
+	}
+	for _, previousDir := range previousDirs {
+		if output, e := o.uninstallTrust(previousDir); e != nil {
+			logrus.Warnf("Can not remove the previous Root CA from the local trust store. Error: %s\nOutput: %s", e, output)
+		}
+		if e := ca.Retire(previousDir); e != nil {
+			logrus.Errorf("Unable to retire the previous CA: %s", e)
+			return
+		}
+	}
+	logrus.Infof("%d previous CAs retired.", len(previousDirs))
+}
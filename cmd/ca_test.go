@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+type testRotator struct {
+	applyErr error
+	outdated bool
+	pending  []string
+	applied  bool
+	reissued bool
+}
+
+func (r *testRotator) ApplySecret() error {
+	r.applied = true
+	return r.applyErr
+}
+
+func (r *testRotator) Applied(_ *ca.CA) (bool, error) {
+	return !r.outdated, nil
+}
+
+func (r *testRotator) Reissue() ([]string, error) {
+	r.reissued = true
+	return []string{"default/app"}, nil
+}
+
+func (r *testRotator) Pending(_ *ca.CA) ([]string, error) {
+	return r.pending, nil
+}
+
+func newTestCaOptions(rotator *testRotator, installed *[]string, uninstalled *[]string) *CaOptions {
+	return &CaOptions{
+		rotator: rotator,
+		installTrust: func(dir string) (string, error) {
+			*installed = append(*installed, dir)
+			return "", nil
+		},
+		uninstallTrust: func(dir string) (string, error) {
+			*uninstalled = append(*uninstalled, dir)
+			return "", errors.New("not installed")
+		},
+	}
+}
+
+func TestCaOptions_RunRotate(t *testing.T) {
+	hook := test.NewGlobal()
+	tests := []struct {
+		name         string
+		rotator      *testRotator
+		wantRetired  int
+		wantReissued bool
+		lastLogEntry string
+	}{
+		{"retired", &testRotator{}, 0, true, "1 previous CAs retired."},
+		{"pending", &testRotator{pending: []string{"default/app"}}, 1, true, "The previous CA is kept, because these certificates are not re-issued yet: default/app"},
+		{"ca-issuer failed", &testRotator{applyErr: errors.New("no cluster")}, 1, false, "Unable to rotate the CA: can not update the ca-issuer: no cluster"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv(ca.DirEnv, dir)
+			previous, _ := ca.LoadOrCreate(dir)
+			var installed, uninstalled []string
+			o := newTestCaOptions(tt.rotator, &installed, &uninstalled)
+
+			o.RunRotate(&cobra.Command{}, nil)
+
+			current, _ := ca.Load(dir)
+			assert.NotEqual(t, previous.Certificate.Raw, current.Certificate.Raw)
+			assert.Equal(t, []string{dir}, installed)
+			assert.True(t, tt.rotator.applied)
+			assert.Equal(t, tt.wantReissued, tt.rotator.reissued)
+			retired, _ := ca.Retired(dir)
+			assert.Len(t, retired, tt.wantRetired)
+			assert.Len(t, uninstalled, 1-tt.wantRetired)
+			testutils.CheckLogEntry(t, hook, tt.lastLogEntry)
+		})
+	}
+}
+
+func TestCaOptions_RunRotate_resume(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(ca.DirEnv, dir)
+	previous, _ := ca.LoadOrCreate(dir)
+	hook := test.NewGlobal()
+	rotator := &testRotator{outdated: true}
+	var installed, uninstalled []string
+	o := newTestCaOptions(rotator, &installed, &uninstalled)
+
+	o.RunRotate(&cobra.Command{}, nil)
+
+	current, _ := ca.Load(dir)
+	assert.Equal(t, previous.Certificate.Raw, current.Certificate.Raw)
+	assert.True(t, rotator.applied)
+	assert.True(t, rotator.reissued)
+	assert.Contains(t, hook.AllEntries()[0].Message, "Resuming the rotation")
+}
+
+func TestCaOptions_RunRetire(t *testing.T) {
+	hook := test.NewGlobal()
+	tests := []struct {
+		name         string
+		pending      []string
+		force        bool
+		wantRetired  int
+		lastLogEntry string
+	}{
+		{"no pending", nil, false, 0, "1 previous CAs retired."},
+		{"pending", []string{"default/app"}, false, 1, "These certificates are not re-issued yet: default/app"},
+		{"pending forced", []string{"default/app"}, true, 0, "1 previous CAs retired."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv(ca.DirEnv, dir)
+			_, _ = ca.LoadOrCreate(dir)
+			_, _, _ = ca.Rotate(dir)
+			var installed, uninstalled []string
+			o := newTestCaOptions(&testRotator{pending: tt.pending}, &installed, &uninstalled)
+			o.force = tt.force
+
+			o.RunRetire(&cobra.Command{}, nil)
+
+			retired, _ := ca.Retired(dir)
+			assert.Len(t, retired, tt.wantRetired)
+			testutils.CheckLogEntry(t, hook, tt.lastLogEntry)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+)
+
+// CertIssueOptions contains the options to issue a local certificate.
+type CertIssueOptions struct {
+	sans      []string
+	outputDir string
+	name      string
+	format    string
+	password  string
+	days      int
+}
+
+// NewCertIssueOptions creates the default options to issue a local certificate.
+func NewCertIssueOptions() *CertIssueOptions {
+	return &CertIssueOptions{
+		outputDir: ".",
+		format:    "pem",
+		password:  ca.DefaultPassword,
+		days:      int(ca.DefaultValidity / (24 * time.Hour)),
+	}
+}
+
+// NewCertCommand creates the cert command which manages certificates outside the cluster.
+func NewCertCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "cert",
+		Short: "Manage certificates signed by the CA of the ca-issuer.",
+	}
+	command.AddCommand(NewCertIssueCommand())
+	return command
+}
+
+// NewCertIssueCommand creates the command to issue a certificate for local usage, e.g. for a dev server.
+func NewCertIssueCommand() *cobra.Command {
+	options := NewCertIssueOptions()
+
+	command := &cobra.Command{
+		Use:   "issue",
+		Short: "Issue a certificate signed by the CA of the ca-issuer.",
+		Long: "The issue command creates a key, certificate and chain signed by the same CA that backs the " +
+			"ca-issuer ClusterIssuer. So the certificate is trusted everywhere the cluster certificates are trusted.\n\n" +
+			"The pem format writes <name>.pem, <name>-key.pem, <name>-chain.pem and <name>-fullchain.pem. The pkcs12 " +
+			"and jks formats write a single key store <name>.p12 or <name>.jks containing the key, certificate and chain.",
+		Example: "  minikube-support cert issue --san api.minikube --san '*.api.minikube' -o ./certs",
+		Run:     options.Run,
+	}
+	flags := command.Flags()
+	flags.StringArrayVar(&options.sans, "san", nil, "A subject alternative name of the certificate. Can be a host name including wildcards, an ip address or an email address. Can be given multiple times.")
+	flags.StringVarP(&options.outputDir, "output", "o", options.outputDir, "The directory to write the files into.")
+	flags.StringVar(&options.name, "name", "", "The base name of the written files. Defaults to the first subject alternative name.")
+	flags.StringVarP(&options.format, "format", "f", options.format, "The output format: pem, pkcs12 or jks.")
+	flags.StringVar(&options.password, "password", options.password, "The password of the pkcs12 or jks key store.")
+	flags.IntVar(&options.days, "days", options.days, "The validity of the certificate in days.")
+	_ = command.MarkFlagRequired("san")
+	return command
+}
+
+// Run issues the certificate and logs the written files.
+func (o *CertIssueOptions) Run(_ *cobra.Command, _ []string) {
+	files, e := o.issue()
+	if e != nil {
+		logrus.Errorf("Unable to issue certificate: %s", e)
+		return
+	}
+	logrus.Infof("Certificate for %s issued:\n%s", strings.Join(o.sans, ", "), strings.Join(files, "\n"))
+}
+
+// issue creates the certificate using the default CA and writes it in the selected format.
+func (o *CertIssueOptions) issue() ([]string, error) {
+	if o.days <= 0 {
+		return nil, fmt.Errorf("invalid validity of %d days", o.days)
+	}
+	contents, e := o.encoders()
+	if e != nil {
+		return nil, e
+	}
+	authority, e := ca.Default()
+	if e != nil {
+		return nil, fmt.Errorf("unable to load the RootCA: %s", e)
+	}
+	leaf, e := authority.Issue(o.sans, time.Duration(o.days)*24*time.Hour)
+	if e != nil {
+		return nil, e
+	}
+
+	if e := os.MkdirAll(o.outputDir, 0755); e != nil {
+		return nil, fmt.Errorf("can not create output directory: %s", e)
+	}
+	name := o.name
+	if name == "" {
+		name = strings.ReplaceAll(o.sans[0], "*", "_wildcard")
+	}
+	var files []string
+	for _, content := range contents {
+		data, e := content.encode(leaf)
+		if e != nil {
+			return nil, e
+		}
+		file := filepath.Join(o.outputDir, name+content.suffix)
+		if e := os.WriteFile(file, data, content.mode); e != nil {
+			return nil, fmt.Errorf("can not write %s: %s", file, e)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// certContent is a single file written for an issued certificate.
+type certContent struct {
+	suffix string
+	mode   os.FileMode
+	encode func(leaf *ca.Leaf) ([]byte, error)
+}
+
+// encoders returns the files to write for the selected format.
+func (o *CertIssueOptions) encoders() ([]certContent, error) {
+	switch o.format {
+	case "pem":
+		return []certContent{
+			{".pem", 0644, func(leaf *ca.Leaf) ([]byte, error) { return ca.EncodeCertificatesPEM(leaf.Certificate), nil }},
+			{"-key.pem", 0600, func(leaf *ca.Leaf) ([]byte, error) { return leaf.KeyPEM() }},
+			{"-chain.pem", 0644, func(leaf *ca.Leaf) ([]byte, error) { return ca.EncodeCertificatesPEM(leaf.Chain...), nil }},
+			{"-fullchain.pem", 0644, func(leaf *ca.Leaf) ([]byte, error) { return leaf.CertificatePEM(), nil }},
+		}, nil
+	case "pkcs12", "p12":
+		return []certContent{{".p12", 0600, func(leaf *ca.Leaf) ([]byte, error) { return leaf.EncodePKCS12(o.password) }}}, nil
+	case "jks":
+		return []certContent{{".jks", 0600, func(leaf *ca.Leaf) ([]byte, error) { return leaf.EncodeJKS(o.password) }}}, nil
+	}
+	return nil, fmt.Errorf("unknown format %s: expected pem, pkcs12 or jks", o.format)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+)
+
+func TestCertIssueOptions_issue(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		days    int
+		files   []string
+		wantErr string
+	}{
+		{"pem", "pem", 30, []string{"_wildcard.api.minikube.pem", "_wildcard.api.minikube-key.pem", "_wildcard.api.minikube-chain.pem", "_wildcard.api.minikube-fullchain.pem"}, ""},
+		{"pkcs12", "pkcs12", 30, []string{"_wildcard.api.minikube.p12"}, ""},
+		{"jks", "jks", 30, []string{"_wildcard.api.minikube.jks"}, ""},
+		{"unknown format", "der", 30, nil, "unknown format der: expected pem, pkcs12 or jks"},
+		{"invalid validity", "pem", 0, nil, "invalid validity of 0 days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ca.DirEnv, t.TempDir())
+			output := filepath.Join(t.TempDir(), "certs")
+			o := NewCertIssueOptions()
+			o.sans = []string{"*.api.minikube", "api.minikube"}
+			o.outputDir = output
+			o.format = tt.format
+			o.days = tt.days
+
+			files, e := o.issue()
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, e, tt.wantErr)
+				return
+			}
+			assert.NoError(t, e)
+			var expected []string
+			for _, file := range tt.files {
+				expected = append(expected, filepath.Join(output, file))
+			}
+			assert.Equal(t, expected, files)
+		})
+	}
+}
+
+func TestCertIssueOptions_issue_signedByCA(t *testing.T) {
+	t.Setenv(ca.DirEnv, t.TempDir())
+	authority, e := ca.Default()
+	assert.NoError(t, e)
+	output := t.TempDir()
+	o := NewCertIssueOptions()
+	o.sans = []string{"api.minikube"}
+	o.outputDir = output
+
+	_, e = o.issue()
+	assert.NoError(t, e)
+
+	data, _ := os.ReadFile(filepath.Join(output, "api.minikube.pem"))
+	block, _ := pem.Decode(data)
+	certificate, e := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, e)
+	assert.NoError(t, certificate.CheckSignatureFrom(authority.Certificate))
+	assert.Equal(t, []string{"api.minikube"}, certificate.DNSNames)
+
+	chain, _ := os.ReadFile(filepath.Join(output, "api.minikube-chain.pem"))
+	assert.Equal(t, authority.CertificatePEM(), chain)
+
+	o.name = "server"
+	o.format = "pkcs12"
+	_, e = o.issue()
+	assert.NoError(t, e)
+	data, _ = os.ReadFile(filepath.Join(output, "server.p12"))
+	_, certificate, chainCertificates, e := pkcs12.DecodeChain(data, ca.DefaultPassword)
+	assert.NoError(t, e)
+	assert.Equal(t, []string{"api.minikube"}, certificate.DNSNames)
+	assert.Equal(t, authority.Certificate.Raw, chainCertificates[0].Raw)
+}
@@ -3,10 +3,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/bundle"
+	"github.com/qaware/minikube-support/pkg/github"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/lock"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
 	"github.com/qaware/minikube-support/pkg/plugins"
+	"github.com/qaware/minikube-support/pkg/plugins/ingress"
+	"github.com/qaware/minikube-support/pkg/plugins/k8sdns"
 	"github.com/spf13/cobra"
 )
 
@@ -15,10 +23,26 @@ type RootCommandOptions struct {
 	kubeConfig                string
 	contextName               string
 	githubAccessToken         string
+	helmManager               string
+	ingressController         string
 	installablePluginRegistry apis.InstallablePluginRegistry
 	startStopPluginRegistry   apis.StartStopPluginRegistry
 	preRunInit                []PreRunInit
 	contextNameSupplier       ContextNameSupplier
+	contexts                  []string
+	kubeContexts              []kubernetes.KubeContext
+	ingressFilter             k8sdns.Filter
+	serviceFilter             k8sdns.Filter
+	portForwards              []string
+	portForwardDns            bool
+	mounts                    []string
+	trustBundleNamespaces     []string
+	contextHandler            kubernetes.ContextHandler
+	lock                      *lock.Lock
+	bundle                    *bundle.Bundle
+	charts                    helm.Manager
+	releases                  github.Client
+	values                    helm.Overrides
 }
 
 // PreRunInit defines the interface for small helper functions which will perform
@@ -39,6 +63,8 @@ func NewRootCmd() (*cobra.Command, *RootCommandOptions) {
 	flags := cmd.PersistentFlags()
 	flags.StringVar(&options.kubeConfig, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests.")
 	flags.StringVar(&options.contextName, "context", "", "The name of the kubeconfig context to use")
+	flags.StringVar(&options.helmManager, "helm", helm.KindSdk, "The helm implementation to install the charts: sdk uses the built-in helm library, cli the installed helm binary.")
+	flags.StringVar(&options.ingressController, "ingress-controller", "", "The ingress controller to install and use: "+strings.Join(ingress.Kinds(), ", ")+".\nDefault: the controller of the default ingress class in the cluster, otherwise "+ingress.DefaultKind+".")
 	flags.StringVar(&options.githubAccessToken, "ghAccessToken", "", "The github access token to access private repositories or avoid rate limiting.\nSee https://github.blog/2013-05-16-personal-api-tokens/ for information about how to create such a token.")
 
 	return cmd, options
@@ -64,6 +90,8 @@ func NewRootCmdOptions() *RootCommandOptions {
 	options := &RootCommandOptions{
 		installablePluginRegistry: plugins.NewInstallablePluginRegistry(),
 		startStopPluginRegistry:   plugins.NewStartStopPluginRegistry(),
+		lock:                      lock.New(),
+		bundle:                    bundle.New(),
 	}
 	return options
 }
@@ -79,20 +107,31 @@ func init() {
 	// initialize basic commands
 	var options *RootCommandOptions
 	rootCmd, options = NewRootCmd()
-	rootCmd.AddCommand(NewVersionCommand(), NewCompletionCmd())
+	rootCmd.AddCommand(NewVersionCommand(), NewCompletionCmd(), NewCertCommand())
 
 	// initializes plugins
 	initPlugins(options)
 
 	// initialize install, update and uninstall
 	rootCmd.AddCommand(
-		NewInstallCommand(options.installablePluginRegistry),
-		NewUpdateCommand(options.installablePluginRegistry),
-		NewUninstallCommand(options.installablePluginRegistry))
+		NewInstallCommand(options.installablePluginRegistry, options.lock, options.bundle, options.contextHandler, &options.values),
+		NewUpdateCommand(options.installablePluginRegistry, options.lock, options.charts, &options.values),
+		NewRollbackCommand(options.installablePluginRegistry, options.charts),
+		NewBundleCommand(options.installablePluginRegistry, options.lock, options.charts, options.releases),
+		NewUninstallCommand(options.installablePluginRegistry),
+		NewCaCommand(options.contextHandler))
 
 	// initializes run commands
 	runCmd := NewRunCommand(options.startStopPluginRegistry, options.contextNameSupplier)
+	addK8sDnsFilterFlags(runCmd, options)
+	addPortForwardFlags(runCmd, options)
+	addMountFlags(runCmd, options)
+	addTrustBundleFlags(runCmd, options)
 	rootCmd.AddCommand(runCmd)
+
+	urlsCmd := NewUrlsCommand(options.contextHandler, &options.portForwards, &options.portForwardDns)
+	addPortForwardFlags(urlsCmd, options)
+	rootCmd.AddCommand(urlsCmd)
 	for _, plugin := range options.startStopPluginRegistry.ListPlugins() {
 		if plugin.IsSingleRunnable() {
 			runCmd.AddCommand(NewRunSingleCommand(plugin))
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/lock"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -11,17 +12,29 @@ import (
 type runnerFunc func(plugin apis.InstallablePlugin, cmd *cobra.Command, args []string)
 
 // Create the install command for all registered plugins.
-func createInstallCommands(registry apis.InstallablePluginRegistry) []*cobra.Command {
+func createInstallCommands(registry apis.InstallablePluginRegistry, locks *lockOptions, bundles *bundleOptions, values *valuesOptions) []*cobra.Command {
 	runner := func(plugin apis.InstallablePlugin, cmd *cobra.Command, args []string) {
-		plugin.Install()
+		e := values.run(cmd, []apis.InstallablePlugin{plugin}, func() error {
+			return bundles.run(func() error {
+				return locks.run(lock.Honour, plugin.Install)
+			})
+		})
+		if e != nil {
+			logrus.Errorf("Unable to install %s: %s", plugin, e)
+		}
 	}
 	return createCommands("Installs the %s %s plugin.", runner, registry)
 }
 
 // Create the update command for all registered plugins.
-func CreateUpdateCommands(registry apis.InstallablePluginRegistry) []*cobra.Command {
+func CreateUpdateCommands(registry apis.InstallablePluginRegistry, locks *lockOptions, rollbacks *rollbackOptions, values *valuesOptions) []*cobra.Command {
 	runner := func(plugin apis.InstallablePlugin, cmd *cobra.Command, args []string) {
-		plugin.Update()
+		e := values.run(cmd, []apis.InstallablePlugin{plugin}, func() error {
+			return locks.run(lock.Refresh, func() { rollbacks.update(plugin) })
+		})
+		if e != nil {
+			logrus.Errorf("Unable to update %s: %s", plugin, e)
+		}
 	}
 	return createCommands("Updates the %s %s plugin.", runner, registry)
 }
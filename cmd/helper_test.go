@@ -12,7 +12,7 @@ import (
 
 func TestCreateInstallCommands(t *testing.T) {
 	plugin, registry := initTestRegistry(apis.LOCAL_TOOLS_INSTALL)
-	plugin.checkCommand(t, createInstallCommands(registry), "Installs the dummy local plugin.", true, false, false)
+	plugin.checkCommand(t, createInstallCommands(registry, &lockOptions{}, &bundleOptions{}, &valuesOptions{}), "Installs the dummy local plugin.", true, false, false)
 }
 
 func (p *DummyPlugin) checkCommand(t *testing.T, cmds []*cobra.Command, short string, installCalled bool, updateCalled bool, uninstallCalled bool) {
@@ -29,7 +29,7 @@ func (p *DummyPlugin) checkCommand(t *testing.T, cmds []*cobra.Command, short st
 
 func TestCreateUpdateCommands(t *testing.T) {
 	plugin, registry := initTestRegistry(apis.LOCAL_TOOLS_INSTALL)
-	plugin.checkCommand(t, CreateUpdateCommands(registry), "Updates the dummy local plugin.", false, true, false)
+	plugin.checkCommand(t, CreateUpdateCommands(registry, &lockOptions{}, &rollbackOptions{}, &valuesOptions{}), "Updates the dummy local plugin.", false, true, false)
 }
 
 func TestCreateUninstallCommands(t *testing.T) {
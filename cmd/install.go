@@ -5,44 +5,71 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/bundle"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/lock"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
 )
 
 type InstallOptions struct {
 	registry            apis.InstallablePluginRegistry
 	includeLocalPlugins bool
+	locks               lockOptions
+	bundles             bundleOptions
+	values              valuesOptions
 }
 
-func NewInstallOptions(registry apis.InstallablePluginRegistry) *InstallOptions {
+func NewInstallOptions(registry apis.InstallablePluginRegistry, l *lock.Lock, b *bundle.Bundle, handler kubernetes.ContextHandler, overrides *helm.Overrides) *InstallOptions {
 	return &InstallOptions{
 		registry: registry,
+		locks:    lockOptions{lock: l},
+		bundles:  bundleOptions{bundle: b, handler: handler},
+		values:   valuesOptions{registry: registry, overrides: overrides},
 	}
 }
 
-func NewInstallCommand(registry apis.InstallablePluginRegistry) *cobra.Command {
-	options := NewInstallOptions(registry)
+func NewInstallCommand(registry apis.InstallablePluginRegistry, l *lock.Lock, b *bundle.Bundle, handler kubernetes.ContextHandler, overrides *helm.Overrides) *cobra.Command {
+	options := NewInstallOptions(registry, l, b, handler, overrides)
 
 	command := &cobra.Command{
 		Use:   "install",
 		Short: "Installs the available cluster plugins.",
 		Long: "The install command installs at least all cluster plugins. If you add the -l or --installLocal flag it " +
-			"will also install the local plugins.",
-		Run: options.Run,
+			"will also install the local plugins. The chart versions and the CoreDNS release are taken from the lock " +
+			"file. Versions which are not locked yet are added to it. With --bundle everything is installed from a bundle " +
+			"created by \"minikube-support bundle create\". The values of the helm plugins can be overridden with " +
+			"--values and --set. Use --show-values to show the resulting values without installing anything.",
+		RunE: options.Run,
 	}
 	flags := command.Flags()
 	flags.BoolVarP(&options.includeLocalPlugins, "installLocal", "l", false, "Also install the local plugins.")
+	options.locks.addFlags(command)
+	options.bundles.addFlags(command)
+	options.values.addFlags(command)
+	options.values.addShowFlag(command)
 
-	command.AddCommand(createInstallCommands(options.registry)...)
+	command.AddCommand(createInstallCommands(options.registry, &options.locks, &options.bundles, &options.values)...)
 	return command
 
 }
 
-func (i *InstallOptions) Run(cmd *cobra.Command, args []string) {
+func (i *InstallOptions) Run(cmd *cobra.Command, args []string) error {
+	var plugins []apis.InstallablePlugin
 	for _, plugin := range i.registry.ListPlugins() {
 		if !i.includeLocalPlugins && apis.IsLocalPlugin(plugin) {
 			continue
 		}
-
-		logrus.Info("Install plugin:", plugin)
-		plugin.Install()
+		plugins = append(plugins, plugin)
 	}
+
+	return i.values.run(cmd, plugins, func() error {
+		return i.bundles.run(func() error {
+			return i.locks.run(lock.Honour, func() {
+				for _, plugin := range plugins {
+					logrus.Info("Install plugin:", plugin)
+					plugin.Install()
+				}
+			})
+		})
+	})
 }
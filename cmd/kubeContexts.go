@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/plugins/coredns"
+)
+
+// kubeContexts creates and caches everything that is shared between the plugins
+// of one kube context, if `run` watches multiple contexts at the same time.
+type kubeContexts struct {
+	options   *RootCommandOptions
+	manager   coredns.Manager
+	resources map[string]*kubeContextResources
+	mutex     sync.Mutex
+}
+
+// kubeContextResources are the shared resources of a single kube context.
+type kubeContextResources struct {
+	handler   kubernetes.ContextHandler
+	manager   coredns.Manager
+	informers *kubernetes.Informers
+}
+
+func newKubeContexts(options *RootCommandOptions, manager coredns.Manager) *kubeContexts {
+	return &kubeContexts{
+		options:   options,
+		manager:   manager,
+		resources: map[string]*kubeContextResources{},
+		mutex:     sync.Mutex{},
+	}
+}
+
+// list returns the configured contexts.
+func (c *kubeContexts) list() []kubernetes.KubeContext {
+	return c.options.kubeContexts
+}
+
+// contextNames returns the names of all configured contexts or the one of the default handler.
+func (c *kubeContexts) contextNames(defaultHandler kubernetes.ContextHandler) ContextNameSupplier {
+	return func() string {
+		contexts := c.list()
+		if len(contexts) == 0 {
+			return defaultHandler.GetContextName()
+		}
+		var names []string
+		for _, context := range contexts {
+			names = append(names, context.Name)
+		}
+		return strings.Join(names, ", ")
+	}
+}
+
+// get returns the resources of the given context. They are created on the first call.
+func (c *kubeContexts) get(context kubernetes.KubeContext) *kubeContextResources {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if resources, ok := c.resources[context.Name]; ok {
+		return resources
+	}
+	name := context.Name
+	resources := &kubeContextResources{
+		handler:   kubernetes.NewContextHandler(&c.options.kubeConfig, &name),
+		manager:   coredns.NewZoneManager(c.manager, context.Zone),
+		informers: kubernetes.NewInformers(kubernetes.DefaultResyncPeriod),
+	}
+	c.resources[context.Name] = resources
+	return resources
+}
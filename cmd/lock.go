@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/qaware/minikube-support/pkg/lock"
+)
+
+// lockOptions contains the flags to install and update the plugins using the lock file.
+type lockOptions struct {
+	lock     *lock.Lock
+	lockFile string
+	frozen   bool
+}
+
+// addFlags adds the lock flags to the given command.
+func (o *lockOptions) addFlags(command *cobra.Command) {
+	flags := command.PersistentFlags()
+	flags.StringVar(&o.lockFile, "lock-file", lock.FileName, "The lock file which records the installed chart versions and the downloaded CoreDNS release.")
+	flags.BoolVar(&o.frozen, "frozen", false, "Fail if a version is not locked or differs from the locked one instead of updating the lock file.")
+}
+
+// run runs the function with the loaded lock file and saves the resolved versions afterwards.
+func (o *lockOptions) run(mode lock.Mode, f func()) error {
+	if o.lock == nil {
+		f()
+		return nil
+	}
+	if e := o.lock.Load(o.lockFile, mode, o.frozen); e != nil {
+		return e
+	}
+	f()
+	if e := o.lock.Err(); e != nil {
+		return fmt.Errorf("versions differ from the lock file %s: %s", o.lockFile, e)
+	}
+	return o.lock.Save()
+}
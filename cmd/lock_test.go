@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qaware/minikube-support/pkg/lock"
+)
+
+func Test_lockOptions_run(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     lock.Mode
+		frozen   bool
+		wantErr  bool
+		wantFile bool
+	}{
+		{"install", lock.Honour, false, false, true},
+		{"update", lock.Refresh, false, false, true},
+		{"frozen", lock.Honour, true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), lock.FileName)
+			if tt.frozen {
+				require.NoError(t, os.WriteFile(path, []byte("charts: {}\n"), 0644))
+			}
+			o := &lockOptions{lock: lock.New(), lockFile: path, frozen: tt.frozen}
+
+			err := o.run(tt.mode, func() {
+				_, _ = o.lock.Release("coredns/coredns", func() (string, error) { return "v1.11.1", nil })
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			content, _ := os.ReadFile(path)
+			assert.Equal(t, tt.wantFile, strings.Contains(string(content), "coredns/coredns"))
+		})
+	}
+}
+
+func Test_lockOptions_run_withoutLock(t *testing.T) {
+	called := false
+	assert.NoError(t, (&lockOptions{}).run(lock.Honour, func() { called = true }))
+	assert.True(t, called)
+}
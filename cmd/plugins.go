@@ -3,22 +3,32 @@ package cmd
 import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/bundle"
 	"github.com/qaware/minikube-support/pkg/github"
 	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/lock"
 	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
 	"github.com/qaware/minikube-support/pkg/packagemanager/os"
 	"github.com/qaware/minikube-support/pkg/plugins"
+	"github.com/qaware/minikube-support/pkg/plugins/certificates"
 	"github.com/qaware/minikube-support/pkg/plugins/certmanager"
+	"github.com/qaware/minikube-support/pkg/plugins/clusterdns"
 	"github.com/qaware/minikube-support/pkg/plugins/coredns"
 	"github.com/qaware/minikube-support/pkg/plugins/ingress"
 	"github.com/qaware/minikube-support/pkg/plugins/k8sdns"
 	"github.com/qaware/minikube-support/pkg/plugins/logs"
 	"github.com/qaware/minikube-support/pkg/plugins/minikube"
 	"github.com/qaware/minikube-support/pkg/plugins/mkcert"
+	"github.com/qaware/minikube-support/pkg/plugins/portforward"
+	"github.com/qaware/minikube-support/pkg/plugins/registry"
+	"github.com/qaware/minikube-support/pkg/plugins/trustbundle"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Initializes all active plugins and register them in the two (installable and start stop) plugin registries.
+// Options set by flags are passed as pointers and read on use, because the flags are parsed afterwards.
 func initPlugins(options *RootCommandOptions) {
 	var errors *multierror.Error
 	logPlugin := logs.NewLogsPlugin(logrus.StandardLogger())
@@ -26,40 +36,112 @@ func initPlugins(options *RootCommandOptions) {
 	os.RegisterOsPackage()
 
 	handler := kubernetes.NewContextHandler(&options.kubeConfig, &options.contextName)
-	options.contextNameSupplier = handler.GetContextName
-	helmManager, e := helm.NewHelmManager(handler)
-	errors = multierror.Append(errors, e)
+	options.contextHandler = handler
+	helmManager := lock.NewHelmManager(bundle.NewHelmManager(helm.NewManager(&options.helmManager, handler), options.bundle), options.lock)
+	options.charts = helmManager
 
 	coreDns := coredns.NewGrpcPlugin(corednsPrefix)
 	manager, e := coredns.NewManager(coreDns)
 	errors = multierror.Append(errors, e)
 
-	k8sIngresses := k8sdns.NewK8sDns(handler, manager, k8sdns.AccessTypeIngress)
-	k8sServices := k8sdns.NewK8sDns(handler, manager, k8sdns.AccessTypeService)
+	informers := kubernetes.NewInformers(kubernetes.DefaultResyncPeriod)
+	configWatcher := kubernetes.NewConfigWatcher(&options.kubeConfig)
+	reloading := func(plugin apis.StartStopPlugin, handler kubernetes.ContextHandler) apis.StartStopPlugin {
+		return plugins.NewReloadingPlugin(plugin, configWatcher, handler)
+	}
+	k8sIngresses := reloading(k8sdns.NewK8sDns(handler, manager, k8sdns.AccessTypeIngress, &options.ingressFilter, informers), handler)
+	k8sServices := reloading(k8sdns.NewK8sDns(handler, manager, k8sdns.AccessTypeService, &options.serviceFilter, informers), handler)
+
+	contexts := newKubeContexts(options, manager)
+	options.contextNameSupplier = contexts.contextNames(handler)
+	options.AddPreRunInitFunction(func(o *RootCommandOptions) error {
+		var e error
+		o.kubeContexts, e = kubernetes.ParseKubeContexts(o.contexts)
+		return e
+	})
+	k8sIngresses = plugins.NewMultiContextPlugin(k8sIngresses, contexts.list, func(c kubernetes.KubeContext) apis.StartStopPlugin {
+		r := contexts.get(c)
+		return reloading(k8sdns.NewK8sDns(r.handler, r.manager, k8sdns.AccessTypeIngress, &options.ingressFilter, r.informers), r.handler)
+	})
+	k8sServices = plugins.NewMultiContextPlugin(k8sServices, contexts.list, func(c kubernetes.KubeContext) apis.StartStopPlugin {
+		r := contexts.get(c)
+		return reloading(k8sdns.NewK8sDns(r.handler, r.manager, k8sdns.AccessTypeService, &options.serviceFilter, r.informers), r.handler)
+	})
+	tunnel := plugins.NewMultiContextPlugin(reloading(minikube.NewTunnel(handler), handler), contexts.list, func(c kubernetes.KubeContext) apis.StartStopPlugin {
+		r := contexts.get(c)
+		return reloading(minikube.NewTunnel(r.handler), r.handler)
+	})
+	ipPlugin := plugins.NewMultiContextPlugin(reloading(minikube.NewIpPlugin(manager, handler), handler), contexts.list, func(c kubernetes.KubeContext) apis.StartStopPlugin {
+		r := contexts.get(c)
+		return reloading(minikube.NewIpPlugin(r.manager, r.handler), r.handler)
+	})
 
 	ghClient := github.NewClient()
 	options.AddPreRunInitFunction(func(o *RootCommandOptions) error {
 		ghClient.SetApiToken(o.githubAccessToken)
 		return nil
 	})
+	releases := lock.NewGithubClient(bundle.NewGithubClient(ghClient, options.bundle), options.lock)
+	options.releases = releases
 
 	coreDnsIngressPlugin, _ := plugins.NewCombinedPlugin("coredns-ingress", []apis.StartStopPlugin{coreDns, k8sIngresses, k8sServices}, true)
-	certManager := certmanager.NewCertManager(helmManager, handler, ghClient)
+	ingressSelector := ingress.NewSelector(&options.ingressController, handler)
+	certManager := certmanager.NewCertManager(helmManager, handler, ghClient, &options.values)
 
 	options.installablePluginRegistry.AddPlugins(
 		mkcert.CreateMkcertInstallerPlugin(),
-		ingress.NewControllerInstaller(helmManager),
+		ingress.NewControllerInstaller(helmManager, handler, ingressSelector, &options.values),
 		certManager,
-		coredns.NewInstaller(corednsPrefix, ghClient),
+		coredns.NewInstaller(corednsPrefix, releases, handler),
+		registry.NewRegistry(helmManager, handler, ingressSelector, &options.values),
+		clusterdns.NewClusterDns(handler, ingressSelector),
 	)
 
 	options.startStopPluginRegistry.AddPlugins(
 		logPlugin,
-		minikube.NewTunnel(handler),
+		tunnel,
 		coreDnsIngressPlugin,
-		minikube.NewIpPlugin(manager, handler),
+		ipPlugin,
+		reloading(minikube.NewMount(handler, &options.mounts), handler),
+		reloading(portforward.NewPortForwardPlugin(handler, manager, &options.portForwards, &options.portForwardDns), handler),
+		reloading(trustbundle.NewTrustBundle(handler, informers, &options.trustBundleNamespaces), handler),
+		reloading(certificates.NewCertificates(handler), handler),
+		reloading(registry.NewNodes(handler, ingressSelector), handler),
 	)
 	if errors.Len() != 0 {
 		logrus.Errorf("unable to initialize all plugins: %s", errors)
 	}
 }
+
+// addK8sDnsFilterFlags adds the flags to select the watched contexts and to filter the watched ingresses and
+// services to the given run command.
+func addK8sDnsFilterFlags(runCmd *cobra.Command, options *RootCommandOptions) {
+	flags := runCmd.PersistentFlags()
+	flags.StringSliceVar(&options.contexts, "contexts", nil, "Watch the given kube contexts at the same time. Records of each context are served in the zone\n<context>.minikube or <label>.minikube if defined as <context>=<label>.")
+	addFilterFlags(flags, string(k8sdns.AccessTypeIngress), "ingresses", &options.ingressFilter)
+	addFilterFlags(flags, string(k8sdns.AccessTypeService), "services", &options.serviceFilter)
+}
+
+// addPortForwardFlags adds the flags to define the port forwards to the given run command.
+func addPortForwardFlags(runCmd *cobra.Command, options *RootCommandOptions) {
+	flags := runCmd.PersistentFlags()
+	flags.StringArrayVar(&options.portForwards, "port-forward", nil, "Forward local ports to a service or pod. Format: \"<namespace>/svc/<name> [local:]remote...\"\nor \"<namespace>/pod/<selector> [local:]remote...\". Can be given multiple times.")
+	flags.BoolVar(&options.portForwardDns, "port-forward-dns", false, "Register <svc>.<ns>.pf.minikube for every forwarded service pointing to 127.0.0.1.")
+}
+
+// addMountFlags adds the flag to define the mounted folders to the given run command.
+func addMountFlags(runCmd *cobra.Command, options *RootCommandOptions) {
+	runCmd.PersistentFlags().StringArrayVar(&options.mounts, "mount", nil, "Mount a host folder into minikube. Format: \"<host path>:<guest path>\". Can be given multiple times.")
+}
+
+// addTrustBundleFlags adds the flag to select the namespaces which get the CA config map to the given run command.
+func addTrustBundleFlags(runCmd *cobra.Command, options *RootCommandOptions) {
+	runCmd.PersistentFlags().StringSliceVar(&options.trustBundleNamespaces, "trust-bundle-namespaces", nil, "Only publish the CA config map "+trustbundle.ConfigMapName+" into the given namespaces. Default: all namespaces.")
+}
+
+func addFilterFlags(flags *pflag.FlagSet, prefix string, objects string, filter *k8sdns.Filter) {
+	flags.StringSliceVar(&filter.IncludeNamespaces, prefix+"-namespaces", nil, "Only watch "+objects+" in the given namespaces.")
+	flags.StringSliceVar(&filter.ExcludeNamespaces, prefix+"-exclude-namespaces", nil, "Do not watch "+objects+" in the given namespaces.")
+	flags.StringVar(&filter.LabelSelector, prefix+"-selector", "", "Label selector to filter the watched "+objects+".")
+	flags.StringVar(&filter.FieldSelector, prefix+"-field-selector", "", "Field selector to filter the watched "+objects+".")
+}
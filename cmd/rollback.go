@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+)
+
+// rollbackOptions contains the flag to roll back the helm release of a plugin which is not healthy after its update.
+type rollbackOptions struct {
+	manager   helm.Manager
+	onFailure bool
+}
+
+// addFlags adds the rollback flag to the given command.
+func (o *rollbackOptions) addFlags(command *cobra.Command) {
+	command.PersistentFlags().BoolVar(&o.onFailure, "rollback-on-failure", false, "Roll the helm release of a plugin back to the previous revision if it is not healthy after the update.")
+}
+
+// update updates the plugin. If the plugin installs a helm release and is not healthy after the update, its release
+// is rolled back to the revision before the update.
+func (o *rollbackOptions) update(plugin apis.InstallablePlugin) {
+	helmPlugin, ok := plugin.(apis.HelmPlugin)
+	if !o.onFailure || !ok || o.manager == nil {
+		plugin.Update()
+		return
+	}
+
+	release, namespace := helmPlugin.Release()
+	previous, e := o.manager.Status(release, namespace)
+	if e != nil {
+		logrus.Debugf("Can not read helm release %s/%s before the update: %s", namespace, release, e)
+	}
+	plugin.Update()
+
+	e = helmPlugin.Ready()
+	if e == nil {
+		return
+	}
+	if previous == nil {
+		logrus.Errorf("%s is not healthy after the update and there is no revision to roll back to: %s", plugin, e)
+		return
+	}
+	if current, se := o.manager.Status(release, namespace); se == nil && current.Revision == previous.Revision {
+		logrus.Errorf("%s is not healthy, but the update did not change its helm release: %s", plugin, e)
+		return
+	}
+
+	logrus.Warnf("%s is not healthy after the update: %s. Rolling back to revision %d.", plugin, e, previous.Revision)
+	if e := o.manager.Rollback(release, namespace, previous.Revision); e != nil {
+		logrus.Errorf("Can not roll back %s: %s", plugin, e)
+	}
+}
+
+// RollbackOptions contains the options to roll back the helm release of a plugin.
+type RollbackOptions struct {
+	registry apis.InstallablePluginRegistry
+	manager  helm.Manager
+	history  bool
+}
+
+// NewRollbackCommand creates the command to roll back the helm release of a plugin.
+func NewRollbackCommand(registry apis.InstallablePluginRegistry, manager helm.Manager) *cobra.Command {
+	options := &RollbackOptions{registry: registry, manager: manager}
+
+	command := &cobra.Command{
+		Use:   "rollback <plugin> [revision]",
+		Short: "Rolls the helm release of a plugin back.",
+		Long: "The rollback command rolls the helm release of a cluster plugin like ingress-controller or certManager " +
+			"back to the given revision. Without revision it is rolled back to the previous one. Use --history to list " +
+			"the revisions of the release.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: options.Run,
+	}
+	command.Flags().BoolVar(&options.history, "history", false, "Only list the revisions of the helm release.")
+	return command
+}
+
+func (o *RollbackOptions) Run(cmd *cobra.Command, args []string) error {
+	plugin, e := o.registry.FindPlugin(args[0])
+	if e != nil {
+		return e
+	}
+	helmPlugin, ok := plugin.(apis.HelmPlugin)
+	if !ok {
+		return fmt.Errorf("plugin %s does not install a helm release", plugin)
+	}
+	release, namespace := helmPlugin.Release()
+
+	if o.history {
+		history, e := o.manager.History(release, namespace)
+		if e != nil {
+			return e
+		}
+		writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(writer, "REVISION\tUPDATED\tSTATUS\tCHART\tAPP VERSION\tDESCRIPTION")
+		for _, revision := range history {
+			_, _ = fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\n", revision.Revision, revision.Updated.Format(time.RFC3339), revision.Status, revision.Chart, revision.AppVersion, revision.Description)
+		}
+		return writer.Flush()
+	}
+
+	revision := 0
+	if len(args) == 2 {
+		if revision, e = strconv.Atoi(args[1]); e != nil || revision <= 0 {
+			return fmt.Errorf("invalid revision %s", args[1])
+		}
+	}
+	if e := o.manager.Rollback(release, namespace, revision); e != nil {
+		return e
+	}
+	status, e := o.manager.Status(release, namespace)
+	if e != nil {
+		return e
+	}
+	logrus.Infof("%s is at revision %d with chart %s (%s).", plugin, status.Revision, status.Chart, status.Status)
+	return nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm/fake"
+	"github.com/qaware/minikube-support/pkg/plugins"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+type dummyHelmPlugin struct {
+	DummyPlugin
+	ready error
+}
+
+func (*dummyHelmPlugin) Release() (string, string) {
+	return "dummy", "mks"
+}
+
+func (p *dummyHelmPlugin) Ready() error {
+	return p.ready
+}
+
+func (*dummyHelmPlugin) Values() (map[string]interface{}, error) {
+	return map[string]interface{}{"replicaCount": int64(1)}, nil
+}
+
+func Test_rollbackOptions_update(t *testing.T) {
+	tests := []struct {
+		name         string
+		onFailure    bool
+		ready        error
+		previous     *helm.Revision
+		current      *helm.Revision
+		wantRollback bool
+		wantLog      string
+	}{
+		{"disabled", false, errors.New("not ready"), nil, nil, false, ""},
+		{"healthy", true, nil, &helm.Revision{Revision: 1}, nil, false, ""},
+		{"unhealthy", true, errors.New("not ready"), &helm.Revision{Revision: 1}, &helm.Revision{Revision: 2}, true, "dummy is not healthy after the update: not ready. Rolling back to revision 1."},
+		{"unhealthy first install", true, errors.New("not ready"), nil, nil, false, "dummy is not healthy after the update and there is no revision to roll back to: not ready"},
+		{"unhealthy without upgrade", true, errors.New("not ready"), &helm.Revision{Revision: 1}, &helm.Revision{Revision: 1}, false, "dummy is not healthy, but the update did not change its helm release: not ready"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hook := test.NewGlobal()
+			manager := fake.NewMockManager(ctrl)
+			plugin := &dummyHelmPlugin{ready: tt.ready}
+			if tt.onFailure {
+				previous := manager.EXPECT().Status("dummy", "mks")
+				if tt.previous != nil {
+					previous.Return(tt.previous, nil)
+				} else {
+					previous.Return(nil, errors.New("release: not found"))
+				}
+				if tt.ready != nil && tt.previous != nil {
+					manager.EXPECT().Status("dummy", "mks").Return(tt.current, nil).After(previous)
+				}
+			}
+			if tt.wantRollback {
+				manager.EXPECT().Rollback("dummy", "mks", tt.previous.Revision)
+			}
+
+			o := &rollbackOptions{manager: manager, onFailure: tt.onFailure}
+			o.update(plugin)
+
+			assert.True(t, plugin.updateRun)
+			if tt.wantLog != "" {
+				testutils.CheckLogEntry(t, hook, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestRollbackOptions_Run(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		history      bool
+		wantRevision int
+		wantOutput   string
+		wantErr      bool
+	}{
+		{"previous", []string{"dummy"}, false, 0, "", false},
+		{"revision", []string{"dummy", "3"}, false, 3, "", false},
+		{"invalid revision", []string{"dummy", "latest"}, false, -1, "", true},
+		{"history", []string{"dummy"}, true, -1, "REVISION  UPDATED               STATUS    CHART        APP VERSION  DESCRIPTION\n" +
+			"1         2024-05-02T10:15:30Z  deployed  dummy-1.0.0  1.0          Install complete\n", false},
+		{"no helm plugin", []string{"other"}, false, -1, "", true},
+		{"unknown plugin", []string{"unknown"}, false, -1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			manager := fake.NewMockManager(ctrl)
+			registry := plugins.NewInstallablePluginRegistry()
+			registry.AddPlugins(&dummyHelmPlugin{}, &DummyPlugin{name: "other"})
+			if tt.wantRevision >= 0 {
+				manager.EXPECT().Rollback("dummy", "mks", tt.wantRevision)
+				manager.EXPECT().Status("dummy", "mks").Return(&helm.Revision{Revision: 4, Chart: "dummy-1.0.0", Status: "deployed"}, nil)
+			}
+			if tt.history {
+				manager.EXPECT().History("dummy", "mks").Return([]helm.Revision{
+					{Revision: 1, Updated: time.Date(2024, 5, 2, 10, 15, 30, 0, time.UTC), Status: "deployed", Chart: "dummy-1.0.0", AppVersion: "1.0", Description: "Install complete"},
+				}, nil)
+			}
+
+			command := NewRollbackCommand(registry, manager)
+			out := &bytes.Buffer{}
+			command.SetOut(out)
+			o := &RollbackOptions{registry: registry, manager: manager, history: tt.history}
+
+			e := o.Run(command, tt.args)
+
+			assert.Equal(t, tt.wantErr, e != nil)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
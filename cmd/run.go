@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -22,7 +23,7 @@ import (
 	"github.com/qaware/minikube-support/pkg/utils"
 )
 
-var boxConfig = [][]string{{"k8sdns-ingress", "k8sdns-service"}, {"coredns-grpc", "minikube-tunnel"}, {"logs"}}
+var boxConfig = [][]string{{"k8sdns-ingress", "k8sdns-service"}, {"coredns-grpc", "minikube-tunnel"}, {"certificates", "logs"}, {"minikube-ip", "port-forward"}, {"minikube-mount", "trust-bundle"}}
 var newGui = gocui.NewGui
 
 type RunOptions struct {
@@ -67,7 +68,7 @@ func (i *RunOptions) Run(_ *cobra.Command, _ []string) {
 	}
 
 	go i.startPlugins()
-	i.handleSignals()
+	signals := i.handleSignals()
 	gui, e := newGui(gocui.Output256, true)
 	if e != nil {
 		logrus.Errorf("Can not start gui: %s", e)
@@ -75,6 +76,7 @@ func (i *RunOptions) Run(_ *cobra.Command, _ []string) {
 	}
 	defer gui.Close()
 	i.gui = gui
+	go i.terminateOnSignal(signals)
 	i.gui.SetManager(i)
 	if e = i.registerKeybindings(gui); e != nil {
 		logrus.Errorf("Can not register keybindings: %s", e)
@@ -119,22 +121,60 @@ func (i *RunOptions) receiveMessages() {
 			return
 		}
 		i.lastMessagesLock.Lock()
-		i.lastMessages[message.Box] = message
+		i.lastMessages[messageKey(message)] = message
+		content := boxContent(i.lastMessages, message.Box)
 		i.lastMessagesLock.Unlock()
 
-		i.gui.UpdateAsync(updateBox(message))
+		i.gui.UpdateAsync(updateBox(message.Box, content))
 	}
 }
 
-func updateBox(message *apis.MonitoringMessage) func(gui *gocui.Gui) error {
-	msg := apis.CloneMonitoringMessage(message)
+// messageKey returns the key of the message in the last messages. Messages of different
+// contexts are stored separately so that they can be shown together in the same box.
+func messageKey(message *apis.MonitoringMessage) string {
+	if message.Context == "" {
+		return message.Box
+	}
+	return message.Box + "@" + message.Context
+}
+
+// boxContent renders the last messages of the given box. If the messages belong to
+// different contexts, they are grouped by the context name.
+func boxContent(lastMessages map[string]*apis.MonitoringMessage, box string) string {
+	var messages []*apis.MonitoringMessage
+	for _, message := range lastMessages {
+		if message.Box == box {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) == 1 && messages[0].Context == "" {
+		return messages[0].Message
+	}
+
+	sort.Slice(messages, func(a, b int) bool {
+		return messages[a].Context < messages[b].Context
+	})
+	var groups []string
+	for _, message := range messages {
+		groups = append(groups, fmt.Sprintf("[%s]\n%s", message.Context, strings.TrimRight(message.Message, "\n")))
+	}
+	return strings.Join(groups, "\n\n")
+}
+
+// updateBox replaces the content of the box. Messages for boxes without view are dropped, so that a plugin
+// whose box is missing in the box config can not stop the gui.
+func updateBox(box string, content string) func(gui *gocui.Gui) error {
 	return func(gui *gocui.Gui) error {
-		view, e := gui.View(msg.Box)
+		view, e := gui.View(box)
+		if errors.Is(e, gocui.ErrUnknownView) {
+			logrus.Debugf("Dropping message for unknown box %s", box)
+			return nil
+		}
 		if e != nil {
 			return e
 		}
 		view.Clear()
-		view.WriteString(padLeft(msg.Message, 1))
+		view.WriteString(padLeft(content, 1))
 		return nil
 	}
 }
@@ -144,15 +184,20 @@ func padLeft(message string, spaces uint8) string {
 	return strings.ReplaceAll(padding+message, "\n", "\n"+padding)
 }
 
-func (i *RunOptions) handleSignals() {
+func (i *RunOptions) handleSignals() chan os.Signal {
 	signalsChannel := make(chan os.Signal, 1)
 	signal.Notify(signalsChannel, syscall.SIGINT, syscall.SIGTERM)
+	return signalsChannel
+}
 
-	go func() {
-		sig := <-signalsChannel
-		logrus.Infof("Got signal %s. Terminating all plugins", sig)
-		i.stopPlugins()
-	}()
+// terminateOnSignal stops all plugins and quits the gui as soon as a signal is received.
+func (i *RunOptions) terminateOnSignal(signals chan os.Signal) {
+	sig := <-signals
+	logrus.Infof("Got signal %s. Terminating all plugins", sig)
+	i.stopPlugins()
+	i.gui.UpdateAsync(func(*gocui.Gui) error {
+		return gocui.ErrQuit
+	})
 }
 
 func (i *RunOptions) stopPlugins() {
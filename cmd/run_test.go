@@ -3,12 +3,14 @@ package cmd
 import (
 	"os/exec"
 	"reflect"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
 	"time"
 
 	"github.com/awesome-gocui/gocui"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/spf13/cobra"
 
 	"github.com/qaware/minikube-support/pkg/sh"
@@ -21,8 +23,11 @@ import (
 
 func TestRunOptions_Run(t *testing.T) {
 	sh.ExecCommand = testutils.FakeExecCommand
+	guis := make(chan *gocui.Gui, 1)
 	newGui = func(mode gocui.OutputMode, supportOverlaps bool) (*gocui.Gui, error) {
-		return gocui.NewGui(gocui.OutputSimulator, supportOverlaps)
+		gui, e := gocui.NewGui(gocui.OutputSimulator, supportOverlaps)
+		guis <- gui
+		return gui, e
 	}
 	defer func() {
 		sh.ExecCommand = exec.Command
@@ -52,6 +57,14 @@ func TestRunOptions_Run(t *testing.T) {
 			[]string{"dummy1"},
 			[]apis.MonitoringMessage{{Box: "dummy1", Message: "Starting..."}},
 		},
+		{
+			"registered box",
+			[]apis.StartStopPlugin{
+				&DummyPlugin{name: "port-forward"},
+			},
+			[]string{"port-forward"},
+			[]apis.MonitoringMessage{{Box: "port-forward", Message: "Starting..."}},
+		},
 		{
 			"one stop fails",
 			[]apis.StartStopPlugin{
@@ -67,6 +80,7 @@ func TestRunOptions_Run(t *testing.T) {
 				{Command: "sudo", Args: []string{"echo", ""}, ResponseStatus: 0},
 				{Command: "which", Args: []string{"sudo"}, ResponseStatus: 0},
 			})
+			hook := test.NewGlobal()
 			startedChannel, cntPlugins := injectStartedChannel(tt.plugins)
 			options := &RunOptions{
 				plugins:          tt.plugins,
@@ -81,6 +95,7 @@ func TestRunOptions_Run(t *testing.T) {
 				terminated <- true
 			}()
 			waitForStarted(startedChannel, cntPlugins)
+			waitForRendered(t, <-guis, options, tt.activePlugins, "Starting...")
 			_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
 
 			select {
@@ -89,6 +104,9 @@ func TestRunOptions_Run(t *testing.T) {
 				messages := messagesValues(options.lastMessages)
 				options.lastMessagesLock.RUnlock()
 				assert.Equal(t, tt.lastMessages, messages)
+				for _, entry := range hook.AllEntries() {
+					assert.NotContains(t, entry.Message, "unknown view")
+				}
 
 			case <-time.After(1 * time.Second):
 				assert.Fail(t, "terminated message not received")
@@ -97,6 +115,49 @@ func TestRunOptions_Run(t *testing.T) {
 	}
 }
 
+// waitForRendered waits until the gui shows the content in all given boxes. Boxes without view only have to be
+// received.
+func waitForRendered(t *testing.T, gui *gocui.Gui, options *RunOptions, boxes []string, content string) {
+	assert.Eventually(t, func() bool {
+		rendered := make(chan bool, 1)
+		gui.UpdateAsync(func(gui *gocui.Gui) error {
+			options.lastMessagesLock.RLock()
+			defer options.lastMessagesLock.RUnlock()
+			for _, box := range boxes {
+				view, e := gui.View(box)
+				if e != nil && options.lastMessages[box] == nil {
+					rendered <- false
+					return nil
+				}
+				if e == nil && !strings.Contains(view.Buffer(), content) {
+					rendered <- false
+					return nil
+				}
+			}
+			rendered <- true
+			return nil
+		})
+		return <-rendered
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_updateBox(t *testing.T) {
+	gui, e := gocui.NewGui(gocui.OutputSimulator, true)
+	assert.NoError(t, e)
+	defer gui.Close()
+	assert.NoError(t, (&RunOptions{}).Layout(gui))
+
+	for _, line := range boxConfig {
+		for _, box := range line {
+			assert.NoError(t, updateBox(box, "content")(gui), box)
+			view, e := gui.View(box)
+			assert.NoError(t, e)
+			assert.Equal(t, " content", view.Buffer(), box)
+		}
+	}
+	assert.NoError(t, updateBox("unknown", "content")(gui), "unknown boxes must not stop the gui")
+}
+
 func TestRunOptions_startPlugins(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -218,3 +279,29 @@ func waitForStarted(startedChannel chan bool, countPlugins int) {
 		}
 	}
 }
+
+func Test_boxContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []*apis.MonitoringMessage
+		box      string
+		want     string
+	}{
+		{"single", []*apis.MonitoringMessage{{Box: "a", Message: "msg"}, {Box: "b", Message: "other"}}, "a", "msg"},
+		{
+			"contexts",
+			[]*apis.MonitoringMessage{{Box: "a", Message: "infra\n", Context: "infra"}, {Box: "a", Message: "app", Context: "app"}, {Box: "b", Message: "other"}},
+			"a",
+			"[app]\napp\n\n[infra]\ninfra",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lastMessages := map[string]*apis.MonitoringMessage{}
+			for _, message := range tt.messages {
+				lastMessages[messageKey(message)] = message
+			}
+			assert.Equal(t, tt.want, boxContent(lastMessages, tt.box))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/lock"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -9,35 +11,51 @@ import (
 type UpdateOptions struct {
 	registry            apis.InstallablePluginRegistry
 	includeLocalPlugins bool
+	locks               lockOptions
+	rollbacks           rollbackOptions
+	values              valuesOptions
 }
 
-func NewUpdateOptions(registry apis.InstallablePluginRegistry) *UpdateOptions {
+func NewUpdateOptions(registry apis.InstallablePluginRegistry, l *lock.Lock, manager helm.Manager, overrides *helm.Overrides) *UpdateOptions {
 	return &UpdateOptions{
-		registry: registry,
+		registry:  registry,
+		locks:     lockOptions{lock: l},
+		rollbacks: rollbackOptions{manager: manager},
+		values:    valuesOptions{registry: registry, overrides: overrides},
 	}
 }
 
-func NewUpdateCommand(registry apis.InstallablePluginRegistry) *cobra.Command {
-	options := NewUpdateOptions(registry)
+func NewUpdateCommand(registry apis.InstallablePluginRegistry, l *lock.Lock, manager helm.Manager, overrides *helm.Overrides) *cobra.Command {
+	options := NewUpdateOptions(registry, l, manager, overrides)
 
 	command := &cobra.Command{
 		Use:   "update",
 		Short: "Updates the cluster plugins.",
 		Long: "The update command updates at least all cluster plugins. If you add the -l or --updateLocal flag " +
-			"it will also update the local plugins.",
-		Run: options.Run,
+			"it will also update the local plugins. The latest chart versions and CoreDNS release are installed and " +
+			"recorded in the lock file. With --rollback-on-failure the helm release of a plugin which is not healthy " +
+			"after the update is rolled back to its previous revision.",
+		RunE: options.Run,
 	}
-	command.AddCommand(CreateUpdateCommands(options.registry)...)
+	options.locks.addFlags(command)
+	options.rollbacks.addFlags(command)
+	options.values.addFlags(command)
+	command.AddCommand(CreateUpdateCommands(options.registry, &options.locks, &options.rollbacks, &options.values)...)
 	command.Flags().BoolVarP(&options.includeLocalPlugins, "uninstallLocal", "l", false, "Also remove the local plugins.")
 	return command
 }
 
-func (i *UpdateOptions) Run(cmd *cobra.Command, args []string) {
-	for _, plugin := range i.registry.ListPlugins() {
-		if !i.includeLocalPlugins && apis.IsLocalPlugin(plugin) {
-			continue
-		}
-		logrus.Info("Update plugin:", plugin)
-		plugin.Update()
+func (i *UpdateOptions) Run(cmd *cobra.Command, args []string) error {
+	if e := i.values.check(); e != nil {
+		return e
 	}
+	return i.locks.run(lock.Refresh, func() {
+		for _, plugin := range i.registry.ListPlugins() {
+			if !i.includeLocalPlugins && apis.IsLocalPlugin(plugin) {
+				continue
+			}
+			logrus.Info("Update plugin:", plugin)
+			i.rollbacks.update(plugin)
+		}
+	})
 }
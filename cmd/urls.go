@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/plugins/portforward"
+	"github.com/qaware/minikube-support/pkg/urls"
+)
+
+// The output formats of the urls command.
+const (
+	outputTable = "table"
+	outputJson  = "json"
+)
+
+var (
+	openBrowser = urls.OpenBrowser
+	rootCAs     = urls.LocalRootCAs
+)
+
+// UrlsOptions contains the options to list and probe the endpoints reachable from the local os.
+type UrlsOptions struct {
+	handler        kubernetes.ContextHandler
+	portForwards   *[]string
+	portForwardDns *bool
+	output         string
+	open           string
+	timeout        time.Duration
+}
+
+// NewUrlsCommand creates the command which lists the urls of all ingresses, LoadBalancer services and port
+// forwards and probes if they are reachable.
+func NewUrlsCommand(handler kubernetes.ContextHandler, portForwards *[]string, portForwardDns *bool) *cobra.Command {
+	options := &UrlsOptions{handler: handler, portForwards: portForwards, portForwardDns: portForwardDns}
+
+	command := &cobra.Command{
+		Use:   "urls",
+		Short: "Lists and probes the urls of ingresses, LoadBalancer services and port forwards.",
+		Long: "The urls command lists every ingress host, LoadBalancer service and port forward. Ingress hosts with a tls " +
+			"section are served with https. For each url the host is resolved through the local resolver, the port is " +
+			"connected and http(s) urls are requested. Certificates are validated against the local CA.",
+		Args: cobra.NoArgs,
+		RunE: options.Run,
+	}
+	flags := command.Flags()
+	flags.StringVarP(&options.output, "output", "o", outputTable, "The output format: "+outputTable+" or "+outputJson+".")
+	flags.StringVar(&options.open, "open", "", "Open the url of the given host in the browser instead of probing the urls.")
+	flags.DurationVar(&options.timeout, "timeout", urls.DefaultTimeout, "The time to wait for each probe.")
+	return command
+}
+
+func (o *UrlsOptions) Run(cmd *cobra.Command, _ []string) error {
+	if o.output != outputTable && o.output != outputJson {
+		return fmt.Errorf("unknown output format %s, use %s or %s", o.output, outputTable, outputJson)
+	}
+	endpoints, e := o.collect()
+	if e != nil {
+		return e
+	}
+	if o.open != "" {
+		return openEndpoint(endpoints, o.open)
+	}
+
+	pool, e := rootCAs()
+	if e != nil {
+		return e
+	}
+	results := urls.NewProber(pool, o.timeout).Probe(endpoints)
+	if o.output == outputJson {
+		return writeJson(cmd.OutOrStdout(), results)
+	}
+	return writeTable(cmd.OutOrStdout(), results)
+}
+
+// collect returns the endpoints of the cluster and of the port forwards defined by the flags.
+func (o *UrlsOptions) collect() ([]urls.Endpoint, error) {
+	clientSet, e := o.handler.GetClientSet()
+	if e != nil {
+		return nil, fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	var definitions []string
+	if o.portForwards != nil {
+		definitions = *o.portForwards
+	}
+	forwards, e := portforward.ParseForwards(definitions)
+	if e != nil {
+		return nil, e
+	}
+	return urls.Collect(clientSet, forwards, o.portForwardDns != nil && *o.portForwardDns)
+}
+
+// openEndpoint opens the url of the host in the browser. If the host has several http endpoints, https is preferred.
+func openEndpoint(endpoints []urls.Endpoint, host string) error {
+	var found *urls.Endpoint
+	for i, endpoint := range endpoints {
+		if endpoint.Host != host || endpoint.Scheme == urls.SchemeTcp {
+			continue
+		}
+		if found == nil || (found.Scheme != urls.SchemeHttps && endpoint.Scheme == urls.SchemeHttps) {
+			found = &endpoints[i]
+		}
+	}
+	if found == nil {
+		return fmt.Errorf("no http or https url found for host %s", host)
+	}
+	return openBrowser(found.URL())
+}
+
+func writeJson(out io.Writer, results []urls.Result) error {
+	if results == nil {
+		results = []urls.Result{}
+	}
+	bytes, e := json.MarshalIndent(results, "", "  ")
+	if e != nil {
+		return e
+	}
+	_, e = fmt.Fprintln(out, string(bytes))
+	return e
+}
+
+func writeTable(out io.Writer, results []urls.Result) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TYPE\tNAME\tURL\tDNS\tTCP\tSTATUS\tLATENCY\tERROR")
+	for _, result := range results {
+		dns, tcp, status, latency := "failed", "-", "-", "-"
+		if result.Addresses != nil {
+			dns = strings.Join(result.Addresses, ",")
+			tcp = "failed"
+		}
+		if result.Connected {
+			tcp = "ok"
+			latency = result.Latency.Round(time.Millisecond).String()
+		}
+		if result.StatusCode != 0 {
+			status = strconv.Itoa(result.StatusCode)
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.Type, result.Name, result.URL(), dns, tcp, status, latency, result.Error)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/urls"
+)
+
+func TestUrlsOptions_Run(t *testing.T) {
+	defer func(open func(string) error, cas func() (*x509.CertPool, error)) {
+		openBrowser = open
+		rootCAs = cas
+	}(openBrowser, rootCAs)
+	rootCAs = func() (*x509.CertPool, error) { return x509.NewCertPool(), nil }
+	var opened string
+	openBrowser = func(url string) error {
+		opened = url
+		return nil
+	}
+	ingress := &networkingV1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "web"},
+		Spec: networkingV1.IngressSpec{
+			TLS:   []networkingV1.IngressTLS{{Hosts: []string{"secure.minikube"}}},
+			Rules: []networkingV1.IngressRule{{Host: "secure.minikube"}, {Host: "web.minikube"}},
+		},
+	}
+	forwards := []string{"db/svc/postgres 5432"}
+
+	tests := []struct {
+		name       string
+		output     string
+		open       string
+		wantOutput string
+		wantOpened string
+		wantErr    string
+	}{
+		{"open https", outputTable, "secure.minikube", "", "https://secure.minikube/", ""},
+		{"open http", outputTable, "web.minikube", "", "http://web.minikube/", ""},
+		{"open tcp", outputTable, "localhost", "", "", "no http or https url found for host localhost"},
+		{"unknown output", "yaml", "", "", "", "unknown output format yaml, use table or json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened = ""
+			o := &UrlsOptions{
+				handler:      fake.NewContextHandler(k8sFake.NewClientset(ingress), nil),
+				portForwards: &forwards,
+				output:       tt.output,
+				open:         tt.open,
+				timeout:      time.Second,
+			}
+			command := &cobra.Command{}
+			out := &bytes.Buffer{}
+			command.SetOut(out)
+
+			e := o.Run(command, nil)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, e, tt.wantErr)
+			} else {
+				assert.NoError(t, e)
+			}
+			assert.Equal(t, tt.wantOutput, out.String())
+			assert.Equal(t, tt.wantOpened, opened)
+		})
+	}
+}
+
+func TestUrlsOptions_Run_json(t *testing.T) {
+	o := &UrlsOptions{handler: fake.NewContextHandler(k8sFake.NewClientset(), nil), output: outputJson, timeout: time.Second}
+	command := &cobra.Command{}
+	out := &bytes.Buffer{}
+	command.SetOut(out)
+
+	assert.NoError(t, o.Run(command, nil))
+	assert.Equal(t, "[]\n", out.String())
+}
+
+func Test_writeJson(t *testing.T) {
+	out := &bytes.Buffer{}
+	results := []urls.Result{{
+		Endpoint:   urls.Endpoint{Type: urls.TypeIngress, Name: "app/web", Scheme: urls.SchemeHttps, Host: "web.minikube", Port: 443},
+		Addresses:  []string{"10.0.0.1"},
+		Connected:  true,
+		StatusCode: 200,
+		Latency:    12 * time.Millisecond,
+		LatencyMs:  12,
+	}}
+
+	assert.NoError(t, writeJson(out, results))
+	assert.JSONEq(t, `[{"type":"Ingress","name":"app/web","scheme":"https","host":"web.minikube","port":443,"addresses":["10.0.0.1"],"connected":true,"statusCode":200,"latencyMs":12}]`, out.String())
+}
+
+func Test_writeTable(t *testing.T) {
+	out := &bytes.Buffer{}
+	results := []urls.Result{
+		{Endpoint: urls.Endpoint{Type: urls.TypeIngress, Name: "app/web", Scheme: urls.SchemeHttps, Host: "web.minikube", Port: 443}, Addresses: []string{"10.0.0.1"}, Connected: true, StatusCode: 200, Latency: 12300 * time.Microsecond},
+		{Endpoint: urls.Endpoint{Type: urls.TypeService, Name: "db/postgres", Scheme: urls.SchemeTcp, Host: "postgres.db.svc.minikube", Port: 5432}, Addresses: []string{"10.0.0.2"}, Error: "tcp: connection refused"},
+		{Endpoint: urls.Endpoint{Type: urls.TypeIngress, Name: "app/old", Scheme: urls.SchemeHttp, Host: "old.minikube", Port: 80}, Error: "dns: no such host"},
+	}
+
+	assert.NoError(t, writeTable(out, results))
+	assert.Equal(t, ""+
+		"TYPE     NAME         URL                                  DNS       TCP     STATUS  LATENCY  ERROR\n"+
+		"Ingress  app/web      https://web.minikube/                10.0.0.1  ok      200     12ms     \n"+
+		"Service  db/postgres  tcp://postgres.db.svc.minikube:5432  10.0.0.2  failed  -       -        tcp: connection refused\n"+
+		"Ingress  app/old      http://old.minikube/                 failed    -       -       -        dns: no such host\n", out.String())
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+)
+
+// valuesOptions contains the flags to override the values of the helm plugins.
+type valuesOptions struct {
+	registry  apis.InstallablePluginRegistry
+	overrides *helm.Overrides
+	show      bool
+}
+
+// addFlags adds the values flags to the given command.
+func (o *valuesOptions) addFlags(command *cobra.Command) {
+	if o.overrides == nil {
+		o.overrides = &helm.Overrides{}
+	}
+	flags := command.PersistentFlags()
+	flags.StringArrayVar(&o.overrides.Files, "values", nil, "Merge the values file over the values of a helm plugin. Format: \"<plugin>=<file>\". Can be given multiple times.")
+	flags.StringArrayVar(&o.overrides.Set, "set", nil, "Set a value of a helm plugin like helm --set. Format: \"<plugin>.<key>=<value>\". Can be given multiple times.")
+}
+
+// addShowFlag adds the flag to show the values instead of installing the plugins.
+func (o *valuesOptions) addShowFlag(command *cobra.Command) {
+	command.PersistentFlags().BoolVar(&o.show, "show-values", false, "Only show the values the helm plugins would be installed with.")
+}
+
+// check verifies that values are only given for helm plugins and that they can be merged.
+func (o *valuesOptions) check() error {
+	if o.overrides == nil {
+		return nil
+	}
+	for _, name := range o.overrides.Plugins() {
+		plugin, e := o.registry.FindPlugin(name)
+		if e != nil {
+			return fmt.Errorf("values are given for unknown plugin %s", name)
+		}
+		helmPlugin, ok := plugin.(apis.HelmPlugin)
+		if !ok {
+			return fmt.Errorf("values are given for plugin %s which does not install a helm release", name)
+		}
+		if _, e := helmPlugin.Values(); e != nil {
+			return fmt.Errorf("invalid values for plugin %s: %s", name, e)
+		}
+	}
+	return nil
+}
+
+// showValues writes the values of all helm plugins among the given ones as yaml.
+func (o *valuesOptions) showValues(out io.Writer, plugins []apis.InstallablePlugin) error {
+	for _, plugin := range plugins {
+		helmPlugin, ok := plugin.(apis.HelmPlugin)
+		if !ok {
+			continue
+		}
+		values, e := helmPlugin.Values()
+		if e != nil {
+			return fmt.Errorf("invalid values for plugin %s: %s", plugin, e)
+		}
+		bytes, e := yaml.Marshal(values)
+		if e != nil {
+			return e
+		}
+		release, namespace := helmPlugin.Release()
+		if _, e := fmt.Fprintf(out, "# %s: helm release %s/%s\n%s---\n", plugin, namespace, release, bytes); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// run checks the values and runs the function. If the values should be shown, they are written to the output of
+// the command instead.
+func (o *valuesOptions) run(cmd *cobra.Command, plugins []apis.InstallablePlugin, f func() error) error {
+	if e := o.check(); e != nil {
+		return e
+	}
+	if o.show {
+		return o.showValues(cmd.OutOrStdout(), plugins)
+	}
+	return f()
+}
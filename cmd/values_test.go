@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	"github.com/qaware/minikube-support/pkg/plugins"
+)
+
+func Test_valuesOptions_run(t *testing.T) {
+	tests := []struct {
+		name       string
+		overrides  *helm.Overrides
+		show       bool
+		wantRun    bool
+		wantOutput string
+		wantErr    bool
+	}{
+		{"no overrides", nil, false, true, "", false},
+		{"helm plugin", &helm.Overrides{Set: []string{"dummy.replicaCount=2"}}, false, true, "", false},
+		{"unknown plugin", &helm.Overrides{Set: []string{"unknown.replicaCount=2"}}, false, false, "", true},
+		{"no helm plugin", &helm.Overrides{Files: []string{"other=values.yaml"}}, false, false, "", true},
+		{"show", nil, true, false, "# dummy: helm release mks/dummy\nreplicaCount: 1\n---\n", false},
+		{"show unknown plugin", &helm.Overrides{Set: []string{"unknown.replicaCount=2"}}, true, false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := plugins.NewInstallablePluginRegistry()
+			helmPlugin := &dummyHelmPlugin{}
+			registry.AddPlugins(helmPlugin, &DummyPlugin{name: "other"})
+			o := &valuesOptions{registry: registry, overrides: tt.overrides, show: tt.show}
+			command := &cobra.Command{}
+			out := &bytes.Buffer{}
+			command.SetOut(out)
+			run := false
+
+			e := o.run(command, []apis.InstallablePlugin{helmPlugin, &DummyPlugin{name: "other"}}, func() error {
+				run = true
+				return nil
+			})
+
+			assert.Equal(t, tt.wantErr, e != nil)
+			assert.Equal(t, tt.wantRun, run)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
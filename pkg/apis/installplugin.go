@@ -2,6 +2,7 @@ package apis
 
 import (
 	"fmt"
+	"runtime"
 )
 
 // InstallablePlugin is a plugin that can install/update/uninstall tools local or within minikube or both.
@@ -24,6 +25,69 @@ type InstallablePlugin interface {
 	Phase() Phase
 }
 
+// BundlablePlugin is an InstallablePlugin whose downloads can be bundled for an offline installation.
+type BundlablePlugin interface {
+	InstallablePlugin
+
+	// Artifacts returns the charts and releases which are downloaded to install the plugin.
+	Artifacts() Artifacts
+}
+
+// HelmPlugin is an InstallablePlugin which installs a helm release. Its release can be rolled back.
+type HelmPlugin interface {
+	InstallablePlugin
+
+	// Release returns the name and namespace of the helm release.
+	Release() (name string, namespace string)
+
+	// Ready checks if the installed release is healthy.
+	Ready() error
+
+	// Values returns the values the release is installed with.
+	Values() (map[string]interface{}, error)
+}
+
+// Artifacts are the charts and releases which are downloaded by a plugin.
+type Artifacts struct {
+	Charts   []ChartArtifact
+	Releases []ReleaseArtifact
+}
+
+// ChartArtifact is a helm chart and the values it is installed with.
+type ChartArtifact struct {
+	// RepositoryName is the name of the helm repository as it is added to helm.
+	RepositoryName string
+	// RepositoryURL is the url of the helm repository.
+	RepositoryURL string
+	// Chart references the chart as <repository>/<chart>.
+	Chart string
+	// Values are the values to install the chart. They define which images are used.
+	Values map[string]interface{}
+}
+
+// ReleaseArtifact is an asset of a GitHub release.
+type ReleaseArtifact struct {
+	Org        string
+	Repository string
+	// Asset returns the name of the downloaded asset of the release with the given tag for the platform.
+	Asset func(tag string, platform Platform) string
+}
+
+// Platform is the os and architecture artifacts are downloaded for.
+type Platform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+// LocalPlatform returns the platform of the local os.
+func LocalPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
 type Phase int
 
 const (
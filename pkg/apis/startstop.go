@@ -19,6 +19,20 @@ type StartStopPlugin interface {
 	IsSingleRunnable() bool
 }
 
+// HealthChecker can be implemented by start stop plugins that are able to report their health.
+type HealthChecker interface {
+	// Health returns nil if the plugin works as expected. Otherwise the error describes the problem.
+	Health() error
+}
+
+// CheckHealth returns the health of the given plugin. Plugins that do not implement HealthChecker are always healthy.
+func CheckHealth(plugin StartStopPlugin) error {
+	if checker, ok := plugin.(HealthChecker); ok {
+		return checker.Health()
+	}
+	return nil
+}
+
 // StartStopPluginRegistry is the registry which collects all StartStopPlugins and provides easy access to them.
 type StartStopPluginRegistry interface {
 	// AddPlugin adds a single plugin to the registry.
@@ -35,6 +49,9 @@ type StartStopPluginRegistry interface {
 type MonitoringMessage struct {
 	Box     string
 	Message string
+	// Context is the name of the kube context the message belongs to. It is only set if
+	// multiple contexts are watched at the same time.
+	Context string
 }
 
 // CloneMonitoringMessage creates a copy of the given MonitoringMessage.
@@ -42,6 +59,7 @@ func CloneMonitoringMessage(message *MonitoringMessage) MonitoringMessage {
 	return MonitoringMessage{
 		Box:     message.Box,
 		Message: message.Message,
+		Context: message.Context,
 	}
 }
 
@@ -0,0 +1,108 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pack writes all files of the directory into a gzipped tar archive.
+func pack(dir string, path string) (err error) {
+	file, e := os.Create(path)
+	if e != nil {
+		return e
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = e
+		}
+	}()
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	e = filepath.Walk(dir, func(name string, info os.FileInfo, e error) error {
+		if e != nil || !info.Mode().IsRegular() {
+			return e
+		}
+		relative, e := filepath.Rel(dir, name)
+		if e != nil {
+			return e
+		}
+		header, e := tar.FileInfoHeader(info, "")
+		if e != nil {
+			return e
+		}
+		header.Name = filepath.ToSlash(relative)
+		if e := tarWriter.WriteHeader(header); e != nil {
+			return e
+		}
+		content, e := os.Open(name)
+		if e != nil {
+			return e
+		}
+		defer func() { _ = content.Close() }()
+		_, e = io.Copy(tarWriter, content)
+		return e
+	})
+	if e != nil {
+		return e
+	}
+	if e := tarWriter.Close(); e != nil {
+		return e
+	}
+	return gzWriter.Close()
+}
+
+// extract extracts the regular files of the gzipped tar archive into the directory.
+func extract(path string, dir string) error {
+	file, e := os.Open(path)
+	if e != nil {
+		return e
+	}
+	defer func() { _ = file.Close() }()
+	gzReader, e := gzip.NewReader(file)
+	if e != nil {
+		return e
+	}
+	tarReader := tar.NewReader(gzReader)
+
+	for {
+		header, e := tarReader.Next()
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return e
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("file %s is outside of the bundle", header.Name)
+		}
+		if e := os.MkdirAll(filepath.Dir(target), 0755); e != nil {
+			return e
+		}
+		if e := writeFile(target, tarReader, os.FileMode(header.Mode)); e != nil {
+			return e
+		}
+	}
+}
+
+func writeFile(path string, reader io.Reader, mode os.FileMode) error {
+	file, e := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if e != nil {
+		return e
+	}
+	if _, e := io.Copy(file, reader); e != nil {
+		_ = file.Close()
+		return e
+	}
+	return file.Close()
+}
@@ -0,0 +1,192 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/cluster"
+)
+
+// ManifestFile is the name of the file within the bundle which lists its content.
+const ManifestFile = "bundle.yaml"
+
+const (
+	chartsDir   = "charts"
+	releasesDir = "releases"
+	imagesDir   = "images"
+)
+
+// Manifest lists the content of a bundle. All files are relative to the bundle directory.
+type Manifest struct {
+	// Platform is the platform the releases and images were downloaded for.
+	Platform apis.Platform `json:"platform"`
+	// Charts are the bundled helm charts by their reference <repository>/<chart>.
+	Charts map[string]Chart `json:"charts,omitempty"`
+	// Releases are the bundled GitHub releases by <org>/<repository>.
+	Releases map[string]Release `json:"releases,omitempty"`
+	// Images are the bundled container images.
+	Images []Image `json:"images,omitempty"`
+}
+
+// Chart is a bundled chart archive.
+type Chart struct {
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+	File       string `json:"file"`
+}
+
+// Release is a bundled GitHub release with its downloaded assets by their name.
+type Release struct {
+	Tag    string            `json:"tag"`
+	Assets map[string]string `json:"assets"`
+}
+
+// Image is a bundled image archive.
+type Image struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+// Bundle contains all downloads needed to install the plugins without internet access. Until it is opened,
+// everything is downloaded as usual.
+type Bundle struct {
+	dir      string
+	tmpDir   string
+	manifest Manifest
+	open     bool
+	mutex    sync.Mutex
+}
+
+// New creates a bundle which is not opened yet.
+func New() *Bundle {
+	return &Bundle{}
+}
+
+// Open opens the bundle at the given path. It is either a directory or an archive created by Create.
+func (b *Bundle) Open(path string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	dir := path
+	if isArchive(path) {
+		tmpDir, e := os.MkdirTemp("", "minikube-support-bundle")
+		if e != nil {
+			return fmt.Errorf("can not extract bundle %s: %s", path, e)
+		}
+		if e := extract(path, tmpDir); e != nil {
+			_ = os.RemoveAll(tmpDir)
+			return fmt.Errorf("can not extract bundle %s: %s", path, e)
+		}
+		b.tmpDir = tmpDir
+		dir = tmpDir
+	}
+
+	bytes, e := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if e != nil {
+		b.cleanup()
+		return fmt.Errorf("can not read bundle %s: %s", path, e)
+	}
+	manifest := Manifest{}
+	if e := yaml.Unmarshal(bytes, &manifest); e != nil {
+		b.cleanup()
+		return fmt.Errorf("can not parse manifest of bundle %s: %s", path, e)
+	}
+	if local := apis.LocalPlatform(); manifest.Platform != local {
+		b.cleanup()
+		return fmt.Errorf("bundle %s was created for %s and can not be installed on %s", path, manifest.Platform, local)
+	}
+	b.dir = dir
+	b.manifest = manifest
+	b.open = true
+	logrus.Infof("Installing from bundle %s.", path)
+	return nil
+}
+
+// Close closes the bundle and removes the extracted archive.
+func (b *Bundle) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.cleanup()
+	b.open = false
+	b.manifest = Manifest{}
+}
+
+// IsOpen checks if the installation uses the bundle.
+func (b *Bundle) IsOpen() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.open
+}
+
+// LoadImages loads all bundled images into the nodes of the cluster.
+func (b *Bundle) LoadImages(provider cluster.Provider) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, image := range b.manifest.Images {
+		logrus.Infof("Loading image %s into %s cluster %s.", image.Name, provider, provider.ClusterName())
+		if e := provider.LoadImage(filepath.Join(b.dir, image.File)); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// chart returns the bundled chart or an error if the chart is not part of the bundle.
+func (b *Bundle) chart(name string) (Chart, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	chart, found := b.manifest.Charts[name]
+	if !found {
+		return Chart{}, fmt.Errorf("chart %s is not part of the bundle", name)
+	}
+	return chart, nil
+}
+
+// release returns the bundled release or an error if the release is not part of the bundle.
+func (b *Bundle) release(name string) (Release, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	release, found := b.manifest.Releases[name]
+	if !found {
+		return Release{}, fmt.Errorf("release %s is not part of the bundle", name)
+	}
+	return release, nil
+}
+
+// path returns the absolute path of a file in the bundle.
+func (b *Bundle) path(file string) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return filepath.Join(b.dir, file)
+}
+
+// cleanup removes the extracted archive. The caller must hold the mutex.
+func (b *Bundle) cleanup() {
+	if b.tmpDir != "" {
+		_ = os.RemoveAll(b.tmpDir)
+		b.tmpDir = ""
+	}
+}
+
+// writeManifest writes the manifest into the bundle directory.
+func writeManifest(dir string, manifest Manifest) error {
+	sort.Slice(manifest.Images, func(i, j int) bool { return manifest.Images[i].Name < manifest.Images[j].Name })
+	bytes, e := yaml.Marshal(manifest)
+	if e != nil {
+		return fmt.Errorf("can not write bundle manifest: %s", e)
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), bytes, 0644)
+}
+
+// isArchive checks if the bundle at the path is a gzipped tar archive.
+func isArchive(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
@@ -0,0 +1,142 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/cluster"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+var testManifest = Manifest{
+	Platform: apis.LocalPlatform(),
+	Charts: map[string]Chart{
+		"jetstack/cert-manager": {Version: "v1.14.5", AppVersion: "v1.14.5", File: "charts/cert-manager-v1.14.5.tgz"},
+	},
+	Releases: map[string]Release{
+		"coredns/coredns": {Tag: "v1.11.1", Assets: map[string]string{"coredns_1.11.1_linux_amd64.tgz": "releases/coredns/coredns/v1.11.1/coredns_1.11.1_linux_amd64.tgz"}},
+	},
+	Images: []Image{
+		{Name: "quay.io/jetstack/cert-manager-controller:v1.14.5", File: "images/quay.io_jetstack_cert-manager-controller_v1.14.5.tar"},
+	},
+}
+
+// writeTestBundle writes a bundle directory with the test manifest and the listed files.
+func writeTestBundle(t *testing.T) string {
+	dir := t.TempDir()
+	files := []string{testManifest.Charts["jetstack/cert-manager"].File, testManifest.Images[0].File}
+	for _, file := range testManifest.Releases["coredns/coredns"].Assets {
+		files = append(files, file)
+	}
+	for _, file := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(file), 0644))
+	}
+	assert.NoError(t, writeManifest(dir, testManifest))
+	return dir
+}
+
+func TestBundle_Open(t *testing.T) {
+	dir := writeTestBundle(t)
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	assert.NoError(t, pack(dir, archive))
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"directory", dir, false},
+		{"archive", archive, false},
+		{"missing", filepath.Join(dir, "missing"), true},
+		{"missing archive", filepath.Join(dir, "missing.tgz"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New()
+			e := b.Open(tt.path)
+			defer b.Close()
+
+			if tt.wantErr {
+				assert.Error(t, e)
+				assert.False(t, b.IsOpen())
+				return
+			}
+			assert.NoError(t, e)
+			assert.True(t, b.IsOpen())
+			assert.Equal(t, testManifest, b.manifest)
+			chart, e := b.chart("jetstack/cert-manager")
+			assert.NoError(t, e)
+			content, e := os.ReadFile(b.path(chart.File))
+			assert.NoError(t, e)
+			assert.Equal(t, chart.File, string(content))
+			_, e = b.chart("twuni/docker-registry")
+			assert.Error(t, e)
+		})
+	}
+}
+
+func TestBundle_Open_otherPlatform(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, writeManifest(dir, Manifest{Platform: apis.Platform{OS: "plan9", Arch: "mips"}}))
+	b := New()
+
+	e := b.Open(dir)
+
+	assert.EqualError(t, e, "bundle "+dir+" was created for plan9/mips and can not be installed on "+apis.LocalPlatform().String())
+	assert.False(t, b.IsOpen())
+}
+
+func TestBundle_Close(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "bundle.tgz")
+	assert.NoError(t, pack(writeTestBundle(t), archive))
+	b := New()
+	assert.NoError(t, b.Open(archive))
+	tmpDir := b.tmpDir
+
+	b.Close()
+
+	assert.False(t, b.IsOpen())
+	assert.NoDirExists(t, tmpDir)
+}
+
+func TestBundle_LoadImages(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	dir := writeTestBundle(t)
+	testutils.MockWithStdOut("", 0, "kind", "load", "image-archive", filepath.Join(dir, testManifest.Images[0].File), "--name", "dev")
+	b := New()
+	assert.NoError(t, b.Open(dir))
+	defer b.Close()
+
+	assert.NoError(t, b.LoadImages(cluster.NewKind("dev")))
+	assert.Error(t, b.LoadImages(cluster.NewKind("other")))
+}
+
+func Test_extract(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "bundle.tgz")
+	file, e := os.Create(archive)
+	assert.NoError(t, e)
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+	assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0644, Size: 4}))
+	_, e = tarWriter.Write([]byte("evil"))
+	assert.NoError(t, e)
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzWriter.Close())
+	assert.NoError(t, file.Close())
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+	assert.EqualError(t, extract(archive, dir), "file ../evil is outside of the bundle")
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "evil"))
+}
+
+func TestHelperProcess(t *testing.T) {
+	testutils.StandardHelperProcess(t)
+}
@@ -0,0 +1,173 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/github"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	"github.com/qaware/minikube-support/pkg/sh"
+)
+
+var (
+	pullChart   = helm.PullChart
+	chartImages = helm.ChartImages
+)
+
+// Create downloads the charts, releases and images of the plugins for the platform into a bundle at the given
+// path. If the path ends with .tar.gz or .tgz, the bundle is written as archive, otherwise into the directory.
+// The chart versions and release tags are resolved by the given helm manager and GitHub client. Images are
+// pulled using docker for the linux nodes with the architecture of the platform.
+func Create(path string, platform apis.Platform, plugins []apis.BundlablePlugin, manager helm.Manager, client github.Client) error {
+	dir := path
+	if isArchive(path) {
+		tmpDir, e := os.MkdirTemp("", "minikube-support-bundle")
+		if e != nil {
+			return e
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		dir = tmpDir
+	}
+	for _, subDir := range []string{chartsDir, releasesDir, imagesDir} {
+		if e := os.MkdirAll(filepath.Join(dir, subDir), 0755); e != nil {
+			return fmt.Errorf("can not create bundle directory: %s", e)
+		}
+	}
+
+	var charts []apis.ChartArtifact
+	var releases []apis.ReleaseArtifact
+	for _, plugin := range plugins {
+		artifacts := plugin.Artifacts()
+		charts = append(charts, artifacts.Charts...)
+		releases = append(releases, artifacts.Releases...)
+	}
+
+	manifest := Manifest{Platform: platform, Charts: map[string]Chart{}, Releases: map[string]Release{}}
+	images, e := addCharts(dir, charts, manager, manifest)
+	if e != nil {
+		return e
+	}
+	if e := addReleases(dir, releases, client, manifest); e != nil {
+		return e
+	}
+	if manifest.Images, e = addImages(dir, images, platform); e != nil {
+		return e
+	}
+	if e := writeManifest(dir, manifest); e != nil {
+		return e
+	}
+
+	if dir != path {
+		if e := pack(dir, path); e != nil {
+			return fmt.Errorf("can not write bundle %s: %s", path, e)
+		}
+	}
+	logrus.Infof("Bundle %s created with %d charts, %d releases and %d images.", path, len(manifest.Charts), len(manifest.Releases), len(manifest.Images))
+	return nil
+}
+
+// addCharts downloads the charts into the bundle and returns the images used by them.
+func addCharts(dir string, charts []apis.ChartArtifact, manager helm.Manager, manifest Manifest) ([]string, error) {
+	if len(charts) == 0 {
+		return nil, nil
+	}
+	repositories := map[string]bool{}
+	for _, chart := range charts {
+		if repositories[chart.RepositoryName] {
+			continue
+		}
+		repositories[chart.RepositoryName] = true
+		if e := manager.AddRepository(chart.RepositoryName, chart.RepositoryURL); e != nil {
+			return nil, fmt.Errorf("can not add helm repository %s: %s", chart.RepositoryName, e)
+		}
+	}
+	if e := manager.UpdateRepository(); e != nil {
+		return nil, fmt.Errorf("can not update helm repositories: %s", e)
+	}
+
+	images := map[string]bool{}
+	for _, chart := range charts {
+		version, e := manager.ResolveChart(chart.Chart, "")
+		if e != nil {
+			return nil, e
+		}
+		logrus.Infof("Adding chart %s %s.", chart.Chart, version.Version)
+		path, e := pullChart(chart.Chart, version.Version, filepath.Join(dir, chartsDir))
+		if e != nil {
+			return nil, e
+		}
+		chartImages, e := chartImages(path, chart.Values)
+		if e != nil {
+			return nil, e
+		}
+		for _, image := range chartImages {
+			images[image] = true
+		}
+		manifest.Charts[chart.Chart] = Chart{Version: version.Version, AppVersion: version.AppVersion, File: filepath.Join(chartsDir, filepath.Base(path))}
+	}
+
+	result := make([]string, 0, len(images))
+	for image := range images {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// addReleases downloads the release assets into the bundle.
+func addReleases(dir string, releases []apis.ReleaseArtifact, client github.Client, manifest Manifest) error {
+	for _, release := range releases {
+		name := release.Org + "/" + release.Repository
+		tag, e := client.GetLatestReleaseTag(release.Org, release.Repository)
+		if e != nil {
+			return fmt.Errorf("can not get release of %s: %s", name, e)
+		}
+		asset := release.Asset(tag, manifest.Platform)
+		logrus.Infof("Adding release %s %s.", name, asset)
+
+		reader, e := client.DownloadReleaseAsset(release.Org, release.Repository, tag, asset)
+		if e != nil {
+			return fmt.Errorf("can not download %s of %s: %s", asset, name, e)
+		}
+		file := filepath.Join(releasesDir, release.Org, release.Repository, tag, asset)
+		if e := os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0755); e != nil {
+			_ = reader.Close()
+			return e
+		}
+		e = writeFile(filepath.Join(dir, file), reader, 0644)
+		_ = reader.Close()
+		if e != nil {
+			return fmt.Errorf("can not write %s of %s: %s", asset, name, e)
+		}
+		manifest.Releases[name] = Release{Tag: tag, Assets: map[string]string{asset: file}}
+	}
+	return nil
+}
+
+// addImages pulls the images for linux nodes of the architecture with docker and saves them into the bundle.
+func addImages(dir string, images []string, platform apis.Platform) ([]Image, error) {
+	var result []Image
+	for _, image := range images {
+		logrus.Infof("Adding image %s.", image)
+		if output, e := sh.RunCmd("docker", "pull", "--platform", "linux/"+platform.Arch, image); e != nil {
+			return nil, fmt.Errorf("can not pull image %s: %s\n%s", image, e, output)
+		}
+		file := filepath.Join(imagesDir, imageFileName(image))
+		if output, e := sh.RunCmd("docker", "save", "-o", filepath.Join(dir, file), image); e != nil {
+			return nil, fmt.Errorf("can not save image %s: %s\n%s", image, e, output)
+		}
+		result = append(result, Image{Name: image, File: file})
+	}
+	return result, nil
+}
+
+// imageFileName returns the name of the archive for the image reference.
+func imageFileName(image string) string {
+	return strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(image) + ".tar"
+}
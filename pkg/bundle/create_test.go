@@ -0,0 +1,80 @@
+package bundle
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	ghFake "github.com/qaware/minikube-support/pkg/github/fake"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	helmFake "github.com/qaware/minikube-support/pkg/packagemanager/helm/fake"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+type testPlugin struct {
+	apis.InstallablePlugin
+	artifacts apis.Artifacts
+}
+
+func (p *testPlugin) Artifacts() apis.Artifacts {
+	return p.artifacts
+}
+
+func TestCreate(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer func() { pullChart, chartImages = helm.PullChart, helm.ChartImages }()
+	pullChart = func(chart string, version string, dir string) (string, error) {
+		path := filepath.Join(dir, "cert-manager-"+version+".tgz")
+		return path, os.WriteFile(path, []byte(chart), 0644)
+	}
+	chartImages = func(path string, values map[string]interface{}) ([]string, error) {
+		return []string{"quay.io/jetstack/cert-manager-controller:v1.14.5"}, nil
+	}
+	dir := filepath.Join(t.TempDir(), "bundle")
+	testutils.MockWithStdOut("", 0, "docker", "pull", "--platform", "linux/"+runtime.GOARCH, "quay.io/jetstack/cert-manager-controller:v1.14.5")
+	testutils.MockWithStdOut("", 0, "docker", "save", "-o", filepath.Join(dir, testManifest.Images[0].File), "quay.io/jetstack/cert-manager-controller:v1.14.5")
+
+	manager := helmFake.NewMockManager(ctrl)
+	manager.EXPECT().AddRepository("jetstack", "https://charts.jetstack.io")
+	manager.EXPECT().UpdateRepository()
+	manager.EXPECT().ResolveChart("jetstack/cert-manager", "").
+		Return(&helm.ChartVersion{Chart: "jetstack/cert-manager", Version: "v1.14.5", AppVersion: "v1.14.5"}, nil)
+	client := ghFake.NewMockClient(ctrl)
+	client.EXPECT().GetLatestReleaseTag("coredns", "coredns").Return("v1.11.1", nil)
+	client.EXPECT().DownloadReleaseAsset("coredns", "coredns", "v1.11.1", "coredns_1.11.1_linux_amd64.tgz").
+		Return(io.NopCloser(strings.NewReader("coredns")), nil)
+
+	var assetPlatform apis.Platform
+	plugins := []apis.BundlablePlugin{
+		&testPlugin{artifacts: apis.Artifacts{Charts: []apis.ChartArtifact{{RepositoryName: "jetstack", RepositoryURL: "https://charts.jetstack.io", Chart: "jetstack/cert-manager"}}}},
+		&testPlugin{artifacts: apis.Artifacts{Releases: []apis.ReleaseArtifact{{Org: "coredns", Repository: "coredns", Asset: func(tag string, platform apis.Platform) string {
+			assetPlatform = platform
+			return "coredns_" + strings.TrimPrefix(tag, "v") + "_linux_amd64.tgz"
+		}}}}},
+	}
+	assert.NoError(t, Create(dir, apis.LocalPlatform(), plugins, manager, client))
+	assert.Equal(t, apis.LocalPlatform(), assetPlatform)
+
+	b := New()
+	assert.NoError(t, b.Open(dir))
+	defer b.Close()
+	assert.Equal(t, testManifest, b.manifest)
+	content, e := os.ReadFile(filepath.Join(dir, testManifest.Releases["coredns/coredns"].Assets["coredns_1.11.1_linux_amd64.tgz"]))
+	assert.NoError(t, e)
+	assert.Equal(t, "coredns", string(content))
+}
+
+func Test_imageFileName(t *testing.T) {
+	assert.Equal(t, "registry.k8s.io_ingress-nginx_controller_v1.10.1.tar", imageFileName("registry.k8s.io/ingress-nginx/controller:v1.10.1"))
+	assert.Equal(t, "registry_sha256_abc.tar", imageFileName("registry@sha256:abc"))
+}
@@ -0,0 +1,48 @@
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/qaware/minikube-support/pkg/github"
+)
+
+// githubClient returns the bundled GitHub releases while the bundle is open.
+type githubClient struct {
+	github.Client
+	bundle *Bundle
+}
+
+// NewGithubClient creates a GitHub client which returns the bundled release tags and assets if the bundle is open.
+// Otherwise it delegates to the given client.
+func NewGithubClient(client github.Client, bundle *Bundle) github.Client {
+	return &githubClient{Client: client, bundle: bundle}
+}
+
+func (c *githubClient) GetLatestReleaseTag(org string, repository string) (string, error) {
+	if !c.bundle.IsOpen() {
+		return c.Client.GetLatestReleaseTag(org, repository)
+	}
+	release, e := c.bundle.release(org + "/" + repository)
+	if e != nil {
+		return "", e
+	}
+	return release.Tag, nil
+}
+
+func (c *githubClient) DownloadReleaseAsset(org string, repository string, tag string, assetName string) (io.ReadCloser, error) {
+	if !c.bundle.IsOpen() {
+		return c.Client.DownloadReleaseAsset(org, repository, tag, assetName)
+	}
+	name := org + "/" + repository
+	release, e := c.bundle.release(name)
+	if e != nil {
+		return nil, e
+	}
+	file, found := release.Assets[assetName]
+	if release.Tag != tag || !found {
+		return nil, fmt.Errorf("asset %s of release %s %s is not part of the bundle", assetName, name, tag)
+	}
+	return os.Open(c.bundle.path(file))
+}
@@ -0,0 +1,41 @@
+package bundle
+
+import (
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/github/fake"
+)
+
+func Test_githubClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := fake.NewMockClient(ctrl)
+	b := New()
+	c := NewGithubClient(client, b)
+
+	client.EXPECT().GetLatestReleaseTag("coredns", "coredns").Return("v1.12.0", nil)
+	tag, e := c.GetLatestReleaseTag("coredns", "coredns")
+	assert.NoError(t, e)
+	assert.Equal(t, "v1.12.0", tag)
+
+	assert.NoError(t, b.Open(writeTestBundle(t)))
+	defer b.Close()
+	tag, e = c.GetLatestReleaseTag("coredns", "coredns")
+	assert.NoError(t, e)
+	assert.Equal(t, "v1.11.1", tag)
+
+	reader, e := c.DownloadReleaseAsset("coredns", "coredns", "v1.11.1", "coredns_1.11.1_linux_amd64.tgz")
+	assert.NoError(t, e)
+	content, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	assert.Equal(t, "releases/coredns/coredns/v1.11.1/coredns_1.11.1_linux_amd64.tgz", string(content))
+
+	_, e = c.DownloadReleaseAsset("coredns", "coredns", "v1.12.0", "coredns_1.12.0_linux_amd64.tgz")
+	assert.Error(t, e)
+	_, e = c.GetLatestReleaseTag("qaware", "minikube-support")
+	assert.Error(t, e)
+}
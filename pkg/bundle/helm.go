@@ -0,0 +1,77 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+)
+
+// helmManager installs the charts from the bundle while it is open.
+type helmManager struct {
+	helm.Manager
+	bundle *Bundle
+}
+
+// NewHelmManager creates a helm manager which installs the bundled chart archives if the bundle is open.
+// Otherwise it delegates to the given manager.
+func NewHelmManager(manager helm.Manager, bundle *Bundle) helm.Manager {
+	return &helmManager{Manager: manager, bundle: bundle}
+}
+
+func (m *helmManager) AddRepository(name string, url string) error {
+	if m.bundle.IsOpen() {
+		return nil
+	}
+	return m.Manager.AddRepository(name, url)
+}
+
+func (m *helmManager) UpdateRepository() error {
+	if m.bundle.IsOpen() {
+		return nil
+	}
+	return m.Manager.UpdateRepository()
+}
+
+// ResolveChart returns the bundled version of the chart. It must match the given version constraint.
+func (m *helmManager) ResolveChart(chart string, version string) (*helm.ChartVersion, error) {
+	if !m.bundle.IsOpen() {
+		return m.Manager.ResolveChart(chart, version)
+	}
+	bundled, e := m.bundle.chart(chart)
+	if e != nil {
+		return nil, e
+	}
+	if !matches(bundled.Version, version) {
+		return nil, fmt.Errorf("bundled version %s of chart %s does not match %s", bundled.Version, chart, version)
+	}
+	return &helm.ChartVersion{Chart: chart, Version: bundled.Version, AppVersion: bundled.AppVersion}, nil
+}
+
+func (m *helmManager) Install(chart string, version string, release string, namespace string, values map[string]interface{}, wait bool) error {
+	if !m.bundle.IsOpen() {
+		return m.Manager.Install(chart, version, release, namespace, values, wait)
+	}
+	if _, e := m.ResolveChart(chart, version); e != nil {
+		return &helm.ReleaseError{Action: "install", Release: release, Namespace: namespace, Err: e}
+	}
+	bundled, _ := m.bundle.chart(chart)
+	return m.Manager.Install(m.bundle.path(bundled.File), "", release, namespace, values, wait)
+}
+
+// matches checks if the version is equal to or satisfies the constraint. An empty constraint matches every version.
+func matches(version string, constraint string) bool {
+	if constraint == "" || constraint == version {
+		return true
+	}
+	c, e := semver.NewConstraint(constraint)
+	if e != nil {
+		return false
+	}
+	v, e := semver.NewVersion(version)
+	if e != nil {
+		return false
+	}
+	return c.Check(v)
+}
@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	helmFake "github.com/qaware/minikube-support/pkg/packagemanager/helm/fake"
+)
+
+func Test_helmManager_ResolveChart(t *testing.T) {
+	tests := []struct {
+		name    string
+		chart   string
+		version string
+		wantErr bool
+	}{
+		{"latest", "jetstack/cert-manager", "", false},
+		{"equal", "jetstack/cert-manager", "v1.14.5", false},
+		{"constraint", "jetstack/cert-manager", "~1.14", false},
+		{"other version", "jetstack/cert-manager", "v1.15.0", true},
+		{"not bundled", "twuni/docker-registry", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			b := New()
+			assert.NoError(t, b.Open(writeTestBundle(t)))
+			defer b.Close()
+
+			got, e := NewHelmManager(helmFake.NewMockManager(ctrl), b).ResolveChart(tt.chart, tt.version)
+
+			if tt.wantErr {
+				assert.Error(t, e)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Equal(t, &helm.ChartVersion{Chart: tt.chart, Version: "v1.14.5", AppVersion: "v1.14.5"}, got)
+		})
+	}
+}
+
+func Test_helmManager_Install(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	manager := helmFake.NewMockManager(ctrl)
+	dir := writeTestBundle(t)
+	b := New()
+	m := NewHelmManager(manager, b)
+
+	manager.EXPECT().AddRepository("jetstack", "https://charts.jetstack.io")
+	manager.EXPECT().UpdateRepository()
+	manager.EXPECT().Install("jetstack/cert-manager", "", "cert-manager", "mks", gomock.Any(), true)
+	assert.NoError(t, m.AddRepository("jetstack", "https://charts.jetstack.io"))
+	assert.NoError(t, m.UpdateRepository())
+	assert.NoError(t, m.Install("jetstack/cert-manager", "", "cert-manager", "mks", map[string]interface{}{}, true))
+
+	assert.NoError(t, b.Open(dir))
+	defer b.Close()
+	manager.EXPECT().Install(filepath.Join(dir, "charts/cert-manager-v1.14.5.tgz"), "", "cert-manager", "mks", gomock.Any(), true)
+	assert.NoError(t, m.AddRepository("jetstack", "https://charts.jetstack.io"))
+	assert.NoError(t, m.UpdateRepository())
+	assert.NoError(t, m.Install("jetstack/cert-manager", "", "cert-manager", "mks", map[string]interface{}{}, true))
+
+	e := m.Install("twuni/docker-registry", "", "registry", "mks", map[string]interface{}{}, false)
+	assert.EqualError(t, e, "can not install helm release mks/registry: chart twuni/docker-registry is not part of the bundle")
+}
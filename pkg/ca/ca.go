@@ -0,0 +1,257 @@
+// Package ca provides the certificate authority of minikube-support. It generates or imports the root CA,
+// stores it on disk and issues leaf certificates for arbitrary host names.
+package ca
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// CertFile is the file name of the root certificate. It uses the same layout as mkcert, so that
+	// `mkcert -install` can install the CA into the local trust stores.
+	CertFile = "rootCA.pem"
+	// KeyFile is the file name of the private key of the root certificate.
+	KeyFile = "rootCA-key.pem"
+	// DirEnv can be set to store the CA in another directory than the default one.
+	DirEnv = "MINIKUBE_SUPPORT_CAROOT"
+
+	rootValidity = 10 * 365 * 24 * time.Hour
+)
+
+// CA is a root certificate authority including its private key.
+type CA struct {
+	Certificate *x509.Certificate
+	Key         crypto.Signer
+}
+
+// DefaultDir returns the directory where the CA is stored. It can be overwritten with MINIKUBE_SUPPORT_CAROOT.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+	configDir, e := os.UserConfigDir()
+	if e != nil {
+		return "", fmt.Errorf("can not determ the config directory: %s", e)
+	}
+	return filepath.Join(configDir, "minikube-support", "ca"), nil
+}
+
+// Default loads the CA from the default directory. If there is none, a new CA will be generated and stored.
+func Default() (*CA, error) {
+	dir, e := DefaultDir()
+	if e != nil {
+		return nil, e
+	}
+	return LoadOrCreate(dir)
+}
+
+// Exists checks if the given directory contains a CA.
+func Exists(dir string) bool {
+	_, e := os.Stat(filepath.Join(dir, CertFile))
+	return e == nil
+}
+
+// LoadOrCreate loads the CA from the given directory or generates and stores a new one if there is none.
+func LoadOrCreate(dir string) (*CA, error) {
+	if Exists(dir) {
+		return Load(dir)
+	}
+	ca, e := Generate()
+	if e != nil {
+		return nil, e
+	}
+	if e := ca.Save(dir); e != nil {
+		return nil, e
+	}
+	logrus.Infof("Created a new root CA in %s", dir)
+	return ca, nil
+}
+
+// Generate creates a new self-signed root CA.
+func Generate() (*CA, error) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		return nil, fmt.Errorf("can not generate CA key: %s", e)
+	}
+	serial, e := randomSerial()
+	if e != nil {
+		return nil, e
+	}
+
+	owner := userAndHost()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"minikube-support development CA"},
+			OrganizationalUnit: []string{owner},
+			CommonName:         "minikube-support " + owner,
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(rootValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, e := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if e != nil {
+		return nil, fmt.Errorf("can not create CA certificate: %s", e)
+	}
+	certificate, e := x509.ParseCertificate(der)
+	if e != nil {
+		return nil, fmt.Errorf("can not parse CA certificate: %s", e)
+	}
+	return &CA{Certificate: certificate, Key: key}, nil
+}
+
+// Load reads the CA from the given directory.
+func Load(dir string) (*CA, error) {
+	certPEM, e := os.ReadFile(filepath.Join(dir, CertFile))
+	if e != nil {
+		return nil, fmt.Errorf("unable to read the CA certificate: %s", e)
+	}
+	keyPEM, e := os.ReadFile(filepath.Join(dir, KeyFile))
+	if e != nil {
+		return nil, fmt.Errorf("unable to read the CA key: %s", e)
+	}
+	return Import(certPEM, keyPEM)
+}
+
+// Import parses the given PEM encoded CA certificate and key. The certificate must be a CA certificate
+// and must belong to the key.
+func Import(certPEM []byte, keyPEM []byte) (*CA, error) {
+	certificates, e := ParseCertificates(certPEM)
+	if e != nil {
+		return nil, e
+	}
+	certificate := certificates[0]
+	if !certificate.IsCA {
+		return nil, errors.New("the certificate is no CA certificate")
+	}
+	key, e := ParsePrivateKey(keyPEM)
+	if e != nil {
+		return nil, e
+	}
+	if !publicKeysEqual(certificate.PublicKey, key.Public()) {
+		return nil, errors.New("the key does not belong to the CA certificate")
+	}
+	return &CA{Certificate: certificate, Key: key}, nil
+}
+
+// Save stores the CA in the given directory. The directory and the key are only accessible by the current user.
+func (c *CA) Save(dir string) error {
+	if e := os.MkdirAll(dir, 0700); e != nil {
+		return fmt.Errorf("can not create CA directory: %s", e)
+	}
+	keyPEM, e := EncodePrivateKeyPEM(c.Key)
+	if e != nil {
+		return e
+	}
+	if e := writeFile(filepath.Join(dir, KeyFile), keyPEM, 0400); e != nil {
+		return e
+	}
+	return writeFile(filepath.Join(dir, CertFile), c.CertificatePEM(), 0644)
+}
+
+// CertificatePEM returns the PEM encoded CA certificate.
+func (c *CA) CertificatePEM() []byte {
+	return EncodeCertificatesPEM(c.Certificate)
+}
+
+// writeFile writes the file with the given permissions, even if it already exists with other ones.
+func writeFile(name string, data []byte, perm os.FileMode) error {
+	if e := os.Remove(name); e != nil && !os.IsNotExist(e) {
+		return fmt.Errorf("can not replace %s: %s", name, e)
+	}
+	if e := os.WriteFile(name, data, perm); e != nil {
+		return fmt.Errorf("can not write %s: %s", name, e)
+	}
+	return nil
+}
+
+func randomSerial() (*big.Int, error) {
+	serial, e := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if e != nil {
+		return nil, fmt.Errorf("can not generate serial number: %s", e)
+	}
+	return serial, nil
+}
+
+func publicKeysEqual(a crypto.PublicKey, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+func userAndHost() string {
+	name := "unknown"
+	if u, e := user.Current(); e == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	return name + "@" + host
+}
+
+// ParseCertificates parses all certificates of the given PEM data.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, e := x509.ParseCertificate(block.Bytes)
+		if e != nil {
+			return nil, fmt.Errorf("can not parse certificate: %s", e)
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certificates, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS8, PKCS1 or EC private key.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no private key found")
+	}
+	var key interface{}
+	var e error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, e = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, e = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, e = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if e != nil {
+		return nil, fmt.Errorf("can not parse private key: %s", e)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return signer, nil
+}
@@ -0,0 +1,101 @@
+package ca
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	ca, e := Generate()
+	assert.NoError(t, e)
+	assert.True(t, ca.Certificate.IsCA)
+	assert.True(t, ca.Certificate.MaxPathLenZero)
+	assert.NotEmpty(t, ca.Certificate.SubjectKeyId)
+	assert.NoError(t, ca.Certificate.CheckSignatureFrom(ca.Certificate))
+}
+
+func TestCA_SaveLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ca")
+	ca, e := Generate()
+	assert.NoError(t, e)
+
+	assert.NoError(t, ca.Save(dir))
+	assert.NoError(t, ca.Save(dir), "existing files are replaced")
+	if runtime.GOOS != "windows" {
+		assertMode(t, dir, 0700)
+		assertMode(t, filepath.Join(dir, KeyFile), 0400)
+		assertMode(t, filepath.Join(dir, CertFile), 0644)
+	}
+
+	loaded, e := Load(dir)
+	assert.NoError(t, e)
+	assert.Equal(t, ca.Certificate.Raw, loaded.Certificate.Raw)
+	assert.True(t, publicKeysEqual(ca.Key.Public(), loaded.Key.Public()))
+}
+
+func TestLoadOrCreate(t *testing.T) {
+	dir := t.TempDir()
+	assert.False(t, Exists(dir))
+
+	created, e := LoadOrCreate(dir)
+	assert.NoError(t, e)
+	assert.True(t, Exists(dir))
+
+	loaded, e := LoadOrCreate(dir)
+	assert.NoError(t, e)
+	assert.Equal(t, created.Certificate.Raw, loaded.Certificate.Raw)
+}
+
+func TestImport(t *testing.T) {
+	ca, _ := Generate()
+	other, _ := Generate()
+	caKey, _ := EncodePrivateKeyPEM(ca.Key)
+	leaf, _ := ca.Issue([]string{"test.minikube"}, DefaultValidity)
+	leafKey, _ := leaf.KeyPEM()
+
+	tests := []struct {
+		name    string
+		cert    []byte
+		key     []byte
+		wantErr string
+	}{
+		{"ok", ca.CertificatePEM(), caKey, ""},
+		{"no certificate", []byte("invalid"), caKey, "no certificate found"},
+		{"no CA", EncodeCertificatesPEM(leaf.Certificate), leafKey, "the certificate is no CA certificate"},
+		{"no key", ca.CertificatePEM(), []byte("invalid"), "no private key found"},
+		{"other key", other.CertificatePEM(), caKey, "the key does not belong to the CA certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imported, e := Import(tt.cert, tt.key)
+			if tt.wantErr != "" {
+				assert.EqualError(t, e, tt.wantErr)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Equal(t, ca.Certificate.Raw, imported.Certificate.Raw)
+		})
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv(DirEnv, "/tmp/ca")
+	dir, e := DefaultDir()
+	assert.NoError(t, e)
+	assert.Equal(t, "/tmp/ca", dir)
+
+	t.Setenv(DirEnv, "")
+	dir, e = DefaultDir()
+	assert.NoError(t, e)
+	assert.Equal(t, filepath.Join("minikube-support", "ca"), filepath.Join(filepath.Base(filepath.Dir(dir)), filepath.Base(dir)))
+}
+
+func assertMode(t *testing.T, name string, mode os.FileMode) {
+	info, e := os.Stat(name)
+	assert.NoError(t, e)
+	assert.Equal(t, mode, info.Mode().Perm(), name)
+}
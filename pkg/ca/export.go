@@ -0,0 +1,105 @@
+package ca
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// DefaultPassword is the default password of exported key and trust stores. It is the default password of the
+// java trust store.
+const DefaultPassword = "changeit"
+
+// EncodeCertificatesPEM encodes the given certificates as PEM.
+func EncodeCertificatesPEM(certificates ...*x509.Certificate) []byte {
+	buffer := new(bytes.Buffer)
+	for _, certificate := range certificates {
+		_ = pem.Encode(buffer, &pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+	}
+	return buffer.Bytes()
+}
+
+// EncodePrivateKeyPEM encodes the given key as PKCS8 PEM.
+func EncodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	der, e := x509.MarshalPKCS8PrivateKey(key)
+	if e != nil {
+		return nil, fmt.Errorf("can not encode private key: %s", e)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// EncodePKCS12TrustStore encodes the given certificates as PKCS12 trust store.
+func EncodePKCS12TrustStore(certificates []*x509.Certificate, password string) ([]byte, error) {
+	data, e := pkcs12.Modern.EncodeTrustStore(certificates, password)
+	if e != nil {
+		return nil, fmt.Errorf("can not encode PKCS12 trust store: %s", e)
+	}
+	return data, nil
+}
+
+// EncodeJKSTrustStore encodes the given certificates as java key store with one trusted certificate entry
+// per certificate. The result only depends on the certificates, so it can be compared with older ones.
+func EncodeJKSTrustStore(certificates []*x509.Certificate, password string) ([]byte, error) {
+	ks := keystore.New()
+	for i, certificate := range certificates {
+		entry := keystore.TrustedCertificateEntry{
+			CreationTime: certificate.NotBefore,
+			Certificate:  keystore.Certificate{Type: "X509", Content: certificate.Raw},
+		}
+		if e := ks.SetTrustedCertificateEntry(fmt.Sprintf("minikube-ca-%d", i), entry); e != nil {
+			return nil, fmt.Errorf("can not add certificate to JKS trust store: %s", e)
+		}
+	}
+	return storeJKS(ks, password)
+}
+
+// EncodePKCS12 encodes the certificate including its key and chain as PKCS12 key store.
+func (l *Leaf) EncodePKCS12(password string) ([]byte, error) {
+	data, e := pkcs12.Modern.Encode(l.Key, l.Certificate, l.Chain, password)
+	if e != nil {
+		return nil, fmt.Errorf("can not encode PKCS12 key store: %s", e)
+	}
+	return data, nil
+}
+
+// EncodeJKS encodes the certificate including its key and chain as java key store with the alias "certificate".
+func (l *Leaf) EncodeJKS(password string) ([]byte, error) {
+	der, e := x509.MarshalPKCS8PrivateKey(l.Key)
+	if e != nil {
+		return nil, fmt.Errorf("can not encode private key: %s", e)
+	}
+	chain := []keystore.Certificate{{Type: "X509", Content: l.Certificate.Raw}}
+	for _, certificate := range l.Chain {
+		chain = append(chain, keystore.Certificate{Type: "X509", Content: certificate.Raw})
+	}
+	ks := keystore.New()
+	entry := keystore.PrivateKeyEntry{CreationTime: time.Now(), PrivateKey: der, CertificateChain: chain}
+	if e := ks.SetPrivateKeyEntry("certificate", entry, []byte(password)); e != nil {
+		return nil, fmt.Errorf("can not add key to JKS key store: %s", e)
+	}
+	return storeJKS(ks, password)
+}
+
+// CertificatePEM returns the PEM encoded certificate followed by its chain.
+func (l *Leaf) CertificatePEM() []byte {
+	return EncodeCertificatesPEM(append([]*x509.Certificate{l.Certificate}, l.Chain...)...)
+}
+
+// KeyPEM returns the PEM encoded private key of the certificate.
+func (l *Leaf) KeyPEM() ([]byte, error) {
+	return EncodePrivateKeyPEM(l.Key)
+}
+
+func storeJKS(ks keystore.KeyStore, password string) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if e := ks.Store(buffer, []byte(password)); e != nil {
+		return nil, fmt.Errorf("can not encode JKS key store: %s", e)
+	}
+	return buffer.Bytes(), nil
+}
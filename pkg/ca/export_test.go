@@ -0,0 +1,61 @@
+package ca
+
+import (
+	"bytes"
+	"crypto/x509"
+	"testing"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"github.com/stretchr/testify/assert"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestEncodeTrustStores(t *testing.T) {
+	ca, _ := Generate()
+
+	p12, e := EncodePKCS12TrustStore([]*x509.Certificate{ca.Certificate}, DefaultPassword)
+	assert.NoError(t, e)
+	certificates, e := pkcs12.DecodeTrustStore(p12, DefaultPassword)
+	assert.NoError(t, e)
+	assert.Equal(t, ca.Certificate.Raw, certificates[0].Raw)
+
+	jks, e := EncodeJKSTrustStore([]*x509.Certificate{ca.Certificate}, DefaultPassword)
+	assert.NoError(t, e)
+	again, _ := EncodeJKSTrustStore([]*x509.Certificate{ca.Certificate}, DefaultPassword)
+	assert.Equal(t, jks, again, "JKS trust stores are reproducible")
+	ks := keystore.New()
+	assert.NoError(t, ks.Load(bytes.NewReader(jks), []byte(DefaultPassword)))
+	entry, e := ks.GetTrustedCertificateEntry("minikube-ca-0")
+	assert.NoError(t, e)
+	assert.Equal(t, ca.Certificate.Raw, entry.Certificate.Content)
+}
+
+func TestLeaf_Encode(t *testing.T) {
+	ca, _ := Generate()
+	leaf, _ := ca.Issue([]string{"app.minikube"}, DefaultValidity)
+
+	p12, e := leaf.EncodePKCS12("secret")
+	assert.NoError(t, e)
+	key, certificate, chain, e := pkcs12.DecodeChain(p12, "secret")
+	assert.NoError(t, e)
+	assert.NotNil(t, key)
+	assert.Equal(t, leaf.Certificate.Raw, certificate.Raw)
+	assert.Equal(t, ca.Certificate.Raw, chain[0].Raw)
+
+	jks, e := leaf.EncodeJKS("secret")
+	assert.NoError(t, e)
+	ks := keystore.New()
+	assert.NoError(t, ks.Load(bytes.NewReader(jks), []byte("secret")))
+	entry, e := ks.GetPrivateKeyEntry("certificate", []byte("secret"))
+	assert.NoError(t, e)
+	assert.Len(t, entry.CertificateChain, 2)
+
+	certificates, e := ParseCertificates(leaf.CertificatePEM())
+	assert.NoError(t, e)
+	assert.Len(t, certificates, 2)
+	keyPEM, e := leaf.KeyPEM()
+	assert.NoError(t, e)
+	parsed, e := ParsePrivateKey(keyPEM)
+	assert.NoError(t, e)
+	assert.True(t, publicKeysEqual(leaf.Key.Public(), parsed.Public()))
+}
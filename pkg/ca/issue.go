@@ -0,0 +1,77 @@
+package ca
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"time"
+)
+
+// DefaultValidity is the validity of issued certificates. It is the maximum accepted by the apple platforms.
+const DefaultValidity = 825 * 24 * time.Hour
+
+// Leaf is a certificate issued by the CA including its private key.
+type Leaf struct {
+	Certificate *x509.Certificate
+	Key         crypto.Signer
+	// Chain contains the issuing CA certificate.
+	Chain []*x509.Certificate
+}
+
+// Issue creates a new certificate for the given subject alternative names. Every name can be a host name
+// (including wildcards like *.app.minikube), an ip address or an email address.
+func (c *CA) Issue(sans []string, validity time.Duration) (*Leaf, error) {
+	if len(sans) == 0 {
+		return nil, errors.New("at least one subject alternative name is required")
+	}
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		return nil, fmt.Errorf("can not generate certificate key: %s", e)
+	}
+	serial, e := randomSerial()
+	if e != nil {
+		return nil, e
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"minikube-support development certificate"},
+			CommonName:   sans[0],
+		},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(validity),
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		AuthorityKeyId: c.Certificate.SubjectKeyId,
+	}
+	for _, san := range sans {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if address, e := mail.ParseAddress(san); e == nil && address.Address == san {
+			template.EmailAddresses = append(template.EmailAddresses, san)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+	if template.NotAfter.After(c.Certificate.NotAfter) {
+		template.NotAfter = c.Certificate.NotAfter
+	}
+
+	der, e := x509.CreateCertificate(rand.Reader, template, c.Certificate, key.Public(), c.Key)
+	if e != nil {
+		return nil, fmt.Errorf("can not create certificate: %s", e)
+	}
+	certificate, e := x509.ParseCertificate(der)
+	if e != nil {
+		return nil, fmt.Errorf("can not parse certificate: %s", e)
+	}
+	return &Leaf{Certificate: certificate, Key: key, Chain: []*x509.Certificate{c.Certificate}}, nil
+}
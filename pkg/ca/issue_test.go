@@ -0,0 +1,44 @@
+package ca
+
+import (
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCA_Issue(t *testing.T) {
+	ca, e := Generate()
+	assert.NoError(t, e)
+
+	leaf, e := ca.Issue([]string{"app.minikube", "*.app.minikube", "127.0.0.1", "dev@example.com"}, DefaultValidity)
+	assert.NoError(t, e)
+
+	assert.Equal(t, "app.minikube", leaf.Certificate.Subject.CommonName)
+	assert.Equal(t, []string{"app.minikube", "*.app.minikube"}, leaf.Certificate.DNSNames)
+	assert.True(t, leaf.Certificate.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")))
+	assert.Equal(t, []string{"dev@example.com"}, leaf.Certificate.EmailAddresses)
+	assert.False(t, leaf.Certificate.IsCA)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+	for _, name := range []string{"app.minikube", "api.app.minikube", "127.0.0.1"} {
+		_, e = leaf.Certificate.Verify(x509.VerifyOptions{DNSName: name, Roots: roots})
+		assert.NoError(t, e, name)
+	}
+	_, e = leaf.Certificate.Verify(x509.VerifyOptions{DNSName: "other.minikube", Roots: roots})
+	assert.Error(t, e)
+}
+
+func TestCA_Issue_validity(t *testing.T) {
+	ca, _ := Generate()
+
+	leaf, e := ca.Issue([]string{"app.minikube"}, 100*365*24*time.Hour)
+	assert.NoError(t, e)
+	assert.Equal(t, ca.Certificate.NotAfter, leaf.Certificate.NotAfter, "a certificate must not outlive its CA")
+
+	_, e = ca.Issue(nil, DefaultValidity)
+	assert.Error(t, e)
+}
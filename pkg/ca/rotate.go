@@ -0,0 +1,87 @@
+package ca
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// RetiredDir is the sub directory of the CA directory containing the previous CAs until they are retired.
+const RetiredDir = "retired"
+
+// Rotate replaces the CA in the given directory with a newly generated one. The previous CA is moved into
+// the retired directory, so that it can still be trusted until all certificates are issued by the new CA.
+// It returns the new CA and the directory of the previous one.
+func Rotate(dir string) (*CA, string, error) {
+	previous, e := Load(dir)
+	if e != nil {
+		return nil, "", e
+	}
+	authority, e := Generate()
+	if e != nil {
+		return nil, "", e
+	}
+
+	previousDir := filepath.Join(dir, RetiredDir, previous.Certificate.SerialNumber.Text(16))
+	if e := previous.Save(previousDir); e != nil {
+		return nil, "", e
+	}
+	if e := authority.Save(dir); e != nil {
+		return nil, "", e
+	}
+	return authority, previousDir, nil
+}
+
+// Retired returns the directories of all previous CAs which are not retired yet.
+func Retired(dir string) ([]string, error) {
+	entries, e := os.ReadDir(filepath.Join(dir, RetiredDir))
+	if os.IsNotExist(e) {
+		return nil, nil
+	}
+	if e != nil {
+		return nil, fmt.Errorf("can not list retired CAs: %s", e)
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && Exists(filepath.Join(dir, RetiredDir, entry.Name())) {
+			dirs = append(dirs, filepath.Join(dir, RetiredDir, entry.Name()))
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// Retire finally removes the previous CA stored in the given directory.
+func Retire(previousDir string) error {
+	if e := os.RemoveAll(previousDir); e != nil {
+		return fmt.Errorf("can not remove retired CA %s: %s", previousDir, e)
+	}
+	return nil
+}
+
+// Bundle returns the certificate of the CA in the given directory followed by the not expired certificates
+// of the previous CAs which are not retired yet.
+func Bundle(dir string) ([]*x509.Certificate, error) {
+	authority, e := LoadOrCreate(dir)
+	if e != nil {
+		return nil, e
+	}
+	certificates := []*x509.Certificate{authority.Certificate}
+	previousDirs, e := Retired(dir)
+	if e != nil {
+		return nil, e
+	}
+	for _, previousDir := range previousDirs {
+		previous, e := Load(previousDir)
+		if e != nil {
+			return nil, e
+		}
+		if previous.Certificate.NotAfter.After(time.Now()) {
+			certificates = append(certificates, previous.Certificate)
+		}
+	}
+	return certificates, nil
+}
@@ -0,0 +1,44 @@
+package ca
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotate(t *testing.T) {
+	dir := t.TempDir()
+	previous, _ := LoadOrCreate(dir)
+
+	authority, previousDir, e := Rotate(dir)
+	assert.NoError(t, e)
+	assert.NotEqual(t, previous.Certificate.Raw, authority.Certificate.Raw)
+
+	loaded, e := Load(dir)
+	assert.NoError(t, e)
+	assert.Equal(t, authority.Certificate.Raw, loaded.Certificate.Raw)
+	retired, e := Retired(dir)
+	assert.NoError(t, e)
+	assert.Equal(t, []string{previousDir}, retired)
+	loaded, e = Load(previousDir)
+	assert.NoError(t, e)
+	assert.Equal(t, previous.Certificate.Raw, loaded.Certificate.Raw)
+
+	bundle, e := Bundle(dir)
+	assert.NoError(t, e)
+	assert.Len(t, bundle, 2)
+	assert.Equal(t, authority.Certificate.Raw, bundle[0].Raw)
+	assert.Equal(t, previous.Certificate.Raw, bundle[1].Raw)
+
+	assert.NoError(t, Retire(previousDir))
+	retired, e = Retired(dir)
+	assert.NoError(t, e)
+	assert.Empty(t, retired)
+	bundle, _ = Bundle(dir)
+	assert.Len(t, bundle, 1)
+}
+
+func TestRotate_noCA(t *testing.T) {
+	_, _, e := Rotate(t.TempDir())
+	assert.ErrorContains(t, e, "unable to read the CA certificate")
+}
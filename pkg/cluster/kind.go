@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qaware/minikube-support/pkg/sh"
+)
+
+const (
+	// kindContextPrefix is the prefix of all kube contexts created by kind.
+	kindContextPrefix = "kind-"
+	// kindNetwork is the docker network of all kind nodes.
+	kindNetwork = "kind"
+	// kindClusterLabel is the docker label containing the cluster name of a kind node.
+	kindClusterLabel = "io.x-k8s.kind.cluster"
+	// cloudProviderKind is the binary that assigns addresses to LoadBalancer services of kind clusters.
+	cloudProviderKind = "cloud-provider-kind"
+)
+
+// Kind is the provider for clusters created by kind. Every node is a docker container.
+type Kind struct {
+	name string
+}
+
+// NewKind initializes the provider for the kind cluster with the given name.
+func NewKind(name string) *Kind {
+	return &Kind{name: name}
+}
+
+func (k *Kind) String() string {
+	return "kind"
+}
+
+func (k *Kind) ClusterName() string {
+	return k.name
+}
+
+// NodeIPs inspects the node containers of the cluster to get their addresses.
+func (k *Kind) NodeIPs() ([]string, error) {
+	output, e := sh.RunCmd("docker", "ps", "-q", "--filter", "label="+kindClusterLabel+"="+k.name)
+	if e != nil {
+		return nil, fmt.Errorf("can not list nodes of kind cluster %s: %s", k.name, e)
+	}
+	containers := strings.Fields(output)
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("kind cluster %s has no running nodes", k.name)
+	}
+
+	args := append([]string{"inspect", "--format", "{{.Name}} {{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}"}, containers...)
+	output, e = sh.RunCmd("docker", args...)
+	if e != nil {
+		return nil, fmt.Errorf("can not inspect nodes of kind cluster %s: %s", k.name, e)
+	}
+
+	var ips []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// the control plane is the primary node
+		if strings.HasSuffix(fields[0], "-control-plane") {
+			ips = append([]string{fields[1]}, ips...)
+		} else {
+			ips = append(ips, fields[1])
+		}
+	}
+	return ips, nil
+}
+
+// LoadBalancer returns LoadBalancerTunnel if cloud-provider-kind is installed. Otherwise kind has no support
+// for LoadBalancer services.
+func (k *Kind) LoadBalancer() LoadBalancerStrategy {
+	if _, e := sh.RunCmd("which", cloudProviderKind); e != nil {
+		return LoadBalancerNone
+	}
+	return LoadBalancerTunnel
+}
+
+func (k *Kind) TunnelCommand() []string {
+	if k.LoadBalancer() != LoadBalancerTunnel {
+		return nil
+	}
+	return []string{cloudProviderKind}
+}
+
+// HostGateway returns the ipv4 gateway of the kind docker network.
+func (k *Kind) HostGateway() (string, error) {
+	output, e := sh.RunCmd("docker", "network", "inspect", kindNetwork, "--format", "{{range .IPAM.Config}}{{.Gateway}} {{end}}")
+	if e != nil {
+		return "", fmt.Errorf("can not inspect docker network %s: %s", kindNetwork, e)
+	}
+	for _, gateway := range strings.Fields(output) {
+		if ip := net.ParseIP(gateway); ip != nil && ip.To4() != nil {
+			return gateway, nil
+		}
+	}
+	return "", fmt.Errorf("docker network %s has no ipv4 gateway", kindNetwork)
+}
+
+// LoadImage loads the image archive into all nodes of the cluster.
+func (k *Kind) LoadImage(archive string) error {
+	if output, e := sh.RunCmd("kind", "load", "image-archive", archive, "--name", k.name); e != nil {
+		return fmt.Errorf("can not load image %s into kind cluster %s: %s\n%s", archive, k.name, e, output)
+	}
+	return nil
+}
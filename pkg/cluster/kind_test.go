@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func TestKind_NodeIPs(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	testutils.MockWithStdOut("a1\nb2\n", 0, "docker", "ps", "-q", "--filter", "label=io.x-k8s.kind.cluster=dev")
+	testutils.MockWithStdOut("/dev-worker 172.18.0.3 \n/dev-control-plane 172.18.0.2 \n", 0,
+		"docker", "inspect", "--format", "{{.Name}} {{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}", "a1", "b2")
+
+	got, err := NewKind("dev").NodeIPs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"172.18.0.2", "172.18.0.3"}, got)
+}
+
+func TestKind_NodeIPs_noNodes(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	testutils.MockWithStdOut("", 0, "docker", "ps", "-q", "--filter", "label=io.x-k8s.kind.cluster=dev")
+
+	_, err := NewKind("dev").NodeIPs()
+
+	assert.Error(t, err)
+}
+
+func TestKind_LoadBalancer(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+
+	k := NewKind("dev")
+	assert.Equal(t, LoadBalancerNone, k.LoadBalancer())
+	assert.Nil(t, k.TunnelCommand())
+
+	testutils.MockWithStdOut("/usr/local/bin/cloud-provider-kind", 0, "which", "cloud-provider-kind")
+	assert.Equal(t, LoadBalancerTunnel, k.LoadBalancer())
+	assert.Equal(t, []string{"cloud-provider-kind"}, k.TunnelCommand())
+}
+
+func TestKind_HostGateway(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		status  int
+		want    string
+		wantErr bool
+	}{
+		{"ipv4 and ipv6", "fc00:f853:ccd:e793::1 172.18.0.1 \n", 0, "172.18.0.1", false},
+		{"only ipv6", "fc00:f853:ccd:e793::1 \n", 0, "", true},
+		{"no network", "", 1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.StartCommandLineTest()
+			defer testutils.StopCommandLineTest()
+			testutils.MockWithStdOut(tt.output, tt.status, "docker", "network", "inspect", "kind", "--format", "{{range .IPAM.Config}}{{.Gateway}} {{end}}")
+
+			got, err := NewKind("dev").HostGateway()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HostGateway() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKind_LoadImage(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	testutils.MockWithStdOut("", 0, "kind", "load", "image-archive", "/bundle/images/nginx.tar", "--name", "dev")
+	testutils.MockWithStdOut("no nodes", 1, "kind", "load", "image-archive", "/bundle/images/missing.tar", "--name", "dev")
+
+	assert.NoError(t, NewKind("dev").LoadImage("/bundle/images/nginx.tar"))
+	assert.Error(t, NewKind("dev").LoadImage("/bundle/images/missing.tar"))
+}
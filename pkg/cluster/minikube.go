@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qaware/minikube-support/pkg/minikube"
+	"github.com/qaware/minikube-support/pkg/sh"
+)
+
+// Minikube is the provider for clusters created by minikube.
+type Minikube struct {
+	// Profile is the minikube profile of the cluster.
+	Profile *minikube.Profile
+}
+
+// NewMinikube initializes the provider for the given minikube profile.
+func NewMinikube(profile *minikube.Profile) *Minikube {
+	return &Minikube{Profile: profile}
+}
+
+// findMinikubeProfile returns the minikube profile of the given context or nil if there is none.
+func findMinikubeProfile(contextName string, server string) (*minikube.Profile, error) {
+	profiles, e := minikube.ListProfiles()
+	if e != nil {
+		return nil, e
+	}
+	return minikube.FindProfile(profiles, contextName, server), nil
+}
+
+func (m *Minikube) String() string {
+	return "minikube"
+}
+
+func (m *Minikube) ClusterName() string {
+	return m.Profile.Name
+}
+
+// NodeIPs reads the current node addresses of the profile, because they may change after
+// every restart of minikube. If the profile does not know them `minikube ip` is used to get
+// at least the address of the primary node.
+func (m *Minikube) NodeIPs() ([]string, error) {
+	profiles, e := minikube.ListProfiles()
+	if e != nil {
+		return nil, e
+	}
+	for _, profile := range profiles {
+		if ips := profile.NodeIPs(); profile.Name == m.Profile.Name && len(ips) > 0 {
+			return ips, nil
+		}
+	}
+
+	ip, e := sh.RunCmd("minikube", m.Profile.Args("ip")...)
+	if e != nil {
+		return nil, fmt.Errorf("can not determ minikube ip: %s", e)
+	}
+	return []string{strings.TrimSpace(ip)}, nil
+}
+
+func (m *Minikube) LoadBalancer() LoadBalancerStrategy {
+	return LoadBalancerTunnel
+}
+
+func (m *Minikube) TunnelCommand() []string {
+	return append([]string{"minikube"}, m.Profile.Args("tunnel")...)
+}
+
+// HostGateway reads the address of host.minikube.internal from the hosts file of the primary node.
+func (m *Minikube) HostGateway() (string, error) {
+	output, e := sh.RunCmd("minikube", m.Profile.Args("ssh", "--", "grep", "host.minikube.internal", "/etc/hosts")...)
+	if e != nil {
+		return "", fmt.Errorf("can not determ host address of minikube: %s", e)
+	}
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("host.minikube.internal is not defined in minikube")
+	}
+	return fields[0], nil
+}
+
+// LoadImage loads the image archive into all nodes of the profile.
+func (m *Minikube) LoadImage(archive string) error {
+	if output, e := sh.RunCmd("minikube", m.Profile.Args("image", "load", archive)...); e != nil {
+		return fmt.Errorf("can not load image %s into minikube: %s\n%s", archive, e, output)
+	}
+	return nil
+}
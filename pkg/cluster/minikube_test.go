@@ -0,0 +1,80 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/minikube"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func TestMinikube_NodeIPs(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles string
+		want     []string
+	}{
+		{"from profile", `{"valid":[{"Name":"app","Config":{"Nodes":[{"IP":"192.168.64.3"},{"Name":"m02","IP":"192.168.64.4"}]}}]}`, []string{"192.168.64.3", "192.168.64.4"}},
+		{"without node ips", `{"valid":[{"Name":"app","Config":{"Nodes":[{}]}}]}`, []string{"192.168.64.5"}},
+		{"other profile", `{"valid":[{"Name":"other","Config":{"Nodes":[{"IP":"192.168.64.3"}]}}]}`, []string{"192.168.64.5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.StartCommandLineTest()
+			defer testutils.StopCommandLineTest()
+			testutils.MockWithStdOut(tt.profiles, 0, "minikube", "profile", "list", "-o", "json")
+			testutils.MockWithStdOut("192.168.64.5\n", 0, "minikube", "-p", "app", "ip")
+
+			got, err := NewMinikube(&minikube.Profile{Name: "app"}).NodeIPs()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMinikube_TunnelCommand(t *testing.T) {
+	m := NewMinikube(&minikube.Profile{Name: "app"})
+
+	assert.Equal(t, LoadBalancerTunnel, m.LoadBalancer())
+	assert.Equal(t, []string{"minikube", "-p", "app", "tunnel"}, m.TunnelCommand())
+}
+
+func TestMinikube_HostGateway(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		status  int
+		want    string
+		wantErr bool
+	}{
+		{"found", "192.168.64.1\thost.minikube.internal\n", 0, "192.168.64.1", false},
+		{"not found", "", 1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.StartCommandLineTest()
+			defer testutils.StopCommandLineTest()
+			testutils.MockWithStdOut(tt.output, tt.status, "minikube", "-p", "app", "ssh", "--", "grep", "host.minikube.internal", "/etc/hosts")
+
+			got, err := NewMinikube(&minikube.Profile{Name: "app"}).HostGateway()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HostGateway() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMinikube_LoadImage(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	testutils.MockWithStdOut("", 0, "minikube", "-p", "app", "image", "load", "/bundle/images/nginx.tar")
+	testutils.MockWithStdOut("not running", 1, "minikube", "-p", "app", "image", "load", "/bundle/images/missing.tar")
+
+	m := NewMinikube(&minikube.Profile{Name: "app"})
+	assert.NoError(t, m.LoadImage("/bundle/images/nginx.tar"))
+	assert.Error(t, m.LoadImage("/bundle/images/missing.tar"))
+}
@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoadBalancerStrategy describes how services of type LoadBalancer become reachable from the host.
+type LoadBalancerStrategy string
+
+const (
+	// LoadBalancerTunnel requires the tunnel command of the provider to run as root on the host.
+	LoadBalancerTunnel LoadBalancerStrategy = "tunnel"
+	// LoadBalancerNone means that LoadBalancer services never get an external address.
+	LoadBalancerNone LoadBalancerStrategy = "none"
+)
+
+// Provider encapsulates everything that differs between the local kubernetes distributions.
+type Provider interface {
+	// String returns the name of the provider like "minikube" or "kind".
+	String() string
+	// ClusterName returns the name of the cluster within the provider, e.g. the minikube profile.
+	ClusterName() string
+	// NodeIPs returns the ip addresses of all cluster nodes. The address of the primary node comes first.
+	NodeIPs() ([]string, error)
+	// LoadBalancer returns the strategy to make LoadBalancer services reachable.
+	LoadBalancer() LoadBalancerStrategy
+	// TunnelCommand returns the command including its arguments that has to run for the
+	// LoadBalancerTunnel strategy. It returns nil for all other strategies.
+	TunnelCommand() []string
+	// HostGateway returns the address of the host as seen from the cluster nodes.
+	HostGateway() (string, error)
+	// LoadImage loads the images of the given image archive into the container runtime of all cluster nodes.
+	LoadImage(archive string) error
+}
+
+// Detect finds the provider of the cluster with the given kube context name and api server.
+// It returns nil if the cluster is not managed by a known provider.
+func Detect(contextName string, server string) (Provider, error) {
+	if strings.HasPrefix(contextName, kindContextPrefix) {
+		return NewKind(strings.TrimPrefix(contextName, kindContextPrefix)), nil
+	}
+
+	profile, e := findMinikubeProfile(contextName, server)
+	if e != nil {
+		return nil, fmt.Errorf("can not detect minikube cluster: %s", e)
+	}
+	if profile != nil {
+		return NewMinikube(profile), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,63 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func TestDetect(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	testutils.SetTestProcessResponse(testutils.TestProcessResponse{
+		Command: "minikube",
+		Args:    []string{"profile", "list", "-o", "json"},
+		Stdout:  `{"invalid":[],"valid":[{"Name":"app","Config":{"Nodes":[{"IP":"192.168.64.3"}]}}]}`,
+	})
+
+	tests := []struct {
+		name        string
+		contextName string
+		server      string
+		want        string
+		wantCluster string
+	}{
+		{"kind", "kind-dev", "https://127.0.0.1:40123", "kind", "dev"},
+		{"minikube by name", "app", "https://127.0.0.1:40123", "minikube", "app"},
+		{"minikube by server", "other", "https://192.168.64.3:8443", "minikube", "app"},
+		{"unknown", "other", "https://10.0.0.1:6443", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.contextName, tt.server)
+			assert.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got.String())
+			assert.Equal(t, tt.wantCluster, got.ClusterName())
+		})
+	}
+}
+
+func TestDetect_error(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	testutils.SetTestProcessResponse(testutils.TestProcessResponse{
+		Command:        "minikube",
+		Args:           []string{"profile", "list", "-o", "json"},
+		ResponseStatus: 1,
+	})
+
+	got, err := Detect("other", "https://10.0.0.1:6443")
+
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func TestHelperProcess(t *testing.T) {
+	testutils.StandardHelperProcess(t)
+}
@@ -0,0 +1,145 @@
+package kubernetes
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// configChangeDelay is the time to wait for further changes of the kubeconfig before reloading it.
+// Tools like kubectl or minikube write the file in several steps.
+var configChangeDelay = 500 * time.Millisecond
+
+// ConfigChangedListener is called after the context or cluster of a ContextHandler has changed.
+type ConfigChangedListener func()
+
+// ConfigWatcher watches the kubeconfig file for changes. After every change it
+// reloads the registered context handlers and informs their listeners if the
+// used context or cluster has changed. It only watches while there are listeners.
+type ConfigWatcher struct {
+	configFile *string
+	listeners  map[ContextHandler]map[*ConfigChangedListener]bool
+	watcher    *fsnotify.Watcher
+	timer      *time.Timer
+	mutex      sync.Mutex
+}
+
+// NewConfigWatcher initializes a new ConfigWatcher for the kubeconfig file given
+// by the config file flag.
+func NewConfigWatcher(configFile *string) *ConfigWatcher {
+	return &ConfigWatcher{
+		configFile: configFile,
+		listeners:  map[ContextHandler]map[*ConfigChangedListener]bool{},
+		mutex:      sync.Mutex{},
+	}
+}
+
+// AddListener registers the listener for changes of the given context handler.
+// It returns the function to remove the listener again.
+func (w *ConfigWatcher) AddListener(handler ContextHandler, listener ConfigChangedListener) (remove func()) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.watcher == nil {
+		w.start()
+	}
+	if _, ok := w.listeners[handler]; !ok {
+		w.listeners[handler] = map[*ConfigChangedListener]bool{}
+	}
+	w.listeners[handler][&listener] = true
+
+	return func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		delete(w.listeners[handler], &listener)
+		if len(w.listeners[handler]) == 0 {
+			delete(w.listeners, handler)
+		}
+		if len(w.listeners) == 0 {
+			w.stop()
+		}
+	}
+}
+
+// start begins to watch the directory of the kubeconfig file. The directory is
+// watched because the file is often replaced instead of written.
+// The caller must hold the mutex.
+func (w *ConfigWatcher) start() {
+	configFile := ConfigFilePath(*w.configFile)
+	watcher, e := fsnotify.NewWatcher()
+	if e != nil {
+		logrus.Warnf("Can not watch kubeconfig %s for changes: %s", configFile, e)
+		return
+	}
+	if e = watcher.Add(filepath.Dir(configFile)); e != nil {
+		logrus.Warnf("Can not watch kubeconfig %s for changes: %s", configFile, e)
+		_ = watcher.Close()
+		return
+	}
+	w.watcher = watcher
+	go w.watch(watcher, filepath.Clean(configFile))
+}
+
+// stop ends watching the kubeconfig file. The caller must hold the mutex.
+func (w *ConfigWatcher) stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	if w.watcher != nil {
+		_ = w.watcher.Close()
+		w.watcher = nil
+	}
+}
+
+func (w *ConfigWatcher) watch(watcher *fsnotify.Watcher, configFile string) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != configFile || event.Has(fsnotify.Chmod) {
+				continue
+			}
+			w.mutex.Lock()
+			if w.timer != nil {
+				w.timer.Stop()
+			}
+			w.timer = time.AfterFunc(configChangeDelay, w.reload)
+			w.mutex.Unlock()
+		case e, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logrus.Warnf("Error while watching kubeconfig: %s", e)
+		}
+	}
+}
+
+// reload reloads all context handlers and informs the listeners of the changed ones.
+func (w *ConfigWatcher) reload() {
+	w.mutex.Lock()
+	var listeners []ConfigChangedListener
+	for handler, handlerListeners := range w.listeners {
+		changed, e := handler.Reload()
+		if e != nil {
+			logrus.Warnf("Can not reload kubeconfig: %s", e)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		logrus.Infof("Kubernetes context changed to %s", handler.GetContextName())
+		for listener := range handlerListeners {
+			listeners = append(listeners, *listener)
+		}
+	}
+	w.mutex.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigWatcher_AddListener(t *testing.T) {
+	configChangeDelay = 10 * time.Millisecond
+	defer func() { configChangeDelay = 500 * time.Millisecond }()
+
+	configFile := copyConfig(t)
+	handler := NewContextHandler(&configFile, s(""))
+	_, e := handler.GetClientSet()
+	if !assert.NoError(t, e) {
+		return
+	}
+	watcher := NewConfigWatcher(&configFile)
+	changed := make(chan bool, 1)
+	remove := watcher.AddListener(handler, func() { changed <- true })
+
+	setCurrentContext(t, configFile, "test1")
+
+	select {
+	case <-changed:
+		assert.Equal(t, "test1", handler.GetContextName())
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "listener was not called")
+	}
+
+	remove()
+	assert.Empty(t, watcher.listeners)
+	assert.Nil(t, watcher.watcher)
+}
+
+func TestConfigWatcher_unchanged(t *testing.T) {
+	configChangeDelay = 10 * time.Millisecond
+	defer func() { configChangeDelay = 500 * time.Millisecond }()
+
+	configFile := copyConfig(t)
+	handler := NewContextHandler(&configFile, s(""))
+	_, e := handler.GetClientSet()
+	if !assert.NoError(t, e) {
+		return
+	}
+	watcher := NewConfigWatcher(&configFile)
+	changed := make(chan bool, 1)
+	remove := watcher.AddListener(handler, func() { changed <- true })
+	defer remove()
+
+	setCurrentContext(t, configFile, "test")
+
+	select {
+	case <-changed:
+		assert.Fail(t, "listener must not be called")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
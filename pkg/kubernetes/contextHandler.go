@@ -3,7 +3,6 @@ package kubernetes
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
 	"sync"
 
 	"k8s.io/client-go/dynamic"
@@ -12,6 +11,8 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 
+	"github.com/qaware/minikube-support/pkg/cluster"
+	"github.com/qaware/minikube-support/pkg/minikube"
 	"github.com/qaware/minikube-support/pkg/sh"
 )
 
@@ -24,6 +25,9 @@ type ContextHandler interface {
 	// GetDynamicClient gets the kubernetes dynamic client to access unknown custom resources.
 	GetDynamicClient() (dynamic.Interface, error)
 
+	// GetRestConfig gets the rest config for clients that need direct access to the api server like port forwards.
+	GetRestConfig() (*rest.Config, error)
+
 	// GetConfigFile gets the path to the configuration file.
 	GetConfigFile() string
 
@@ -35,6 +39,18 @@ type ContextHandler interface {
 
 	// IsMinikube returns true if the target of this context is a minikube instance. Otherwise false.
 	IsMinikube() (bool, error)
+
+	// GetMinikubeProfile returns the minikube profile of the used context or nil if the
+	// context does not target a minikube instance.
+	GetMinikubeProfile() (*minikube.Profile, error)
+
+	// GetClusterProvider returns the provider of the local cluster like minikube or kind
+	// or nil if the cluster is not managed by a known provider.
+	GetClusterProvider() (cluster.Provider, error)
+
+	// Reload reads the kubeconfig again. If the used context or its cluster has changed, all cached
+	// clients are replaced and it returns true. Otherwise false.
+	Reload() (bool, error)
 }
 
 type contextHandler struct {
@@ -47,7 +63,8 @@ type contextHandler struct {
 	contextNameMutex      sync.RWMutex
 	clientConfig          clientcmd.ClientConfig
 	restConfig            *rest.Config
-	minikube              *bool
+	clusterProvider       cluster.Provider
+	clusterDetected       bool
 }
 
 // NewContextHandler creates a new ContextHandler instance for the given config file and context name.
@@ -83,6 +100,20 @@ func (h *contextHandler) GetDynamicClient() (dynamic.Interface, error) {
 	return h.dynamicClient, nil
 }
 
+func (h *contextHandler) GetRestConfig() (*rest.Config, error) {
+	h.clientSetMutex.Lock()
+	defer h.clientSetMutex.Unlock()
+
+	if h.restConfig == nil {
+		e := h.openRestConfig()
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return h.restConfig, nil
+}
+
 func (h *contextHandler) GetConfigFile() string {
 	if h.configFile != nil {
 		return *h.configFile
@@ -122,32 +153,74 @@ func (h *contextHandler) Kubectl(command string, args ...string) (string, error)
 }
 
 func (h *contextHandler) IsMinikube() (bool, error) {
+	profile, e := h.GetMinikubeProfile()
+	if e != nil {
+		return false, e
+	}
+	return profile != nil, nil
+}
+
+func (h *contextHandler) GetMinikubeProfile() (*minikube.Profile, error) {
+	provider, e := h.GetClusterProvider()
+	if e != nil {
+		return nil, e
+	}
+	if m, ok := provider.(*cluster.Minikube); ok {
+		return m.Profile, nil
+	}
+	return nil, nil
+}
+
+func (h *contextHandler) GetClusterProvider() (cluster.Provider, error) {
 	h.clientSetMutex.Lock()
 	defer h.clientSetMutex.Unlock()
 
 	if h.restConfig == nil {
 		e := h.openRestConfig()
 		if e != nil {
-			return false, e
+			return nil, e
 		}
 	}
 
-	if h.minikube == nil {
-		ip, e := sh.RunCmd("minikube", "ip")
+	if !h.clusterDetected {
+		provider, e := cluster.Detect(h.GetContextName(), h.restConfig.Host)
 		if e != nil {
-			if sh.IsExitCode(e, 66) {
-				// minikube vm don't exists
-				state := false
-				h.minikube = &state
-				return false, nil
-			}
-			return false, e
+			return nil, e
 		}
-		ip = strings.Trim(ip, "\n\r\t ")
-		state := strings.Contains(h.restConfig.Host, ip)
-		h.minikube = &state
+		h.clusterProvider = provider
+		h.clusterDetected = true
+	}
+	return h.clusterProvider, nil
+}
+
+func (h *contextHandler) Reload() (bool, error) {
+	h.clientSetMutex.Lock()
+	defer h.clientSetMutex.Unlock()
+
+	if h.restConfig == nil {
+		// nothing loaded yet, so nothing can be outdated
+		return false, nil
+	}
+
+	oldContextName := h.GetContextName()
+	oldHost := h.restConfig.Host
+	if e := h.openRestConfig(); e != nil {
+		return false, e
+	}
+	if oldContextName == h.GetContextName() && oldHost == h.restConfig.Host {
+		return false, nil
+	}
+	h.clusterProvider = nil
+	h.clusterDetected = false
+	return true, nil
+}
+
+// ConfigFilePath returns the path of the kubeconfig file that is used for the given config file flag.
+func ConfigFilePath(configFile string) string {
+	if configFile != "" {
+		return configFile
 	}
-	return *h.minikube, nil
+	return filepath.Join(homedir.HomeDir(), ".kube", "config")
 }
 
 // openRestConfig opens the kubernetes configuration and creates a client set that can
@@ -162,9 +235,7 @@ func (h *contextHandler) openRestConfig() error {
 
 		// if not run in cluster try to use default from user home
 		if e == rest.ErrNotInCluster {
-			homeDir := homedir.HomeDir()
-			configPath := filepath.Join(homeDir, ".kube", "config")
-			config, e = h.loadConfig(configPath)
+			config, e = h.loadConfig(ConfigFilePath(configFile))
 		}
 
 		// Neither in cluster config nor user home config exists.
@@ -3,11 +3,14 @@ package kubernetes
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/client-go/rest"
 
+	"github.com/qaware/minikube-support/pkg/minikube"
 	"github.com/qaware/minikube-support/pkg/sh"
 	"github.com/qaware/minikube-support/pkg/testutils"
 )
@@ -213,17 +216,18 @@ func Test_contextHandler_IsMinikube(t *testing.T) {
 	tests := []struct {
 		name           string
 		restConfig     *rest.Config
-		ip             string
+		profiles       string
 		responseStatus int
 		want           bool
 		wantErr        bool
 	}{
-		{"yes and initialized", &rest.Config{Host: "192.168.64.2"}, "192.168.64.2\n", 0, true, false},
-		{"no and initialized", &rest.Config{}, "192.168.64.3", 0, false, false},
+		{"yes and initialized", &rest.Config{Host: "https://192.168.64.2:8443"}, profiles("other", "192.168.64.2"), 0, true, false},
+		{"no and initialized", &rest.Config{}, profiles("other", "192.168.64.3"), 0, false, false},
 		{"error and initialized", &rest.Config{}, "", 1, false, true},
 		{"uninitialized and no minikube vm", nil, "", 66, false, false},
-		{"no and uninitialized", nil, "192.168.64.3", 0, false, false},
-		{"yes and uninitialized", nil, "localhost\r\n", 0, true, false},
+		{"uninitialized and no minikube profile", nil, "", 85, false, false},
+		{"no and uninitialized", nil, profiles("minikube", "192.168.64.3"), 0, false, false},
+		{"yes and uninitialized", nil, profiles("test", "192.168.64.3"), 0, true, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -235,10 +239,9 @@ func Test_contextHandler_IsMinikube(t *testing.T) {
 
 			testutils.SetTestProcessResponse(testutils.TestProcessResponse{
 				Command:        "minikube",
-				Args:           []string{"ip"},
+				Args:           []string{"profile", "list", "-o", "json"},
 				ResponseStatus: tt.responseStatus,
-				Stdout:         tt.ip,
-				// Stderr:         "error",
+				Stdout:         tt.profiles,
 			})
 
 			h := NewContextHandler(s(""), nil).(*contextHandler)
@@ -253,6 +256,35 @@ func Test_contextHandler_IsMinikube(t *testing.T) {
 	}
 }
 
+func Test_contextHandler_GetMinikubeProfile(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+	testutils.SetTestProcessResponse(testutils.TestProcessResponse{
+		Command: "minikube",
+		Args:    []string{"profile", "list", "-o", "json"},
+		Stdout:  profiles("test", "192.168.64.3"),
+	})
+
+	h := NewContextHandler(s("valid-config_test.yaml"), nil)
+	got, err := h.GetMinikubeProfile()
+
+	assert.NoError(t, err)
+	assert.Equal(t, &minikube.Profile{
+		Name:              "test",
+		Status:            "Running",
+		Driver:            "hyperkit",
+		KubernetesVersion: "v1.28.3",
+		Nodes:             []minikube.Node{{IP: "192.168.64.3", ControlPlane: true}},
+	}, got)
+	assert.Equal(t, []string{"-p", "test", "ip"}, got.Args("ip"))
+}
+
+// profiles creates the output of `minikube profile list -o json` for a single profile.
+func profiles(name string, ip string) string {
+	return `{"invalid":[],"valid":[{"Name":"` + name + `","Status":"Running","Config":{"Driver":"hyperkit",` +
+		`"KubernetesConfig":{"KubernetesVersion":"v1.28.3"},"Nodes":[{"Name":"","IP":"` + ip + `","ControlPlane":true}]}}]}`
+}
+
 func TestHelperProcess(t *testing.T) {
 	testutils.StandardHelperProcess(t)
 }
@@ -260,3 +292,61 @@ func TestHelperProcess(t *testing.T) {
 func s(str string) *string {
 	return &str
 }
+
+func Test_contextHandler_Reload(t *testing.T) {
+	configFile := copyConfig(t)
+	h := NewContextHandler(&configFile, s(""))
+
+	changed, e := h.Reload()
+	assert.NoError(t, e)
+	assert.False(t, changed, "nothing loaded yet")
+
+	_, e = h.GetClientSet()
+	assert.NoError(t, e)
+	changed, e = h.Reload()
+	assert.NoError(t, e)
+	assert.False(t, changed, "config not changed")
+
+	setCurrentContext(t, configFile, "test1")
+	changed, e = h.Reload()
+	assert.NoError(t, e)
+	assert.True(t, changed)
+	assert.Equal(t, "test1", h.GetContextName())
+}
+
+// copyConfig copies the valid test config into a temporary directory and returns its path.
+func copyConfig(t *testing.T) string {
+	content, e := os.ReadFile("valid-config_test.yaml")
+	if e != nil {
+		t.Fatal(e)
+	}
+	configFile := filepath.Join(t.TempDir(), "config")
+	if e = os.WriteFile(configFile, content, 0600); e != nil {
+		t.Fatal(e)
+	}
+	return configFile
+}
+
+// setCurrentContext replaces the current context of the given test config.
+func setCurrentContext(t *testing.T, configFile string, context string) {
+	content, e := os.ReadFile(configFile)
+	if e != nil {
+		t.Fatal(e)
+	}
+	content = regexp.MustCompile(`current-context: .*`).ReplaceAll(content, []byte(`current-context: "`+context+`"`))
+	if e = os.WriteFile(configFile, content, 0600); e != nil {
+		t.Fatal(e)
+	}
+}
+
+func Test_contextHandler_GetRestConfig(t *testing.T) {
+	h := NewContextHandler(s("valid-config_test.yaml"), s(""))
+	got, err := h.GetRestConfig()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://localhost:6443", got.Host)
+
+	h = NewContextHandler(s("invalid-config_test.yaml"), s(""))
+	_, err = h.GetRestConfig()
+	assert.Error(t, err)
+}
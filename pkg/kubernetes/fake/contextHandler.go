@@ -3,21 +3,28 @@ package fake
 import (
 	"fmt"
 
+	"github.com/qaware/minikube-support/pkg/cluster"
+	"github.com/qaware/minikube-support/pkg/minikube"
 	"github.com/qaware/minikube-support/pkg/testutils"
 	"k8s.io/client-go/dynamic"
 	dyntestclient "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 )
 
 // ContextHandler is a simple context handler for unit tests.
 type ContextHandler struct {
 	ClientSet        *testclient.Clientset
 	DynamicClient    *dyntestclient.FakeDynamicClient
+	RestConfig       *rest.Config
 	ConfigFile       string
 	ContextName      string
 	kubectlResponses []testutils.TestProcessResponse
 	MiniKube         bool
+	Profile          *minikube.Profile
+	Provider         cluster.Provider
+	Changed          bool
 }
 
 // NewContextHandler initializes a new ContextHandler instance for unit tests.
@@ -43,6 +50,13 @@ func (f *ContextHandler) GetDynamicClient() (dynamic.Interface, error) {
 	return f.DynamicClient, nil
 }
 
+func (f *ContextHandler) GetRestConfig() (*rest.Config, error) {
+	if f.RestConfig == nil {
+		return nil, fmt.Errorf("no rest config")
+	}
+	return f.RestConfig, nil
+}
+
 func (f *ContextHandler) GetConfigFile() string {
 	return f.ConfigFile
 }
@@ -72,3 +86,29 @@ func (f *ContextHandler) MockKubectl(command string, args []string, response str
 func (f *ContextHandler) IsMinikube() (bool, error) {
 	return f.MiniKube, nil
 }
+
+// GetMinikubeProfile returns Profile. If it is not set but MiniKube is true, the default profile "minikube" is returned.
+func (f *ContextHandler) GetMinikubeProfile() (*minikube.Profile, error) {
+	if f.Profile == nil && f.MiniKube {
+		return &minikube.Profile{Name: "minikube"}, nil
+	}
+	return f.Profile, nil
+}
+
+// GetClusterProvider returns Provider. If it is not set but the context is minikube, the minikube provider
+// of GetMinikubeProfile is returned.
+func (f *ContextHandler) GetClusterProvider() (cluster.Provider, error) {
+	if f.Provider != nil {
+		return f.Provider, nil
+	}
+	if profile, _ := f.GetMinikubeProfile(); profile != nil {
+		return cluster.NewMinikube(profile), nil
+	}
+	return nil, nil
+}
+
+func (f *ContextHandler) Reload() (bool, error) {
+	changed := f.Changed
+	f.Changed = false
+	return changed, nil
+}
@@ -0,0 +1,57 @@
+package fake
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dyntestclient "k8s.io/client-go/dynamic/fake"
+)
+
+// CRDResource is the resource of custom resource definitions in the dynamic client.
+var CRDResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// AvailableDeployment creates a deployment with the given labels which is rolled out and available.
+func AvailableDeployment(namespace string, name string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Status: appsv1.DeploymentStatus{
+			UpdatedReplicas:   1,
+			AvailableReplicas: 1,
+			Conditions:        []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+// EstablishedCRD creates an established custom resource definition with the given name.
+func EstablishedCRD(name string) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Established", "status": "True"}},
+		},
+	}}
+	crd.SetGroupVersionKind(CRDResource.GroupVersion().WithKind("CustomResourceDefinition"))
+	crd.SetName(name)
+	return crd
+}
+
+// ReadyEndpointSlice creates an endpoint slice with a ready endpoint for the given service.
+func ReadyEndpointSlice(namespace string, service string) *discoveryv1.EndpointSlice {
+	ready := true
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: service + "-1", Labels: map[string]string{discoveryv1.LabelServiceName: service}},
+		Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+	}
+}
+
+// NewDynamicClient creates a fake dynamic client which can list the given resources.
+func NewDynamicClient(listKinds map[schema.GroupVersionResource]string, objects ...runtime.Object) *dyntestclient.FakeDynamicClient {
+	kinds := map[schema.GroupVersionResource]string{CRDResource: "CustomResourceDefinitionList"}
+	for resource, kind := range listKinds {
+		kinds[resource] = kind
+	}
+	return dyntestclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), kinds, objects...)
+}
@@ -78,19 +78,18 @@ func (mr *MockWatchHandlerMockRecorder) PostEvent() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostEvent", reflect.TypeOf((*MockWatchHandler)(nil).PostEvent))
 }
 
-// PreWatch mocks base method.
-func (m *MockWatchHandler) PreWatch(options v1.ListOptions) (watch.Interface, error) {
+// Reconcile mocks base method.
+func (m *MockWatchHandler) Reconcile(objects []runtime.Object) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PreWatch", options)
-	ret0, _ := ret[0].(watch.Interface)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Reconcile", objects)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// PreWatch indicates an expected call of PreWatch.
-func (mr *MockWatchHandlerMockRecorder) PreWatch(options interface{}) *gomock.Call {
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockWatchHandlerMockRecorder) Reconcile(objects interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreWatch", reflect.TypeOf((*MockWatchHandler)(nil).PreWatch), options)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockWatchHandler)(nil).Reconcile), objects)
 }
 
 // UpdatedEvent mocks base method.
@@ -106,3 +105,84 @@ func (mr *MockWatchHandlerMockRecorder) UpdatedEvent(obj interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatedEvent", reflect.TypeOf((*MockWatchHandler)(nil).UpdatedEvent), obj)
 }
+
+// MockSource is a mock of Source interface.
+type MockSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockSourceMockRecorder
+}
+
+// MockSourceMockRecorder is the mock recorder for MockSource.
+type MockSourceMockRecorder struct {
+	mock *MockSource
+}
+
+// NewMockSource creates a new mock instance.
+func NewMockSource(ctrl *gomock.Controller) *MockSource {
+	mock := &MockSource{ctrl: ctrl}
+	mock.recorder = &MockSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSource) EXPECT() *MockSourceMockRecorder {
+	return m.recorder
+}
+
+// Key mocks base method.
+func (m *MockSource) Key() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Key")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Key indicates an expected call of Key.
+func (mr *MockSourceMockRecorder) Key() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Key", reflect.TypeOf((*MockSource)(nil).Key))
+}
+
+// List mocks base method.
+func (m *MockSource) List(options v1.ListOptions) (runtime.Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", options)
+	ret0, _ := ret[0].(runtime.Object)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSourceMockRecorder) List(options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSource)(nil).List), options)
+}
+
+// Object mocks base method.
+func (m *MockSource) Object() runtime.Object {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Object")
+	ret0, _ := ret[0].(runtime.Object)
+	return ret0
+}
+
+// Object indicates an expected call of Object.
+func (mr *MockSourceMockRecorder) Object() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Object", reflect.TypeOf((*MockSource)(nil).Object))
+}
+
+// Watch mocks base method.
+func (m *MockSource) Watch(options v1.ListOptions) (watch.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", options)
+	ret0, _ := ret[0].(watch.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockSourceMockRecorder) Watch(options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockSource)(nil).Watch), options)
+}
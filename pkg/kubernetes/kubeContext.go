@@ -0,0 +1,46 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KubeContext is a kubeconfig context that can be watched in parallel to other ones.
+type KubeContext struct {
+	// Name is the name of the context within the kubeconfig.
+	Name string
+	// Zone is the dns zone for all records of this context. For example "app.minikube".
+	Zone string
+}
+
+// KubeContextSupplier returns the list of kube contexts to watch. An empty list means only the default context.
+type KubeContextSupplier func() []KubeContext
+
+// dnsLabel matches a single valid dns label.
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ParseKubeContexts parses a list of context definitions in the form "<context>[=<zone label>]".
+// If no zone label is given the context name is used. The zone is always a subdomain of "minikube".
+func ParseKubeContexts(definitions []string) ([]KubeContext, error) {
+	var contexts []KubeContext
+	known := map[string]bool{}
+	for _, definition := range definitions {
+		name, label, found := strings.Cut(definition, "=")
+		if !found {
+			label = strings.ToLower(name)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("empty context name in %q", definition)
+		}
+		if !dnsLabel.MatchString(label) {
+			return nil, fmt.Errorf("%q is not a valid dns label for context %s. Use <context>=<label> to define one", label, name)
+		}
+		if known[label] {
+			return nil, fmt.Errorf("zone label %q is used for more than one context", label)
+		}
+		known[label] = true
+		contexts = append(contexts, KubeContext{Name: name, Zone: label + ".minikube"})
+	}
+	return contexts, nil
+}
@@ -0,0 +1,33 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKubeContexts(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions []string
+		want        []KubeContext
+		wantErr     bool
+	}{
+		{"none", nil, nil, false},
+		{"names", []string{"app", "Infra"}, []KubeContext{{"app", "app.minikube"}, {"Infra", "infra.minikube"}}, false},
+		{"explicit label", []string{"kind-dev=dev"}, []KubeContext{{"kind-dev", "dev.minikube"}}, false},
+		{"invalid label", []string{"arn:aws:eks:cluster/dev"}, nil, true},
+		{"empty name", []string{"=dev"}, nil, true},
+		{"duplicate label", []string{"a=dev", "b=dev"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKubeContexts(tt.definitions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseKubeContexts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
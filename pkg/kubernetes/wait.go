@@ -0,0 +1,169 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DefaultWaitTimeout is the default time to wait until objects of a freshly installed chart are ready.
+const DefaultWaitTimeout = 5 * time.Minute
+
+// waitInterval is the interval in which the state of the waited objects is checked.
+var waitInterval = 2 * time.Second
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// Waiter waits until objects in the cluster are ready. It is used by the cluster plugins before they apply
+// objects that depend on a freshly installed chart, like custom resources or objects validated by a webhook.
+type Waiter struct {
+	contextHandler ContextHandler
+	timeout        time.Duration
+}
+
+// readyCheck checks if the waited objects are ready. It returns a short description of the current state.
+type readyCheck func(ctx context.Context) (bool, string, error)
+
+// NewWaiter creates a new Waiter which fails if the objects are not ready within the given timeout.
+func NewWaiter(handler ContextHandler, timeout time.Duration) *Waiter {
+	return &Waiter{contextHandler: handler, timeout: timeout}
+}
+
+// Deployments waits until all deployments in the namespace matching the label selector are available.
+func (w *Waiter) Deployments(namespace string, selector string) error {
+	clientSet, e := w.contextHandler.GetClientSet()
+	if e != nil {
+		return fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	return w.wait(fmt.Sprintf("deployments %s in %s", selector, namespace), func(ctx context.Context) (bool, string, error) {
+		deployments, e := clientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if e != nil {
+			return false, "", fmt.Errorf("can not list deployments: %s", e)
+		}
+		if len(deployments.Items) == 0 {
+			return false, "no deployment found", nil
+		}
+		var waiting []string
+		for _, deployment := range deployments.Items {
+			if !isAvailable(&deployment) {
+				waiting = append(waiting, deployment.Name)
+			}
+		}
+		return len(waiting) == 0, progress(len(deployments.Items)-len(waiting), len(deployments.Items), "available", waiting), nil
+	})
+}
+
+// CRDs waits until the custom resource definitions with the given names are established.
+func (w *Waiter) CRDs(names ...string) error {
+	client, e := w.contextHandler.GetDynamicClient()
+	if e != nil {
+		return e
+	}
+	return w.wait("custom resource definitions", func(ctx context.Context) (bool, string, error) {
+		var waiting []string
+		for _, name := range names {
+			crd, e := client.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+			if e != nil || !hasCondition(crd, "Established") {
+				waiting = append(waiting, name)
+			}
+		}
+		return len(waiting) == 0, progress(len(names)-len(waiting), len(names), "established", waiting), nil
+	})
+}
+
+// Endpoints waits until the service has at least one ready endpoint. This ensures that webhooks served by the
+// service accept requests.
+func (w *Waiter) Endpoints(namespace string, service string) error {
+	clientSet, e := w.contextHandler.GetClientSet()
+	if e != nil {
+		return fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	return w.wait(fmt.Sprintf("endpoints of service %s/%s", namespace, service), func(ctx context.Context) (bool, string, error) {
+		slices, e := clientSet.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{LabelSelector: discoveryv1.LabelServiceName + "=" + service})
+		if e != nil {
+			return false, "", fmt.Errorf("can not list endpoints: %s", e)
+		}
+		for _, slice := range slices.Items {
+			for _, endpoint := range slice.Endpoints {
+				if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+					return true, "ready", nil
+				}
+			}
+		}
+		return false, "no ready endpoint", nil
+	})
+}
+
+// wait polls the check until it is ready or the timeout is reached. Every change of the state is logged as
+// progress. Errors of the check are treated as not ready, because the api may not be available yet.
+func (w *Waiter) wait(description string, check readyCheck) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	last := ""
+	e := wait.PollUntilContextCancel(ctx, waitInterval, true, func(ctx context.Context) (bool, error) {
+		ready, state, e := check(ctx)
+		if e != nil {
+			state = e.Error()
+		}
+		if !ready && state != last {
+			logrus.Infof("Waiting for %s: %s", description, state)
+		}
+		last = state
+		return ready, nil
+	})
+	if e != nil {
+		return fmt.Errorf("%s not ready within %s: %s", description, w.timeout, last)
+	}
+	logrus.Debugf("%s ready", description)
+	return nil
+}
+
+func progress(ready int, total int, state string, waiting []string) string {
+	result := fmt.Sprintf("%d/%d %s", ready, total, state)
+	if len(waiting) > 0 {
+		result += " (waiting for " + strings.Join(waiting, ", ") + ")"
+	}
+	return result
+}
+
+// isAvailable checks if the current generation of the deployment is rolled out and available.
+func isAvailable(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	if deployment.Status.UpdatedReplicas < replicas || deployment.Status.AvailableReplicas < replicas {
+		return false
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// hasCondition checks if the unstructured object has the given status condition set to true.
+func hasCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		if values, ok := c.(map[string]interface{}); ok && values["type"] == conditionType {
+			return values["status"] == "True"
+		}
+	}
+	return false
+}
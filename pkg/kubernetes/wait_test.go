@@ -0,0 +1,95 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func init() {
+	waitInterval = 10 * time.Millisecond
+}
+
+func TestWaiter_Deployments(t *testing.T) {
+	labels := map[string]string{"app.kubernetes.io/instance": "test"}
+	unavailable := fake.AvailableDeployment("mks", "webhook", labels)
+	unavailable.Status.AvailableReplicas = 0
+	outdated := fake.AvailableDeployment("mks", "outdated", labels)
+	outdated.Generation = 2
+	outdated.Status.ObservedGeneration = 1
+
+	tests := []struct {
+		name        string
+		deployments []*appsv1.Deployment
+		wantErr     string
+	}{
+		{"available", []*appsv1.Deployment{fake.AvailableDeployment("mks", "controller", labels)}, ""},
+		{"no deployment", nil, "deployments app.kubernetes.io/instance=test in mks not ready within 50ms: no deployment found"},
+		{"unavailable", []*appsv1.Deployment{fake.AvailableDeployment("mks", "controller", labels), unavailable, outdated},
+			"deployments app.kubernetes.io/instance=test in mks not ready within 50ms: 1/3 available (waiting for outdated, webhook)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSet := k8sfake.NewClientset()
+			for _, deployment := range tt.deployments {
+				_ = clientSet.Tracker().Add(deployment)
+			}
+			e := NewWaiter(fake.NewContextHandler(clientSet, nil), 50*time.Millisecond).Deployments("mks", "app.kubernetes.io/instance=test")
+			if tt.wantErr == "" {
+				assert.NoError(t, e)
+			} else {
+				assert.EqualError(t, e, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaiter_CRDs(t *testing.T) {
+	hook := test.NewGlobal()
+	client := fake.NewDynamicClient(nil, fake.EstablishedCRD("certificates.cert-manager.io"))
+	waiter := NewWaiter(fake.NewContextHandler(nil, client), 50*time.Millisecond)
+
+	assert.NoError(t, waiter.CRDs("certificates.cert-manager.io"))
+	assert.EqualError(t, waiter.CRDs("certificates.cert-manager.io", "issuers.cert-manager.io"),
+		"custom resource definitions not ready within 50ms: 1/2 established (waiting for issuers.cert-manager.io)")
+	testutils.CheckLogEntry(t, hook, "Waiting for custom resource definitions: 1/2 established")
+}
+
+func TestWaiter_Endpoints(t *testing.T) {
+	notReady := fake.ReadyEndpointSlice("mks", "webhook")
+	ready := false
+	notReady.Endpoints[0].Conditions.Ready = &ready
+
+	tests := []struct {
+		name    string
+		slices  []*discoveryv1.EndpointSlice
+		wantErr string
+	}{
+		{"ready", []*discoveryv1.EndpointSlice{fake.ReadyEndpointSlice("mks", "webhook")}, ""},
+		{"not ready", []*discoveryv1.EndpointSlice{notReady}, "endpoints of service mks/webhook not ready within 50ms: no ready endpoint"},
+		{"no endpoints", nil, "endpoints of service mks/webhook not ready within 50ms: no ready endpoint"},
+		{"other service", []*discoveryv1.EndpointSlice{fake.ReadyEndpointSlice("mks", "other")}, "endpoints of service mks/webhook not ready within 50ms: no ready endpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSet := k8sfake.NewClientset()
+			for _, slice := range tt.slices {
+				_ = clientSet.Tracker().Add(slice)
+			}
+			e := NewWaiter(fake.NewContextHandler(clientSet, nil), 50*time.Millisecond).Endpoints("mks", "webhook")
+			if tt.wantErr == "" {
+				assert.NoError(t, e)
+			} else {
+				assert.EqualError(t, e, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,32 +4,28 @@ package kubernetes
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
 )
 
-// Watcher is a small helper to handle to watch changes on kubernetes objects. It
-// reduces the need of error handling in the actual implementation.
-type Watcher struct {
-	// Resource Version is the minimum version to start the watch on.
-	ResourceVersion string
-	options         *metav1.ListOptions
-	handler         WatchHandler
-	watch           watch.Interface
-	mutex           sync.Mutex
-}
+// DefaultResyncPeriod is the default period after which all watchers get an update event for every known object.
+const DefaultResyncPeriod = 10 * time.Minute
+
+// syncTimeout is the maximum duration to wait until the initial list of a newly added watcher was processed.
+var syncTimeout = 30 * time.Second
 
 // WatchHandler is the interface that must be implemented by the application code
 // which requires watching on kubernetes resources.
 type WatchHandler interface {
-	// PreWatch is executed before the watch loop starts and must return the
-	// watch.Interface instance to watch for the events.
-	PreWatch(options metav1.ListOptions) (watch.Interface, error)
 	// AddedEvent handles the event when a new resource was added.
 	AddedEvent(obj runtime.Object) error
 	// UpdatedEvent handles the event when a resource was updated.
@@ -39,97 +35,309 @@ type WatchHandler interface {
 	// PostEvent is executed after every event if the event handling were executed
 	// without an error.
 	PostEvent() error
+	// Reconcile is executed after every relist of the resources, for example after
+	// the watch expired. It gets all currently existing objects and must only drop
+	// the state of objects that are missing. Added and changed objects are still
+	// reported as events.
+	Reconcile(objects []runtime.Object) error
 }
 
-// NewWatcher initializes a new Watcher instance and ensures the consistency
-// of the given parameters.
-func NewWatcher(handler WatchHandler, options *metav1.ListOptions, resourceVersion string) (*Watcher, error) {
+// Source provides list and watch access to one type of kubernetes objects.
+type Source interface {
+	// Object returns an empty instance of the accessed type.
+	Object() runtime.Object
+	// Key identifies the accessed objects including all filters applied by the source itself.
+	// Together with the type and the selectors it identifies the shared informer.
+	Key() string
+	// List returns a list object containing all objects that matches the given options.
+	List(options metav1.ListOptions) (runtime.Object, error)
+	// Watch starts the watch process for the objects that matches the given options.
+	Watch(options metav1.ListOptions) (watch.Interface, error)
+}
+
+// Informers holds one shared informer per source and selectors. This allows
+// multiple watchers on the same objects without listing and watching them twice.
+// An informer is started with the first watcher and stopped with the last one.
+// All sources of one Informers instance must access the same cluster.
+type Informers struct {
+	resyncPeriod time.Duration
+	informers    map[informerKey]*sharedInformer
+	mutex        sync.Mutex
+}
+
+// informerKey identifies a shared informer.
+type informerKey struct {
+	objectType    reflect.Type
+	source        string
+	labelSelector string
+	fieldSelector string
+}
+
+// sharedInformer is a single informer including all watchers using it.
+type sharedInformer struct {
+	informer cache.SharedIndexInformer
+	stop     chan struct{}
+	watchers map[*Watcher]bool
+	mutex    sync.RWMutex
+}
+
+// Watcher is a small helper to handle to watch changes on kubernetes objects. It
+// reduces the need of error handling in the actual implementation.
+type Watcher struct {
+	handler      WatchHandler
+	informers    *Informers
+	key          informerKey
+	registration cache.ResourceEventHandlerRegistration
+	stopped      bool
+	mutex        sync.Mutex
+}
+
+// NewInformers initializes a new Informers instance whose informers resync all
+// watchers after the given period.
+func NewInformers(resyncPeriod time.Duration) *Informers {
+	return &Informers{
+		resyncPeriod: resyncPeriod,
+		informers:    map[informerKey]*sharedInformer{},
+		mutex:        sync.Mutex{},
+	}
+}
+
+// Watch adds a new watcher for the objects of the given source. If there is no
+// informer for the source and the selectors of the options, it will be created
+// and started. Otherwise the existing informer is used. The call blocks until the
+// handler has received all currently existing objects.
+func (i *Informers) Watch(source Source, options *metav1.ListOptions, handler WatchHandler) (*Watcher, error) {
+	if source == nil {
+		return nil, errors.New("watch source is nil")
+	}
 	if handler == nil {
 		return nil, errors.New("watch handler is nil")
 	}
 	if options == nil {
 		options = &metav1.ListOptions{}
 	}
-	if resourceVersion == "" {
-		return nil, errors.New("resource version is empty. watcher can not be started without an resource version")
+
+	key := informerKey{
+		objectType:    reflect.TypeOf(source.Object()),
+		source:        source.Key(),
+		labelSelector: options.LabelSelector,
+		fieldSelector: options.FieldSelector,
+	}
+	w := &Watcher{handler: handler, informers: i, key: key, mutex: sync.Mutex{}}
+
+	i.mutex.Lock()
+	shared, ok := i.informers[key]
+	if !ok {
+		shared = i.newSharedInformer(source, *options)
+		i.informers[key] = shared
+	}
+	shared.mutex.Lock()
+	shared.watchers[w] = true
+	shared.mutex.Unlock()
+
+	registration, e := shared.informer.AddEventHandler(w)
+	if e != nil {
+		i.removeWatcher(key, w)
+		i.mutex.Unlock()
+		return nil, fmt.Errorf("can not add watcher: %s", e)
+	}
+	w.registration = registration
+	if !ok {
+		go shared.informer.Run(shared.stop)
+	}
+	i.mutex.Unlock()
+
+	timeout := make(chan struct{})
+	timer := time.AfterFunc(syncTimeout, func() { close(timeout) })
+	defer timer.Stop()
+	if !cache.WaitForCacheSync(timeout, registration.HasSynced) {
+		w.Stop()
+		return nil, fmt.Errorf("can not sync %s within %s", key.source, syncTimeout)
 	}
-	return &Watcher{
-		ResourceVersion: resourceVersion,
-		options:         options,
-		handler:         handler,
-		mutex:           sync.Mutex{},
-	}, nil
+	w.postEvent()
+	return w, nil
 }
 
-// Start starts the watcher. It will immediately return.
-func (h *Watcher) Start() {
-	go h.run()
+// newSharedInformer creates a new shared informer for the given source.
+func (i *Informers) newSharedInformer(source Source, options metav1.ListOptions) *sharedInformer {
+	shared := &sharedInformer{
+		stop:     make(chan struct{}),
+		watchers: map[*Watcher]bool{},
+		mutex:    sync.RWMutex{},
+	}
+	lw := &listWatch{source: source, options: options, relisted: shared.reconcile}
+	shared.informer = cache.NewSharedIndexInformer(lw, source.Object(), i.resyncPeriod, cache.Indexers{})
+	return shared
 }
 
-// Stop ends the watcher loop.
-func (h *Watcher) Stop() {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
-	if h.watch != nil {
-		h.watch.Stop()
+// removeWatcher removes the watcher from the shared informer and stops it if it was the last one.
+// The caller must hold the mutex.
+func (i *Informers) removeWatcher(key informerKey, w *Watcher) {
+	shared, ok := i.informers[key]
+	if !ok {
+		return
+	}
+	shared.mutex.Lock()
+	delete(shared.watchers, w)
+	empty := len(shared.watchers) == 0
+	shared.mutex.Unlock()
+
+	if empty {
+		close(shared.stop)
+		delete(i.informers, key)
 	}
 }
 
-func (h *Watcher) run() {
-	restartWatch := true
-	for restartWatch {
-		restartWatch = h.watcher()
+// reconcile passes the relisted objects to all watchers.
+func (s *sharedInformer) reconcile(objects []runtime.Object) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for w := range s.watchers {
+		w.reconcile(objects)
 	}
 }
 
-func (h *Watcher) watcher() bool {
-	h.mutex.Lock()
-	options := h.options.DeepCopy()
-	options.ResourceVersion = h.ResourceVersion
+// Stop removes the watcher from the informer. The informer itself is only stopped
+// if there are no other watchers left. The handler will not be called anymore
+// after Stop returns.
+func (w *Watcher) Stop() {
+	w.mutex.Lock()
+	w.stopped = true
+	w.mutex.Unlock()
 
-	w, e := h.handler.PreWatch(*options)
-	if e != nil {
-		logrus.Errorf("Can not start watch: %s", e)
-		h.mutex.Unlock()
-		return false
-	}
-	h.watch = w
-	h.mutex.Unlock()
-	accessor := meta.NewAccessor()
-
-	for event := range w.ResultChan() {
-		var e error
-		switch event.Type {
-		case watch.Added:
-			e = h.handler.AddedEvent(event.Object)
-		case watch.Modified:
-			e = h.handler.UpdatedEvent(event.Object)
-		case watch.Deleted:
-			e = h.handler.DeletedEvent(event.Object)
-		case watch.Error:
-			h.watch.Stop()
-			logrus.Infof("Got Error Event: %v Restart watch now.", event.Object)
-			return true
-		default:
-			logrus.Infof("Received unhandled event %s for object %v", event.Type, event.Object)
-		}
+	w.informers.mutex.Lock()
+	defer w.informers.mutex.Unlock()
 
-		if e != nil {
-			logrus.Warnf("Can not handle %s event: %s", event.Type, e)
-			continue
+	if shared, ok := w.informers.informers[w.key]; ok && w.registration != nil {
+		if e := shared.informer.RemoveEventHandler(w.registration); e != nil {
+			logrus.Debugf("can not remove event handler: %s", e)
 		}
+	}
+	w.informers.removeWatcher(w.key, w)
+}
 
-		resourceVersion, e := accessor.ResourceVersion(event.Object)
-		if e != nil {
-			logrus.Debugf("can not extract resource version: %s", e)
-		} else {
-			h.ResourceVersion = resourceVersion
-		}
+// OnAdd implements cache.ResourceEventHandler.
+func (w *Watcher) OnAdd(obj interface{}, _ bool) {
+	w.handleEvent(watch.Added, obj, w.handler.AddedEvent)
+}
+
+// OnUpdate implements cache.ResourceEventHandler.
+func (w *Watcher) OnUpdate(_, newObj interface{}) {
+	w.handleEvent(watch.Modified, newObj, w.handler.UpdatedEvent)
+}
+
+// OnDelete implements cache.ResourceEventHandler.
+// It also handles the final state of objects whose deletion was missed while the watch was interrupted.
+func (w *Watcher) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	w.handleEvent(watch.Deleted, obj, w.handler.DeletedEvent)
+}
+
+func (w *Watcher) handleEvent(eventType watch.EventType, obj interface{}, handle func(runtime.Object) error) {
+	object, ok := obj.(runtime.Object)
+	if !ok {
+		logrus.Infof("Received unhandled %s event for object %v", eventType, obj)
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.stopped {
+		return
+	}
+	if e := handle(object); e != nil {
+		logrus.Warnf("Can not handle %s event: %s", eventType, e)
+		return
+	}
+	w.postEventLocked()
+}
+
+// reconcile passes all objects of a full list to the handler.
+func (w *Watcher) reconcile(objects []runtime.Object) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.stopped {
+		return
+	}
+	if e := w.handler.Reconcile(objects); e != nil {
+		logrus.Warnf("Can not reconcile objects: %s", e)
+		return
+	}
+	w.postEventLocked()
+}
 
-		e = h.handler.PostEvent()
-		if e != nil {
-			logrus.Info("Unable to handle post event function")
+func (w *Watcher) postEvent() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.postEventLocked()
+}
+
+func (w *Watcher) postEventLocked() {
+	if e := w.handler.PostEvent(); e != nil {
+		logrus.Info("Unable to handle post event function")
+	}
+}
+
+// listWatch is the cache.ListerWatcher for a Source. It adds the configured
+// selectors to every request and reports every relist once the reflector has
+// replaced the content of the informer with it.
+type listWatch struct {
+	source   Source
+	options  metav1.ListOptions
+	relisted func(objects []runtime.Object)
+	listed   bool
+	pending  []runtime.Object
+	mutex    sync.Mutex
+}
+
+func (lw *listWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	options.LabelSelector = lw.options.LabelSelector
+	options.FieldSelector = lw.options.FieldSelector
+	// Always list everything at once. Otherwise the reported objects would be incomplete.
+	options.Limit = 0
+	options.Continue = ""
+
+	list, e := lw.source.List(options)
+	if e != nil {
+		return nil, e
+	}
+	objects, e := meta.ExtractList(list)
+	if e != nil {
+		return nil, fmt.Errorf("can not extract list items: %s", e)
+	}
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+	// The initial list is reported as added events, only relists may have missed deletions.
+	if lw.listed {
+		lw.pending = objects
+		if objects == nil {
+			lw.pending = []runtime.Object{}
 		}
 	}
-	return false
+	lw.listed = true
+	return list, nil
+}
+
+// Watch starts the watch. The reflector only calls it after the listed objects
+// were passed to the informer, so a pending relist is reported now.
+func (lw *listWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	lw.mutex.Lock()
+	pending := lw.pending
+	lw.pending = nil
+	lw.mutex.Unlock()
+	if pending != nil {
+		lw.relisted(pending)
+	}
+
+	options.LabelSelector = lw.options.LabelSelector
+	options.FieldSelector = lw.options.FieldSelector
+	return lw.source.Watch(options)
+}
+
+// IsWatchListSemanticsUnSupported disables streaming lists for the reflector,
+// so every relist passes List() and can be reported to the watchers.
+func (lw *listWatch) IsWatchListSemanticsUnSupported() bool {
+	return true
 }
@@ -1,187 +1,228 @@
 package kubernetes
 
 import (
+	"context"
 	"errors"
-	"reflect"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
 )
 
-func TestNewWatcher(t *testing.T) {
+func TestInformers_Watch_invalid(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	tests := []struct {
-		name            string
-		handler         WatchHandler
-		options         *metav1.ListOptions
-		resourceVersion string
-		want            *Watcher
-		wantErr         bool
+		name    string
+		source  Source
+		handler WatchHandler
 	}{
-		{
-			"ok",
-			fake.NewMockWatchHandler(ctrl),
-			&metav1.ListOptions{},
-			"1",
-			&Watcher{"1", &metav1.ListOptions{}, fake.NewMockWatchHandler(ctrl), nil, sync.Mutex{}},
-			false,
-		},
-		{
-			"options nil",
-			fake.NewMockWatchHandler(ctrl),
-			nil,
-			"1",
-			&Watcher{"1", &metav1.ListOptions{}, fake.NewMockWatchHandler(ctrl), nil, sync.Mutex{}},
-			false,
-		},
-		{
-			"handler nil",
-			nil,
-			&metav1.ListOptions{},
-			"1",
-			nil,
-			true,
-		},
-		{
-			"resource empty",
-			fake.NewMockWatchHandler(ctrl),
-			&metav1.ListOptions{},
-			"",
-			nil,
-			true,
-		},
+		{"source nil", nil, fake.NewMockWatchHandler(ctrl)},
+		{"handler nil", fake.NewMockSource(ctrl), nil},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewWatcher(tt.handler, tt.options, tt.resourceVersion)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NewWatcher() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NewWatcher() got = %v, want %v", got, tt.want)
-			}
+			got, err := NewInformers(0).Watch(tt.source, nil, tt.handler)
+			assert.Error(t, err)
+			assert.Nil(t, got)
 		})
 	}
 }
 
-func TestWatcher_run(t *testing.T) {
-	obj := &unstructured.Unstructured{}
-	obj.SetResourceVersion("2")
+func TestInformers_Watch(t *testing.T) {
+	clientSet := k8sfake.NewClientset(createService("a"))
+	informers := NewInformers(0)
+	handler := &FakeWatchHandler{}
 
-	handler := &FakeWatchHandler{waiter: &sync.WaitGroup{}}
-	handler.waiter.Add(1)
-	watcher, _ := NewWatcher(handler, nil, "1")
+	watcher, e := informers.Watch(serviceSource{clientSet}, nil, handler)
+	if !assert.NoError(t, e) {
+		return
+	}
+	assert.Equal(t, []string{"AddedEvent:a", "PostEvent", "PostEvent"}, handler.GetExecutedFunc())
 
-	watcher.Start()
-	handler.waiter.Wait()
+	services := clientSet.CoreV1().Services("default")
+	_, _ = services.Create(context.Background(), createService("b"), metav1.CreateOptions{})
+	updated := createService("b")
+	updated.Labels = map[string]string{"changed": "true"}
+	_, _ = services.Update(context.Background(), updated, metav1.UpdateOptions{})
+	_ = services.Delete(context.Background(), "b", metav1.DeleteOptions{})
 
-	handler.watch.Add(obj)
-	handler.watch.Modify(obj)
+	assert.Eventually(t, func() bool { return len(handler.GetExecutedFunc()) == 9 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"AddedEvent:b", "PostEvent", "UpdatedEvent:b", "PostEvent", "DeletedEvent:b", "PostEvent"}, handler.GetExecutedFunc()[3:])
 
-	handler.waiter.Add(1)
-	handler.watch.Error(nil)
-	handler.waiter.Wait()
+	watcher.Stop()
+	assert.Empty(t, informers.informers)
+}
 
-	handler.watch.Delete(obj)
-	handler.AddPostError(errors.New(""))
-	handler.watch.Add(obj)
+func TestInformers_Watch_shared(t *testing.T) {
+	clientSet := k8sfake.NewClientset(createService("a"))
+	informers := NewInformers(0)
 
-	handler.AddError(errors.New(""))
-	handler.watch.Add(obj)
-	handler.watch.Modify(obj)
-	handler.watch.Delete(obj)
-	time.Sleep(10 * time.Millisecond)
-	watcher.Stop()
+	watcher1, e := informers.Watch(serviceSource{clientSet}, nil, &FakeWatchHandler{})
+	assert.NoError(t, e)
+	handler2 := &FakeWatchHandler{}
+	watcher2, e := informers.Watch(serviceSource{clientSet}, nil, handler2)
+	assert.NoError(t, e)
+
+	assert.Len(t, informers.informers, 1)
+	assert.Equal(t, []string{"AddedEvent:a", "PostEvent", "PostEvent"}, handler2.GetExecutedFunc())
+
+	watcher1.Stop()
+	assert.Len(t, informers.informers, 1)
+	watcher2.Stop()
+	assert.Empty(t, informers.informers)
+}
+
+func TestInformers_Watch_differentSelectors(t *testing.T) {
+	a := createService("a")
+	a.Labels = map[string]string{"app": "a"}
+	clientSet := k8sfake.NewClientset(a, createService("b"))
+	informers := NewInformers(0)
+
+	handler1 := &FakeWatchHandler{}
+	watcher1, e := informers.Watch(serviceSource{clientSet}, &metav1.ListOptions{LabelSelector: "app=a"}, handler1)
+	assert.NoError(t, e)
+	defer watcher1.Stop()
+	handler2 := &FakeWatchHandler{}
+	watcher2, e := informers.Watch(serviceSource{clientSet}, nil, handler2)
+	assert.NoError(t, e)
+	defer watcher2.Stop()
+
+	assert.Len(t, informers.informers, 2)
+	assert.Equal(t, []string{"AddedEvent:a", "PostEvent", "PostEvent"}, handler1.GetExecutedFunc())
+	assert.ElementsMatch(t, []string{"AddedEvent:a", "PostEvent", "AddedEvent:b", "PostEvent", "PostEvent"}, handler2.GetExecutedFunc())
+}
+
+func TestInformers_Watch_syncTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	syncTimeout = 100 * time.Millisecond
+	defer func() { syncTimeout = 30 * time.Second }()
+
+	source := fake.NewMockSource(ctrl)
+	source.EXPECT().Object().Return(&v1.Service{}).AnyTimes()
+	source.EXPECT().Key().Return("services").AnyTimes()
+	source.EXPECT().List(gomock.Any()).Return(nil, errors.New("dummy error")).AnyTimes()
+	informers := NewInformers(0)
 
-	expectedMethods := []string{"PreWatch",
-		"AddedEvent",
-		"PostEvent",
-		"UpdatedEvent",
-		"PostEvent",
-		"PreWatch",
-		"DeletedEvent",
-		"PostEvent",
-		"AddedEvent",
-		"PostEvent",
-		"AddedEvent",
-		"UpdatedEvent",
-		"DeletedEvent"}
-	if !reflect.DeepEqual(expectedMethods, handler.GetExecutedFunc()) {
-		t.Errorf("Wrong execution order:\nWant: %s\nGot:  %s", expectedMethods, handler.executedFunc)
+	got, e := informers.Watch(source, nil, &FakeWatchHandler{})
+
+	assert.Error(t, e)
+	assert.Nil(t, got)
+	assert.Empty(t, informers.informers)
+}
+
+func TestInformers_Watch_relistOnExpiredWatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeWatch := watch.NewFake()
+	source := fake.NewMockSource(ctrl)
+	source.EXPECT().Object().Return(&v1.Service{}).AnyTimes()
+	source.EXPECT().Key().Return("services").AnyTimes()
+	source.EXPECT().List(gomock.Any()).Return(&v1.ServiceList{Items: []v1.Service{*createService("a")}}, nil).Times(1)
+	source.EXPECT().List(gomock.Any()).Return(&v1.ServiceList{Items: []v1.Service{}}, nil).AnyTimes()
+	source.EXPECT().Watch(gomock.Any()).Return(fakeWatch, nil).Times(1)
+	source.EXPECT().Watch(gomock.Any()).Return(watch.NewFake(), nil).AnyTimes()
+	handler := &FakeWatchHandler{}
+
+	watcher, e := NewInformers(0).Watch(source, &metav1.ListOptions{LabelSelector: "app=a"}, handler)
+	if !assert.NoError(t, e) {
+		return
 	}
+	defer watcher.Stop()
+
+	fakeWatch.Error(&metav1.Status{Status: metav1.StatusFailure, Code: 410, Reason: metav1.StatusReasonExpired, Message: "too old resource version"})
+
+	assert.Eventually(t, func() bool {
+		executed := handler.GetExecutedFunc()
+		return len(executed) > 0 && contains(executed, "Reconcile:0") && contains(executed, "DeletedEvent:a")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NotContains(t, handler.GetExecutedFunc(), "Reconcile:1")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func createService(name string) *v1.Service {
+	return &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+}
+
+type serviceSource struct {
+	clientSet *k8sfake.Clientset
+}
+
+func (s serviceSource) Object() runtime.Object {
+	return &v1.Service{}
+}
+
+func (s serviceSource) Key() string {
+	return "services"
+}
+
+func (s serviceSource) List(options metav1.ListOptions) (runtime.Object, error) {
+	return s.clientSet.CoreV1().Services(v1.NamespaceAll).List(context.Background(), options)
+}
+
+func (s serviceSource) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return s.clientSet.CoreV1().Services(v1.NamespaceAll).Watch(context.Background(), options)
 }
 
 type FakeWatchHandler struct {
 	executedFunc []string
-	watch        *watch.FakeWatcher
-	error        error
-	postError    error
 	lock         sync.Mutex
-	waiter       *sync.WaitGroup
 }
 
-func (h *FakeWatchHandler) AddError(err error) {
-	h.lock.Lock()
-	defer h.lock.Unlock()
-	h.error = err
-}
-func (h *FakeWatchHandler) AddPostError(err error) {
+func (h *FakeWatchHandler) GetExecutedFunc() []string {
 	h.lock.Lock()
 	defer h.lock.Unlock()
-	h.postError = err
+	return append([]string{}, h.executedFunc...)
 }
 
-func (h *FakeWatchHandler) GetExecutedFunc() []string {
+func (h *FakeWatchHandler) record(function string, obj runtime.Object) error {
 	h.lock.Lock()
 	defer h.lock.Unlock()
-	return h.executedFunc
+	h.executedFunc = append(h.executedFunc, function+":"+obj.(*v1.Service).Name)
+	return nil
 }
 
-func (h *FakeWatchHandler) PreWatch(_ metav1.ListOptions) (watch.Interface, error) {
-	h.lock.Lock()
-	defer func() {
-		h.lock.Unlock()
-		h.waiter.Done()
-	}()
-	h.executedFunc = append(h.executedFunc, "PreWatch")
-	h.watch = watch.NewFake()
-	return h.watch, h.error
+func (h *FakeWatchHandler) AddedEvent(obj runtime.Object) error {
+	return h.record("AddedEvent", obj)
 }
 
-func (h *FakeWatchHandler) AddedEvent(_ runtime.Object) error {
-	h.lock.Lock()
-	defer h.lock.Unlock()
-	h.executedFunc = append(h.executedFunc, "AddedEvent")
-	return h.error
+func (h *FakeWatchHandler) UpdatedEvent(obj runtime.Object) error {
+	return h.record("UpdatedEvent", obj)
 }
 
-func (h *FakeWatchHandler) UpdatedEvent(_ runtime.Object) error {
-	h.lock.Lock()
-	defer h.lock.Unlock()
-	h.executedFunc = append(h.executedFunc, "UpdatedEvent")
-	return h.error
+func (h *FakeWatchHandler) DeletedEvent(obj runtime.Object) error {
+	return h.record("DeletedEvent", obj)
 }
 
-func (h *FakeWatchHandler) DeletedEvent(_ runtime.Object) error {
+func (h *FakeWatchHandler) PostEvent() error {
 	h.lock.Lock()
 	defer h.lock.Unlock()
-	h.executedFunc = append(h.executedFunc, "DeletedEvent")
-	return h.error
+	h.executedFunc = append(h.executedFunc, "PostEvent")
+	return nil
 }
 
-func (h *FakeWatchHandler) PostEvent() error {
+func (h *FakeWatchHandler) Reconcile(objects []runtime.Object) error {
 	h.lock.Lock()
 	defer h.lock.Unlock()
-	h.executedFunc = append(h.executedFunc, "PostEvent")
-	return h.postError
+	h.executedFunc = append(h.executedFunc, "Reconcile:"+string(rune('0'+len(objects))))
+	return nil
 }
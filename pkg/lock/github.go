@@ -0,0 +1,23 @@
+package lock
+
+import (
+	"github.com/qaware/minikube-support/pkg/github"
+)
+
+// githubClient downloads the locked GitHub releases.
+type githubClient struct {
+	github.Client
+	lock *Lock
+}
+
+// NewGithubClient creates a GitHub client which returns the locked release tag instead of the latest one and
+// records the latest tags in the lock.
+func NewGithubClient(client github.Client, lock *Lock) github.Client {
+	return &githubClient{Client: client, lock: lock}
+}
+
+func (c *githubClient) GetLatestReleaseTag(org string, repository string) (string, error) {
+	return c.lock.Release(org+"/"+repository, func() (string, error) {
+		return c.Client.GetLatestReleaseTag(org, repository)
+	})
+}
@@ -0,0 +1,25 @@
+package lock
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/github/fake"
+)
+
+func Test_githubClient_GetLatestReleaseTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := fake.NewMockClient(ctrl)
+	client.EXPECT().GetLatestReleaseTag("coredns", "coredns").Return("v1.12.0", nil)
+
+	tag, e := NewGithubClient(client, loadTestLock(t, Honour, false)).GetLatestReleaseTag("coredns", "coredns")
+	assert.NoError(t, e)
+	assert.Equal(t, "v1.11.1", tag)
+
+	tag, e = NewGithubClient(client, loadTestLock(t, Refresh, false)).GetLatestReleaseTag("coredns", "coredns")
+	assert.NoError(t, e)
+	assert.Equal(t, "v1.12.0", tag)
+}
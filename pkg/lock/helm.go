@@ -0,0 +1,41 @@
+package lock
+
+import (
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+)
+
+// helmManager installs the chart versions of the lock.
+type helmManager struct {
+	helm.Manager
+	lock *Lock
+}
+
+// NewHelmManager creates a helm manager which installs the locked chart versions and records the installed
+// versions in the lock.
+func NewHelmManager(manager helm.Manager, lock *Lock) helm.Manager {
+	return &helmManager{Manager: manager, lock: lock}
+}
+
+// ResolveChart returns the locked version of the chart if the lock is honoured. Otherwise the version matching
+// the constraint is resolved and recorded in the lock.
+func (m *helmManager) ResolveChart(chart string, version string) (*helm.ChartVersion, error) {
+	locked, e := m.lock.Chart(chart, version, func(version string) (Chart, error) {
+		resolved, e := m.Manager.ResolveChart(chart, version)
+		if e != nil {
+			return Chart{}, e
+		}
+		return Chart{Version: resolved.Version, AppVersion: resolved.AppVersion}, nil
+	})
+	if e != nil {
+		return nil, e
+	}
+	return &helm.ChartVersion{Chart: chart, Version: locked.Version, AppVersion: locked.AppVersion}, nil
+}
+
+func (m *helmManager) Install(chart string, version string, release string, namespace string, values map[string]interface{}, wait bool) error {
+	resolved, e := m.ResolveChart(chart, version)
+	if e != nil {
+		return &helm.ReleaseError{Action: "install", Release: release, Namespace: namespace, Err: e}
+	}
+	return m.Manager.Install(chart, resolved.Version, release, namespace, values, wait)
+}
@@ -0,0 +1,49 @@
+package lock
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	helmFake "github.com/qaware/minikube-support/pkg/packagemanager/helm/fake"
+)
+
+func Test_helmManager_Install(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        Mode
+		frozen      bool
+		wantResolve string
+		wantInstall string
+	}{
+		{"honour", Honour, false, "v1.14.5", "v1.14.5"},
+		{"refresh", Refresh, false, "", "v1.15.0"},
+		{"frozen drift", Refresh, true, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			manager := helmFake.NewMockManager(ctrl)
+			resolved := "v1.15.0"
+			if tt.wantResolve != "" {
+				resolved = tt.wantResolve
+			}
+			manager.EXPECT().ResolveChart("jetstack/cert-manager", tt.wantResolve).
+				Return(&helm.ChartVersion{Chart: "jetstack/cert-manager", Version: resolved, AppVersion: resolved}, nil)
+			if tt.wantInstall != "" {
+				manager.EXPECT().Install("jetstack/cert-manager", tt.wantInstall, "cert-manager", "mks", gomock.Any(), true)
+			}
+
+			e := NewHelmManager(manager, loadTestLock(t, tt.mode, tt.frozen)).Install("jetstack/cert-manager", "", "cert-manager", "mks", map[string]interface{}{}, true)
+
+			if tt.wantInstall == "" {
+				assert.ErrorContains(t, e, "chart jetstack/cert-manager drifted")
+			} else {
+				assert.NoError(t, e)
+			}
+		})
+	}
+}
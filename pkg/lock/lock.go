@@ -0,0 +1,207 @@
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// FileName is the default name of the lock file.
+const FileName = "minikube-support.lock"
+
+const header = "# Generated by minikube-support. Run `minikube-support update` to refresh the locked versions.\n"
+
+// Mode defines how the locked versions are used.
+type Mode int
+
+const (
+	// Honour installs the locked versions. Versions which are not locked yet are resolved and added to the lock file.
+	Honour Mode = iota
+	// Refresh resolves the latest versions and updates the lock file.
+	Refresh
+)
+
+// File is the content of the lock file.
+type File struct {
+	// Charts are the locked helm charts by their reference <repository>/<chart>.
+	Charts map[string]Chart `json:"charts,omitempty"`
+	// Releases are the locked tags of downloaded GitHub releases by <org>/<repository>.
+	Releases map[string]Release `json:"releases,omitempty"`
+}
+
+// Chart is a locked version of a helm chart.
+type Chart struct {
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+}
+
+// Release is a locked GitHub release.
+type Release struct {
+	Tag string `json:"tag"`
+}
+
+// Lock records the versions of the installed charts and downloaded releases, so that every install of the same
+// lock file results in the same versions.
+type Lock struct {
+	path    string
+	mode    Mode
+	frozen  bool
+	file    File
+	changed bool
+	errs    *multierror.Error
+	mutex   sync.Mutex
+}
+
+// New creates an empty lock. It is not read from or written to a file until Load is called.
+func New() *Lock {
+	return &Lock{file: newFile()}
+}
+
+// Load reads the lock file at the given path if it exists and sets the mode to use it. A frozen lock fails
+// on every version which is not locked or differs from the locked one instead of updating the lock file.
+func (l *Lock) Load(path string, mode Mode, frozen bool) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.path = path
+	l.mode = mode
+	l.frozen = frozen
+	l.file = newFile()
+	l.changed = false
+	l.errs = nil
+
+	bytes, e := os.ReadFile(path)
+	if errors.Is(e, os.ErrNotExist) {
+		if frozen {
+			return fmt.Errorf("lock file %s does not exist", path)
+		}
+		return nil
+	}
+	if e != nil {
+		return fmt.Errorf("can not read lock file %s: %s", path, e)
+	}
+	if e := yaml.Unmarshal(bytes, &l.file); e != nil {
+		return fmt.Errorf("can not parse lock file %s: %s", path, e)
+	}
+	if l.file.Charts == nil {
+		l.file.Charts = map[string]Chart{}
+	}
+	if l.file.Releases == nil {
+		l.file.Releases = map[string]Release{}
+	}
+	return nil
+}
+
+// Save writes the lock file if a version was added or changed.
+func (l *Lock) Save() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.changed || l.path == "" {
+		return nil
+	}
+	bytes, e := yaml.Marshal(l.file)
+	if e != nil {
+		return fmt.Errorf("can not write lock file %s: %s", l.path, e)
+	}
+	if e := os.WriteFile(l.path, append([]byte(header), bytes...), 0644); e != nil {
+		return fmt.Errorf("can not write lock file %s: %s", l.path, e)
+	}
+	l.changed = false
+	logrus.Infof("Lock file %s updated.", l.path)
+	return nil
+}
+
+// Err returns the drifts from a frozen lock file found since it was loaded.
+func (l *Lock) Err() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.errs.ErrorOrNil()
+}
+
+// Chart returns the version of the chart to install. The resolve function finds the chart version matching a
+// version constraint. It is called with the locked version if the lock is honoured and with the given
+// constraint otherwise.
+func (l *Lock) Chart(name string, constraint string, resolve func(version string) (Chart, error)) (Chart, error) {
+	l.mutex.Lock()
+	locked, found := l.file.Charts[name]
+	version := constraint
+	if found && l.mode == Honour {
+		version = locked.Version
+	}
+	l.mutex.Unlock()
+
+	resolved, e := resolve(version)
+	if e != nil {
+		return Chart{}, e
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if found && resolved == locked {
+		return resolved, nil
+	}
+	if e := l.drift("chart", name, found, fmt.Sprintf("%s (app %s)", locked.Version, locked.AppVersion), fmt.Sprintf("%s (app %s)", resolved.Version, resolved.AppVersion)); e != nil {
+		return Chart{}, e
+	}
+	l.file.Charts[name] = resolved
+	return resolved, nil
+}
+
+// Release returns the tag of the GitHub release to download. The latest function finds the tag of the latest
+// release. It is only called if the lock is refreshed or the release is not locked yet.
+func (l *Lock) Release(name string, latest func() (string, error)) (string, error) {
+	l.mutex.Lock()
+	locked, found := l.file.Releases[name]
+	if found && l.mode == Honour {
+		l.mutex.Unlock()
+		return locked.Tag, nil
+	}
+	l.mutex.Unlock()
+
+	tag, e := latest()
+	if e != nil {
+		return "", e
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if found && tag == locked.Tag {
+		return tag, nil
+	}
+	if e := l.drift("release", name, found, locked.Tag, tag); e != nil {
+		return "", e
+	}
+	l.file.Releases[name] = Release{Tag: tag}
+	return tag, nil
+}
+
+// drift records the changed version. For a frozen lock an error is returned instead. The caller must hold the mutex.
+func (l *Lock) drift(kind string, name string, found bool, locked string, resolved string) error {
+	var e error
+	switch {
+	case l.frozen && !found:
+		e = fmt.Errorf("%s %s is not locked", kind, name)
+	case l.frozen:
+		e = fmt.Errorf("%s %s drifted from the locked version %s to %s", kind, name, locked, resolved)
+	case found:
+		logrus.Infof("Update locked %s %s from %s to %s.", kind, name, locked, resolved)
+	default:
+		logrus.Debugf("Lock %s %s at %s.", kind, name, resolved)
+	}
+	if e != nil {
+		l.errs = multierror.Append(l.errs, e)
+		return e
+	}
+	l.changed = true
+	return nil
+}
+
+func newFile() File {
+	return File{Charts: map[string]Chart{}, Releases: map[string]Release{}}
+}
@@ -0,0 +1,169 @@
+package lock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockFile = `charts:
+  jetstack/cert-manager:
+    appVersion: v1.14.5
+    version: v1.14.5
+releases:
+  coredns/coredns:
+    tag: v1.11.1
+`
+
+func TestLock_Chart(t *testing.T) {
+	latest := Chart{Version: "v1.15.0", AppVersion: "v1.15.0"}
+	locked := Chart{Version: "v1.14.5", AppVersion: "v1.14.5"}
+	tests := []struct {
+		name        string
+		chart       string
+		mode        Mode
+		frozen      bool
+		want        Chart
+		wantVersion string
+		wantErr     bool
+		wantChanged bool
+	}{
+		{"honour locked", "jetstack/cert-manager", Honour, false, locked, "v1.14.5", false, false},
+		{"honour not locked", "ingress-nginx/ingress-nginx", Honour, false, latest, "", false, true},
+		{"refresh", "jetstack/cert-manager", Refresh, false, latest, "", false, true},
+		{"frozen locked", "jetstack/cert-manager", Honour, true, locked, "v1.14.5", false, false},
+		{"frozen not locked", "ingress-nginx/ingress-nginx", Honour, true, Chart{}, "", true, false},
+		{"frozen drift", "jetstack/cert-manager", Refresh, true, Chart{}, "", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := loadTestLock(t, tt.mode, tt.frozen)
+			got, err := l.Chart(tt.chart, "", func(version string) (Chart, error) {
+				assert.Equal(t, tt.wantVersion, version)
+				if version == locked.Version {
+					return locked, nil
+				}
+				return latest, nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Chart() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantChanged, l.changed)
+			assert.Equal(t, tt.wantErr, l.Err() != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, l.file.Charts[tt.chart])
+			}
+		})
+	}
+}
+
+func TestLock_Chart_resolveError(t *testing.T) {
+	l := loadTestLock(t, Honour, false)
+	_, e := l.Chart("jetstack/cert-manager", "", func(string) (Chart, error) {
+		return Chart{}, fmt.Errorf("not found")
+	})
+	assert.Error(t, e)
+	assert.NoError(t, l.Err())
+}
+
+func TestLock_Release(t *testing.T) {
+	tests := []struct {
+		name        string
+		release     string
+		mode        Mode
+		frozen      bool
+		want        string
+		wantLatest  bool
+		wantErr     bool
+		wantChanged bool
+	}{
+		{"honour locked", "coredns/coredns", Honour, false, "v1.11.1", false, false, false},
+		{"honour not locked", "other/other", Honour, false, "v1.12.0", true, false, true},
+		{"refresh", "coredns/coredns", Refresh, false, "v1.12.0", true, false, true},
+		{"frozen locked", "coredns/coredns", Honour, true, "v1.11.1", false, false, false},
+		{"frozen drift", "coredns/coredns", Refresh, true, "", true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := loadTestLock(t, tt.mode, tt.frozen)
+			latestCalled := false
+			got, err := l.Release(tt.release, func() (string, error) {
+				latestCalled = true
+				return "v1.12.0", nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Release() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantLatest, latestCalled)
+			assert.Equal(t, tt.wantChanged, l.changed)
+		})
+	}
+}
+
+func TestLock_Load(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		frozen  bool
+		wantErr bool
+	}{
+		{"valid", testLockFile, false, false},
+		{"empty", "", false, false},
+		{"invalid", "charts: [", false, true},
+		{"missing", "-", false, false},
+		{"missing frozen", "-", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".lock")
+			if tt.content != "-" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+			}
+			l := New()
+			if err := l.Load(path, Honour, tt.frozen); (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.NotNil(t, l.file.Charts)
+			assert.NotNil(t, l.file.Releases)
+		})
+	}
+}
+
+func TestLock_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	l := New()
+	require.NoError(t, l.Load(path, Refresh, false))
+
+	require.NoError(t, l.Save())
+	assert.NoFileExists(t, path, "unchanged lock must not be written")
+
+	_, e := l.Chart("jetstack/cert-manager", "", func(string) (Chart, error) {
+		return Chart{Version: "v1.14.5", AppVersion: "v1.14.5"}, nil
+	})
+	require.NoError(t, e)
+	_, e = l.Release("coredns/coredns", func() (string, error) { return "v1.11.1", nil })
+	require.NoError(t, e)
+	require.NoError(t, l.Save())
+
+	content, e := os.ReadFile(path)
+	require.NoError(t, e)
+	assert.Equal(t, header+testLockFile, string(content))
+}
+
+// loadTestLock loads the test lock file with the given mode.
+func loadTestLock(t *testing.T, mode Mode, frozen bool) *Lock {
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte(testLockFile), 0644))
+	l := New()
+	require.NoError(t, l.Load(path, mode, frozen))
+	return l
+}
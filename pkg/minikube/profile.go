@@ -0,0 +1,157 @@
+package minikube
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/qaware/minikube-support/pkg/sh"
+)
+
+// Exit codes of minikube which signal that there is no minikube cluster at all.
+const (
+	exitCodeNoHost    = 66
+	exitCodeNoProfile = 85
+)
+
+// Profile contains the metadata of one minikube profile.
+type Profile struct {
+	// Name is the name of the profile. It is also the name of the kube context created by minikube.
+	Name string
+	// Status is the state of the cluster like "Running" or "Stopped".
+	Status string
+	// Driver is the used minikube driver like "docker" or "hyperkit".
+	Driver string
+	// KubernetesVersion is the kubernetes version running in the cluster like "v1.28.3".
+	KubernetesVersion string
+	// Nodes are all nodes of the cluster.
+	Nodes []Node
+}
+
+// Node is a single node of a minikube profile.
+type Node struct {
+	// Name is the node name within the profile. It is empty for the primary node.
+	Name         string
+	IP           string
+	ControlPlane bool
+}
+
+// profileList is the output of `minikube profile list -o json`.
+type profileList struct {
+	Valid []struct {
+		Name   string
+		Status string
+		Config struct {
+			Driver           string
+			KubernetesConfig struct {
+				KubernetesVersion string
+			}
+			Nodes []Node
+		}
+	} `json:"valid"`
+}
+
+// ListProfiles returns all valid minikube profiles. If there is no profile an empty list will be returned.
+func ListProfiles() ([]Profile, error) {
+	output, e := sh.RunCmd("minikube", "profile", "list", "-o", "json")
+	if e != nil {
+		if errors.Is(e, exec.ErrNotFound) {
+			// minikube is not installed
+			return []Profile{}, nil
+		}
+		if sh.IsExitCode(e, exitCodeNoHost) || sh.IsExitCode(e, exitCodeNoProfile) {
+			return []Profile{}, nil
+		}
+		return nil, fmt.Errorf("can not list minikube profiles: %s (%s)", e, output)
+	}
+	return parseProfiles(output)
+}
+
+// parseProfiles parses the json output of `minikube profile list`. Warnings which are
+// written before the actual json document are ignored.
+func parseProfiles(output string) ([]Profile, error) {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("can not find minikube profiles in output: %s", output)
+	}
+
+	list := profileList{}
+	if e := json.Unmarshal([]byte(output[start:end+1]), &list); e != nil {
+		return nil, fmt.Errorf("can not parse minikube profiles: %s", e)
+	}
+
+	profiles := make([]Profile, 0, len(list.Valid))
+	for _, valid := range list.Valid {
+		profiles = append(profiles, Profile{
+			Name:              valid.Name,
+			Status:            valid.Status,
+			Driver:            valid.Config.Driver,
+			KubernetesVersion: valid.Config.KubernetesConfig.KubernetesVersion,
+			Nodes:             valid.Config.Nodes,
+		})
+	}
+	return profiles, nil
+}
+
+// FindProfile returns the profile that belongs to the given kube context. A profile
+// matches if it has the same name as the context or if one of its nodes is the
+// server of the context. It returns nil if no profile matches.
+func FindProfile(profiles []Profile, contextName string, server string) *Profile {
+	for i := range profiles {
+		if profiles[i].Name == contextName {
+			return &profiles[i]
+		}
+	}
+
+	host := server
+	if u, e := url.Parse(server); e == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	} else if h, _, e := net.SplitHostPort(server); e == nil {
+		host = h
+	}
+	for i := range profiles {
+		for _, ip := range profiles[i].NodeIPs() {
+			if ip == host {
+				return &profiles[i]
+			}
+		}
+	}
+	return nil
+}
+
+// NodeIPs returns the ip addresses of all nodes. The address of the primary node comes first.
+func (p *Profile) NodeIPs() []string {
+	ips := make([]string, 0, len(p.Nodes))
+	for _, node := range p.Nodes {
+		if node.IP != "" {
+			ips = append(ips, node.IP)
+		}
+	}
+	return ips
+}
+
+// NodeNames returns the names of all nodes as used by `minikube ssh -n`. The primary node comes first.
+func (p *Profile) NodeNames() []string {
+	if len(p.Nodes) == 0 {
+		return []string{p.Name}
+	}
+	names := make([]string, 0, len(p.Nodes))
+	for _, node := range p.Nodes {
+		if node.Name == "" {
+			names = append(names, p.Name)
+		} else {
+			names = append(names, node.Name)
+		}
+	}
+	return names
+}
+
+// Args prefixes the given minikube arguments with the flag selecting this profile.
+func (p *Profile) Args(args ...string) []string {
+	return append([]string{"-p", p.Name}, args...)
+}
@@ -0,0 +1,82 @@
+package minikube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []Profile
+		wantErr bool
+	}{
+		{"empty", `{"invalid":[],"valid":[]}`, []Profile{}, false},
+		{
+			"with warnings",
+			"! Found 1 invalid profile(s)\n" + `{"invalid":[{"Name":"broken"}],"valid":[{"Name":"app","Status":"Running","Config":{"Driver":"docker",` +
+				`"KubernetesConfig":{"KubernetesVersion":"v1.28.3"},"Nodes":[{"Name":"","IP":"192.168.49.2","ControlPlane":true},{"Name":"m02","IP":"192.168.49.3"}]}}]}`,
+			[]Profile{{
+				Name:              "app",
+				Status:            "Running",
+				Driver:            "docker",
+				KubernetesVersion: "v1.28.3",
+				Nodes:             []Node{{IP: "192.168.49.2", ControlPlane: true}, {Name: "m02", IP: "192.168.49.3"}},
+			}},
+			false,
+		},
+		{"no json", "minikube not found", nil, true},
+		{"invalid json", `{"valid":{}}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProfiles(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseProfiles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFindProfile(t *testing.T) {
+	profiles := []Profile{
+		{Name: "minikube", Nodes: []Node{{IP: "192.168.64.2"}}},
+		{Name: "app", Nodes: []Node{{IP: "192.168.64.3"}, {Name: "m02", IP: "192.168.64.4"}}},
+	}
+	tests := []struct {
+		name        string
+		contextName string
+		server      string
+		want        string
+	}{
+		{"by name", "app", "https://127.0.0.1:32771", "app"},
+		{"by server", "other", "https://192.168.64.2:8443", "minikube"},
+		{"by second node", "other", "192.168.64.4:8443", "app"},
+		{"no match", "other", "https://10.0.0.1:6443", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindProfile(profiles, tt.contextName, tt.server)
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got.Name)
+		})
+	}
+}
+
+func TestProfile_NodeIPs(t *testing.T) {
+	p := &Profile{Nodes: []Node{{IP: "192.168.64.3"}, {Name: "m02"}, {Name: "m03", IP: "192.168.64.5"}}}
+	assert.Equal(t, []string{"192.168.64.3", "192.168.64.5"}, p.NodeIPs())
+}
+
+func TestProfile_NodeNames(t *testing.T) {
+	p := &Profile{Name: "dev", Nodes: []Node{{IP: "192.168.64.3"}, {Name: "m02"}}}
+	assert.Equal(t, []string{"dev", "m02"}, p.NodeNames())
+	assert.Equal(t, []string{"dev"}, (&Profile{Name: "dev"}).NodeNames())
+}
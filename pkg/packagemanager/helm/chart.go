@@ -0,0 +1,146 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// containerFields are the fields of a pod spec which contain containers.
+var containerFields = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// ChartVersion is a version of a chart found in the index of its repository.
+type ChartVersion struct {
+	Chart      string
+	Version    string
+	AppVersion string
+}
+
+// resolveChart finds the newest version of the chart that matches the version constraint in the cached index of
+// its repository. An empty version matches the latest version.
+func resolveChart(cacheDir string, chart string, version string) (*ChartVersion, error) {
+	repoName, name, found := strings.Cut(chart, "/")
+	if !found {
+		return nil, fmt.Errorf("chart %s is not referenced as <repository>/<chart>", chart)
+	}
+	index, e := repo.LoadIndexFile(filepath.Join(cacheDir, helmpath.CacheIndexFile(repoName)))
+	if e != nil {
+		return nil, fmt.Errorf("can not load index of repository %s: %s", repoName, e)
+	}
+	chartVersion, e := index.Get(name, version)
+	if e != nil {
+		return nil, fmt.Errorf("can not find version '%s' of chart %s: %s", version, chart, e)
+	}
+	return &ChartVersion{Chart: chart, Version: chartVersion.Version, AppVersion: chartVersion.AppVersion}, nil
+}
+
+// PullChart downloads the version of the chart from its repository into the directory. It returns the path of
+// the chart archive <chart>-<version>.tgz.
+func PullChart(chart string, version string, dir string) (string, error) {
+	tmp, e := os.MkdirTemp("", "minikube-support-chart")
+	if e != nil {
+		return "", e
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	pull := action.NewPullWithOpts(action.WithConfig(&action.Configuration{}))
+	pull.Settings = cli.New()
+	pull.Version = version
+	pull.DestDir = tmp
+	if _, e := pull.Run(chart); e != nil {
+		return "", fmt.Errorf("can not download chart %s %s: %s", chart, version, e)
+	}
+	archives, e := filepath.Glob(filepath.Join(tmp, "*.tgz"))
+	if e != nil || len(archives) != 1 {
+		return "", fmt.Errorf("can not find downloaded archive of chart %s", chart)
+	}
+	content, e := os.ReadFile(archives[0])
+	if e != nil {
+		return "", e
+	}
+
+	_, name, _ := strings.Cut(chart, "/")
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.tgz", name, version))
+	if e := os.WriteFile(path, content, 0644); e != nil {
+		return "", fmt.Errorf("can not write chart %s: %s", path, e)
+	}
+	return path, nil
+}
+
+// ChartImages renders the chart with the given chart values without accessing a cluster and returns all container
+// images used by it, including the images of its hooks.
+func ChartImages(path string, values map[string]interface{}) ([]string, error) {
+	chart, e := loader.Load(path)
+	if e != nil {
+		return nil, fmt.Errorf("can not load chart %s: %s", path, e)
+	}
+	install := action.NewInstall(&action.Configuration{Log: logrus.Debugf})
+	install.ReleaseName = chart.Name()
+	install.Namespace = "default"
+	install.DryRun = true
+	install.ClientOnly = true
+	install.IncludeCRDs = true
+	rel, e := install.Run(chart, values)
+	if e != nil {
+		return nil, fmt.Errorf("can not render chart %s: %s", path, e)
+	}
+
+	manifests := []string{rel.Manifest}
+	for _, hook := range rel.Hooks {
+		manifests = append(manifests, hook.Manifest)
+	}
+	return manifestImages(strings.Join(manifests, "\n---\n"))
+}
+
+// manifestImages returns the sorted images of all containers defined in the yaml documents of the manifest.
+func manifestImages(manifest string) ([]string, error) {
+	images := map[string]bool{}
+	for _, document := range strings.Split(manifest, "\n---") {
+		var object interface{}
+		if e := yaml.Unmarshal([]byte(document), &object); e != nil {
+			return nil, fmt.Errorf("can not parse manifest: %s", e)
+		}
+		collectImages(object, images)
+	}
+
+	result := make([]string, 0, len(images))
+	for image := range images {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// collectImages walks through the object and adds the images of all containers.
+func collectImages(object interface{}, images map[string]bool) {
+	switch value := object.(type) {
+	case map[string]interface{}:
+		for _, field := range containerFields {
+			containers, _ := value[field].([]interface{})
+			for _, container := range containers {
+				if c, ok := container.(map[string]interface{}); ok {
+					if image, ok := c["image"].(string); ok && image != "" {
+						images[image] = true
+					}
+				}
+			}
+		}
+		for _, nested := range value {
+			collectImages(nested, images)
+		}
+	case []interface{}:
+		for _, nested := range value {
+			collectImages(nested, images)
+		}
+	}
+}
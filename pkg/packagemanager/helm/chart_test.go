@@ -0,0 +1,72 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartImages(t *testing.T) {
+	dir := writeTestChart(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "deployment.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox:1.36
+      containers:
+        - name: app
+          image: {{ .Values.image }}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "hook.yaml"), []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: hook
+  annotations:
+    helm.sh/hook: post-install
+spec:
+  template:
+    spec:
+      containers:
+        - name: hook
+          image: busybox:1.36
+`), 0644))
+
+	images, e := ChartImages(dir, map[string]interface{}{"image": "nginx:1.25", "replicas": "1"})
+
+	assert.NoError(t, e)
+	assert.Equal(t, []string{"busybox:1.36", "nginx:1.25"}, images)
+}
+
+func Test_manifestImages(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+		wantErr  bool
+	}{
+		{"empty", "", []string{}, false},
+		{"no containers", "apiVersion: v1\nkind: ConfigMap\n", []string{}, false},
+		{"pod", "kind: Pod\nspec:\n  containers:\n  - image: nginx\n  ephemeralContainers:\n  - image: busybox\n", []string{"busybox", "nginx"}, false},
+		{"documents", "kind: Pod\nspec:\n  containers:\n  - image: nginx\n---\nkind: Pod\nspec:\n  containers:\n  - image: nginx\n", []string{"nginx"}, false},
+		{"invalid", "kind: [", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := manifestImages(tt.manifest)
+			if tt.wantErr {
+				assert.Error(t, e)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
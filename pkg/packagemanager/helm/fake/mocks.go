@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	helm "github.com/qaware/minikube-support/pkg/packagemanager/helm"
 )
 
 // MockManager is a mock of Manager interface.
@@ -61,6 +62,21 @@ func (mr *MockManagerMockRecorder) GetVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockManager)(nil).GetVersion))
 }
 
+// History mocks base method.
+func (m *MockManager) History(release, namespace string) ([]helm.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", release, namespace)
+	ret0, _ := ret[0].([]helm.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockManagerMockRecorder) History(release, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockManager)(nil).History), release, namespace)
+}
+
 // Init mocks base method.
 func (m *MockManager) Init() error {
 	m.ctrl.T.Helper()
@@ -76,15 +92,61 @@ func (mr *MockManagerMockRecorder) Init() *gomock.Call {
 }
 
 // Install mocks base method.
-func (m *MockManager) Install(chart, release, namespace string, values map[string]interface{}, wait bool) {
+func (m *MockManager) Install(chart, version, release, namespace string, values map[string]interface{}, wait bool) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Install", chart, release, namespace, values, wait)
+	ret := m.ctrl.Call(m, "Install", chart, version, release, namespace, values, wait)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Install indicates an expected call of Install.
-func (mr *MockManagerMockRecorder) Install(chart, release, namespace, values, wait interface{}) *gomock.Call {
+func (mr *MockManagerMockRecorder) Install(chart, version, release, namespace, values, wait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Install", reflect.TypeOf((*MockManager)(nil).Install), chart, version, release, namespace, values, wait)
+}
+
+// ResolveChart mocks base method.
+func (m *MockManager) ResolveChart(chart, version string) (*helm.ChartVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveChart", chart, version)
+	ret0, _ := ret[0].(*helm.ChartVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveChart indicates an expected call of ResolveChart.
+func (mr *MockManagerMockRecorder) ResolveChart(chart, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveChart", reflect.TypeOf((*MockManager)(nil).ResolveChart), chart, version)
+}
+
+// Rollback mocks base method.
+func (m *MockManager) Rollback(release, namespace string, revision int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", release, namespace, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockManagerMockRecorder) Rollback(release, namespace, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockManager)(nil).Rollback), release, namespace, revision)
+}
+
+// Status mocks base method.
+func (m *MockManager) Status(release, namespace string) (*helm.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", release, namespace)
+	ret0, _ := ret[0].(*helm.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockManagerMockRecorder) Status(release, namespace interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Install", reflect.TypeOf((*MockManager)(nil).Install), chart, release, namespace, values, wait)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockManager)(nil).Status), release, namespace)
 }
 
 // Uninstall mocks base method.
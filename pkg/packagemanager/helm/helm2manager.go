@@ -3,14 +3,14 @@ package helm
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"sync"
 
-	"github.com/kballard/go-shellquote"
 	"github.com/sirupsen/logrus"
 
 	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/sh"
-	"github.com/qaware/minikube-support/pkg/utils"
 )
 
 type helm2Manager struct {
@@ -45,11 +45,13 @@ func (m *helm2Manager) Init() error {
 	return nil
 }
 
-func (m *helm2Manager) Install(chart string, release string, namespace string, values map[string]interface{}, wait bool) {
+func (m *helm2Manager) Install(chart string, version string, release string, namespace string, values map[string]interface{}, wait bool) error {
+	releaseError := func(e error) error {
+		return &ReleaseError{Action: "install", Release: release, Namespace: namespace, Err: e}
+	}
 	if !m.initialized {
 		if e := m.Init(); e != nil {
-			logrus.Errorf("Can not install helm chart: %s", e)
-			return
+			return releaseError(e)
 		}
 	}
 
@@ -58,29 +60,31 @@ func (m *helm2Manager) Install(chart string, release string, namespace string, v
 		"--namespace", namespace,
 		release, chart,
 	}
+	if version != "" {
+		args = append(args, "--version", version)
+	}
 	if wait {
 		args = append(args, "--wait")
 	}
 
-	flatValues, e := utils.Flatten(values)
-	if e != nil {
-		logrus.Warnf("Can not flatten values map; Abort to install chart: %s", e)
-		return
-	}
-
-	for k, v := range flatValues {
-		val := shellquote.Join(fmt.Sprintf("%s=%s", k, v))
-		args = append(args, "--set", val)
+	// the values are passed as file, so that their types and keys containing dots or commas are kept
+	if len(values) > 0 {
+		file, remove, e := writeValuesFile(values)
+		if e != nil {
+			return releaseError(e)
+		}
+		defer remove()
+		args = append(args, "--values", file)
 	}
 
 	response, e := m.runCommand("upgrade", args...)
 	if e != nil {
-		logrus.Errorf("Can not install (%s) helm chart %s into namespace %s:\n%s", e, chart, namespace, response)
-		return
+		return releaseError(fmt.Errorf("%s\n%s", e, response))
 	}
 
 	logrus.Infof("Install of helm chart %s as %s/%s was successful.", chart, namespace, release)
 	logrus.Debug(response)
+	return nil
 }
 
 func (m *helm2Manager) Uninstall(release string, _ string, purge bool) {
@@ -137,6 +141,64 @@ func (m *helm2Manager) UpdateRepository() error {
 	return nil
 }
 
+func (m *helm2Manager) ResolveChart(chart string, version string) (*ChartVersion, error) {
+	home := os.Getenv("HELM_HOME")
+	if home == "" {
+		userHome, e := os.UserHomeDir()
+		if e != nil {
+			return nil, fmt.Errorf("can not determ helm home: %s", e)
+		}
+		home = filepath.Join(userHome, ".helm")
+	}
+	return resolveChart(filepath.Join(home, "repository", "cache"), chart, version)
+}
+
+func (m *helm2Manager) Status(release string, namespace string) (*Revision, error) {
+	history, e := m.History(release, namespace)
+	if e != nil {
+		return nil, e
+	}
+	return lastRevision(release, history)
+}
+
+func (m *helm2Manager) History(release string, _ string) ([]Revision, error) {
+	if !m.initialized {
+		if e := m.Init(); e != nil {
+			return nil, e
+		}
+	}
+	response, e := m.runCommand("history", "--output", "json", release)
+	if e != nil {
+		return nil, fmt.Errorf("%s\n%s", e, response)
+	}
+	return parseHistory(response)
+}
+
+func (m *helm2Manager) Rollback(release string, namespace string, revision int) error {
+	if revision <= 0 {
+		// helm 2 requires the revision to roll back to
+		history, e := m.History(release, namespace)
+		if e != nil {
+			return e
+		}
+		if revision, e = previousRevision(release, history); e != nil {
+			return e
+		}
+	}
+	if !m.initialized {
+		if e := m.Init(); e != nil {
+			return e
+		}
+	}
+	response, e := m.runCommand("rollback", "--wait", release, strconv.Itoa(revision))
+	if e != nil {
+		return fmt.Errorf("%s\n%s", e, response)
+	}
+	logrus.Infof("Rollback of helm release %s was successful.", release)
+	logrus.Debug(response)
+	return nil
+}
+
 func (m *helm2Manager) GetVersion() string {
 	return "2"
 }
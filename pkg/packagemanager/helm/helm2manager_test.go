@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"errors"
 	"os/exec"
 	"testing"
 
@@ -51,6 +52,7 @@ func Test_helm2Manager_Init(t *testing.T) {
 func Test_helm2Manager_Install(t *testing.T) {
 	sh.ExecCommand = testutils.FakeExecCommand
 	defer func() { sh.ExecCommand = exec.Command }()
+	defer fixedValuesFile()()
 	tests := []struct {
 		name           string
 		chart          string
@@ -62,7 +64,7 @@ func Test_helm2Manager_Install(t *testing.T) {
 		expectedArgs   [][]string
 		response       string
 		responseStatus int
-		lastEntryLevel logrus.Level
+		wantErr        bool
 	}{
 		{
 			"success",
@@ -75,7 +77,7 @@ func Test_helm2Manager_Install(t *testing.T) {
 			[][]string{{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test"}},
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"wait for success",
 			"dummy/test",
@@ -87,7 +89,7 @@ func Test_helm2Manager_Install(t *testing.T) {
 			[][]string{{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--wait"}},
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"success uninitialized",
 			"dummy/test",
@@ -99,7 +101,7 @@ func Test_helm2Manager_Install(t *testing.T) {
 			[][]string{{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test"}},
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"success with values",
 			"dummy/test",
@@ -108,13 +110,10 @@ func Test_helm2Manager_Install(t *testing.T) {
 			false,
 			map[string]interface{}{"v1": []map[string]interface{}{{"h": 2, "b": "def"}}},
 			true,
-			[][]string{
-				{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--set", "v1\\[0].h=2", "--set", "v1\\[0].b=def"},
-				{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--set", "v1\\[0].b=def", "--set", "v1\\[0].h=2"},
-			},
+			[][]string{{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--values", "values.yaml"}},
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"missing name and chart",
 			"",
@@ -126,7 +125,7 @@ func Test_helm2Manager_Install(t *testing.T) {
 			[][]string{{"upgrade", "--install", "--force", "--namespace", "test", "", ""}},
 			"no release and name given",
 			1,
-			logrus.ErrorLevel,
+			true,
 		},
 	}
 	for _, tt := range tests {
@@ -143,12 +142,14 @@ func Test_helm2Manager_Install(t *testing.T) {
 				testutils.AddTestProcessResponse(testutils.TestProcessResponse{Command: "helm", Args: args, ResponseStatus: tt.responseStatus, Stdout: tt.response})
 			}
 
-			m.Install(tt.chart, tt.release, tt.namespace, tt.values, tt.wait)
+			e := m.Install(tt.chart, "", tt.release, tt.namespace, tt.values, tt.wait)
 
-			lastEntry := global.LastEntry()
-			if !assert.Equal(t, tt.lastEntryLevel, lastEntry.Level) {
-				s, _ := lastEntry.String()
-				t.Log(s)
+			if tt.wantErr {
+				var releaseError *ReleaseError
+				assert.True(t, errors.As(e, &releaseError))
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, logrus.InfoLevel, global.LastEntry().Level)
 			}
 		})
 	}
@@ -332,3 +333,19 @@ func Test_helm2Manager_runCommand(t *testing.T) {
 		})
 	}
 }
+
+func Test_helm2Manager_Rollback(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+	m := &helm2Manager{context: fake.NewContextHandler(nil, nil), initialized: true}
+	testutils.SetTestProcessResponses([]testutils.TestProcessResponse{
+		{Command: "helm", Args: []string{"history", "--output", "json", "test"}, Stdout: `[{"revision":4,"status":"SUPERSEDED"},{"revision":5,"status":"DEPLOYED"}]`},
+		{Command: "helm", Args: []string{"history", "--output", "json", "single"}, Stdout: `[{"revision":1,"status":"DEPLOYED"}]`},
+		{Command: "helm", Args: []string{"rollback", "--wait", "test", "4"}, Stdout: "Rollback was a success!"},
+		{Command: "helm", Args: []string{"rollback", "--wait", "test", "2"}, Stdout: "Rollback was a success!"},
+	})
+
+	assert.NoError(t, m.Rollback("test", "", 0))
+	assert.NoError(t, m.Rollback("test", "", 2))
+	assert.EqualError(t, m.Rollback("single", "", 0), "release single has no previous revision")
+}
@@ -5,15 +5,14 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/kballard/go-shellquote"
 	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/cli"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/sh"
-	"github.com/qaware/minikube-support/pkg/utils"
 )
 
 const namespaceArgument = "--namespace"
@@ -51,10 +50,17 @@ func (h *helm3Manager) UpdateRepository() error {
 	return nil
 }
 
-func (h *helm3Manager) Install(chart string, release string, namespace string, values map[string]interface{}, wait bool) {
+func (h *helm3Manager) ResolveChart(chart string, version string) (*ChartVersion, error) {
+	// the helm binary uses the same environment variables and default locations as the helm sdk
+	return resolveChart(cli.New().RepositoryCache, chart, version)
+}
+
+func (h *helm3Manager) Install(chart string, version string, release string, namespace string, values map[string]interface{}, wait bool) error {
+	releaseError := func(e error) error {
+		return &ReleaseError{Action: "install", Release: release, Namespace: namespace, Err: e}
+	}
 	if e := h.ensureNamespaceExists(namespace); e != nil {
-		logrus.Errorf("can not ensure that namespace %s exists: %s", namespace, e)
-		return
+		return releaseError(fmt.Errorf("can not ensure that namespace %s exists: %s", namespace, e))
 	}
 
 	var args = []string{
@@ -62,29 +68,31 @@ func (h *helm3Manager) Install(chart string, release string, namespace string, v
 		namespaceArgument, namespace,
 		release, chart,
 	}
+	if version != "" {
+		args = append(args, "--version", version)
+	}
 	if wait {
 		args = append(args, "--wait")
 	}
 
-	flatValues, e := utils.Flatten(values)
-	if e != nil {
-		logrus.Warnf("Can not flatten values map; Abort to install chart: %s", e)
-		return
-	}
-
-	for k, v := range flatValues {
-		val := shellquote.Join(fmt.Sprintf("%s=%s", k, v))
-		args = append(args, "--set", val)
+	// the values are passed as file, so that their types and keys containing dots or commas are kept
+	if len(values) > 0 {
+		file, remove, e := writeValuesFile(values)
+		if e != nil {
+			return releaseError(e)
+		}
+		defer remove()
+		args = append(args, "--values", file)
 	}
 
 	response, e := h.runCommand("upgrade", args...)
 	if e != nil {
-		logrus.Errorf("Can not install (%s) helm chart %s into namespace %s:\n%s", e, chart, namespace, response)
-		return
+		return releaseError(fmt.Errorf("%s\n%s", e, response))
 	}
 
 	logrus.Infof("Install of helm chart %s as %s/%s was successful.", chart, namespace, release)
 	logrus.Debug(response)
+	return nil
 }
 
 func (h *helm3Manager) Uninstall(release string, namespace string, purge bool) {
@@ -105,6 +113,33 @@ func (h *helm3Manager) Uninstall(release string, namespace string, purge bool) {
 	logrus.Debug(response)
 }
 
+func (h *helm3Manager) Status(release string, namespace string) (*Revision, error) {
+	history, e := h.History(release, namespace)
+	if e != nil {
+		return nil, e
+	}
+	return lastRevision(release, history)
+}
+
+func (h *helm3Manager) History(release string, namespace string) ([]Revision, error) {
+	response, e := h.runCommand("history", namespaceArgument, namespace, "--output", "json", release)
+	if e != nil {
+		return nil, fmt.Errorf("%s\n%s", e, response)
+	}
+	return parseHistory(response)
+}
+
+func (h *helm3Manager) Rollback(release string, namespace string, revision int) error {
+	args := append([]string{namespaceArgument, namespace, "--wait", release}, revisionArgs(revision)...)
+	response, e := h.runCommand("rollback", args...)
+	if e != nil {
+		return fmt.Errorf("%s\n%s", e, response)
+	}
+	logrus.Infof("Rollback of helm release %s/%s was successful.", namespace, release)
+	logrus.Debug(response)
+	return nil
+}
+
 func (h *helm3Manager) GetVersion() string {
 	return "3"
 }
@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
@@ -19,6 +20,7 @@ import (
 func Test_helm3Manager_Install(t *testing.T) {
 	sh.ExecCommand = testutils.FakeExecCommand
 	defer func() { sh.ExecCommand = exec.Command }()
+	defer fixedValuesFile()()
 	tests := []struct {
 		name           string
 		chart          string
@@ -30,7 +32,7 @@ func Test_helm3Manager_Install(t *testing.T) {
 		nsExists       bool
 		response       string
 		responseStatus int
-		lastEntryLevel logrus.Level
+		wantErr        bool
 	}{
 		{
 			"success",
@@ -43,7 +45,7 @@ func Test_helm3Manager_Install(t *testing.T) {
 			true,
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"success but namespace is missing",
 			"dummy/test",
@@ -55,7 +57,7 @@ func Test_helm3Manager_Install(t *testing.T) {
 			false,
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"wait for success",
 			"dummy/test",
@@ -67,7 +69,7 @@ func Test_helm3Manager_Install(t *testing.T) {
 			true,
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"success with values",
 			"dummy/test",
@@ -75,14 +77,11 @@ func Test_helm3Manager_Install(t *testing.T) {
 			"test",
 			false,
 			map[string]interface{}{"v1": []map[string]interface{}{{"h": 2, "b": "def"}}},
-			[][]string{
-				{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--set", "v1\\[0].h=2", "--set", "v1\\[0].b=def"},
-				{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--set", "v1\\[0].b=def", "--set", "v1\\[0].h=2"},
-			},
+			[][]string{{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--values", "values.yaml"}},
 			true,
 			"ok installed",
 			0,
-			logrus.InfoLevel,
+			false,
 		}, {
 			"missing name and chart",
 			"",
@@ -94,7 +93,7 @@ func Test_helm3Manager_Install(t *testing.T) {
 			true,
 			"no release and name given",
 			1,
-			logrus.ErrorLevel,
+			true,
 		},
 	}
 	for _, tt := range tests {
@@ -120,17 +119,46 @@ func Test_helm3Manager_Install(t *testing.T) {
 					testutils.TestProcessResponse{Command: "helm", Args: args, ResponseStatus: tt.responseStatus, Stdout: tt.response})
 			}
 
-			m.Install(tt.chart, tt.release, tt.namespace, tt.values, tt.wait)
+			e := m.Install(tt.chart, "", tt.release, tt.namespace, tt.values, tt.wait)
 
-			lastEntry := global.LastEntry()
-			if !assert.Equal(t, tt.lastEntryLevel, lastEntry.Level) {
-				s, _ := lastEntry.String()
-				t.Log(s)
+			if tt.wantErr {
+				var releaseError *ReleaseError
+				assert.True(t, errors.As(e, &releaseError))
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, logrus.InfoLevel, global.LastEntry().Level)
 			}
 		})
 	}
 }
 
+func Test_helm3Manager_Install_version(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+	fakeClientSet := k8sFake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	m := &helm3Manager{context: fake.NewContextHandler(fakeClientSet, nil)}
+	testutils.SetTestProcessResponse(testutils.TestProcessResponse{
+		Command:        "helm",
+		Args:           []string{"upgrade", "--install", "--force", "--namespace", "test", "test", "dummy/test", "--version", "1.2.3"},
+		ResponseStatus: 0,
+		Stdout:         "ok installed",
+	})
+
+	assert.NoError(t, m.Install("dummy/test", "1.2.3", "test", "test", map[string]interface{}{}, false))
+	assert.Equal(t, logrus.InfoLevel, global.LastEntry().Level)
+}
+
+// fixedValuesFile makes the managers pass the values file as values.yaml. It returns a function to restore the
+// original behaviour.
+func fixedValuesFile() func() {
+	original := writeValuesFile
+	writeValuesFile = func(values map[string]interface{}) (string, func(), error) {
+		_, remove, e := original(values)
+		return "values.yaml", remove, e
+	}
+	return func() { writeValuesFile = original }
+}
+
 func Test_helm3Manager_Uninstall(t *testing.T) {
 	sh.ExecCommand = testutils.FakeExecCommand
 	defer func() { sh.ExecCommand = exec.Command }()
@@ -319,3 +347,34 @@ func Test_helm3Manager_ensureNamespaceExists(t *testing.T) {
 		})
 	}
 }
+
+func Test_helm3Manager_Rollback(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+	m := &helm3Manager{context: fake.NewContextHandler(nil, nil)}
+	testutils.SetTestProcessResponses([]testutils.TestProcessResponse{
+		{Command: "helm", Args: []string{"rollback", "--namespace", "mks", "--wait", "test"}, Stdout: "Rollback was a success!"},
+		{Command: "helm", Args: []string{"rollback", "--namespace", "mks", "--wait", "test", "3"}, Stdout: "Rollback was a success!"},
+		{Command: "helm", Args: []string{"rollback", "--namespace", "mks", "--wait", "other"}, ResponseStatus: 1, Stdout: "Error: release: not found"},
+	})
+
+	assert.NoError(t, m.Rollback("test", "mks", 0))
+	assert.NoError(t, m.Rollback("test", "mks", 3))
+	assert.Error(t, m.Rollback("other", "mks", 0))
+}
+
+func Test_helm3Manager_Status(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+	m := &helm3Manager{context: fake.NewContextHandler(nil, nil)}
+	testutils.SetTestProcessResponses([]testutils.TestProcessResponse{
+		{Command: "helm", Args: []string{"history", "--namespace", "mks", "--output", "json", "test"}, Stdout: `[{"revision":1,"status":"superseded"},{"revision":2,"status":"deployed"}]`},
+		{Command: "helm", Args: []string{"history", "--namespace", "mks", "--output", "json", "other"}, ResponseStatus: 1, Stdout: "Error: release: not found"},
+	})
+
+	status, e := m.Status("test", "mks")
+	assert.NoError(t, e)
+	assert.Equal(t, &Revision{Revision: 2, Status: "deployed"}, status)
+	_, e = m.Status("other", "mks")
+	assert.Error(t, e)
+}
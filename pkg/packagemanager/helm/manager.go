@@ -1,9 +1,12 @@
 package helm
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/sh"
@@ -15,11 +18,141 @@ type Manager interface {
 	Init() error
 	AddRepository(name string, url string) error
 	UpdateRepository() error
-	Install(chart string, release string, namespace string, values map[string]interface{}, wait bool)
+	// ResolveChart finds the newest version of the chart matching the version constraint in the local repository
+	// index. An empty version matches the latest version.
+	ResolveChart(chart string, version string) (*ChartVersion, error)
+	// Install installs or upgrades the release to the given version of the chart. An empty version installs the
+	// latest version. The values are the nested values of the chart, see ParseValues. A failed installation is
+	// returned as *ReleaseError.
+	Install(chart string, version string, release string, namespace string, values map[string]interface{}, wait bool) error
 	Uninstall(release string, namespace string, purge bool)
+	// Status returns the current revision of the release.
+	Status(release string, namespace string) (*Revision, error)
+	// History returns all revisions of the release from the oldest to the newest.
+	History(release string, namespace string) ([]Revision, error)
+	// Rollback rolls the release back to the given revision. Revision 0 rolls back to the previous revision.
+	Rollback(release string, namespace string, revision int) error
 	GetVersion() string
 }
 
+// The kinds of helm managers which can be selected with NewManager.
+const (
+	// KindSdk selects the manager using the helm go sdk.
+	KindSdk = "sdk"
+	// KindCli selects the manager using the installed helm binary.
+	KindCli = "cli"
+)
+
+// selectingManager creates the selected helm manager on its first use.
+type selectingManager struct {
+	kind    *string
+	context kubernetes.ContextHandler
+	once    sync.Once
+	manager Manager
+	err     error
+}
+
+// NewManager creates a helm manager which delegates to the manager of the given kind. The kind is read when the
+// manager is used the first time.
+func NewManager(kind *string, context kubernetes.ContextHandler) Manager {
+	return &selectingManager{kind: kind, context: context}
+}
+
+func (m *selectingManager) get() (Manager, error) {
+	m.once.Do(func() {
+		switch *m.kind {
+		case KindSdk, "":
+			m.manager = NewSdkManager(m.context)
+		case KindCli:
+			m.manager, m.err = NewHelmManager(m.context)
+		default:
+			m.err = fmt.Errorf("unknown helm manager %s, use %s or %s", *m.kind, KindSdk, KindCli)
+		}
+	})
+	return m.manager, m.err
+}
+
+func (m *selectingManager) Init() error {
+	manager, e := m.get()
+	if e != nil {
+		return e
+	}
+	return manager.Init()
+}
+
+func (m *selectingManager) AddRepository(name string, url string) error {
+	manager, e := m.get()
+	if e != nil {
+		return e
+	}
+	return manager.AddRepository(name, url)
+}
+
+func (m *selectingManager) UpdateRepository() error {
+	manager, e := m.get()
+	if e != nil {
+		return e
+	}
+	return manager.UpdateRepository()
+}
+
+func (m *selectingManager) ResolveChart(chart string, version string) (*ChartVersion, error) {
+	manager, e := m.get()
+	if e != nil {
+		return nil, e
+	}
+	return manager.ResolveChart(chart, version)
+}
+
+func (m *selectingManager) Install(chart string, version string, release string, namespace string, values map[string]interface{}, wait bool) error {
+	manager, e := m.get()
+	if e != nil {
+		return &ReleaseError{Action: "install", Release: release, Namespace: namespace, Err: e}
+	}
+	return manager.Install(chart, version, release, namespace, values, wait)
+}
+
+func (m *selectingManager) Uninstall(release string, namespace string, purge bool) {
+	manager, e := m.get()
+	if e != nil {
+		logrus.Errorf("Can not delete helm release %s: %s", release, e)
+		return
+	}
+	manager.Uninstall(release, namespace, purge)
+}
+
+func (m *selectingManager) Status(release string, namespace string) (*Revision, error) {
+	manager, e := m.get()
+	if e != nil {
+		return nil, e
+	}
+	return manager.Status(release, namespace)
+}
+
+func (m *selectingManager) History(release string, namespace string) ([]Revision, error) {
+	manager, e := m.get()
+	if e != nil {
+		return nil, e
+	}
+	return manager.History(release, namespace)
+}
+
+func (m *selectingManager) Rollback(release string, namespace string, revision int) error {
+	manager, e := m.get()
+	if e != nil {
+		return e
+	}
+	return manager.Rollback(release, namespace, revision)
+}
+
+func (m *selectingManager) GetVersion() string {
+	manager, e := m.get()
+	if e != nil {
+		return ""
+	}
+	return manager.GetVersion()
+}
+
 func NewHelmManager(context kubernetes.ContextHandler) (Manager, error) {
 	version, e := getHelmVersion()
 	if e != nil {
@@ -0,0 +1,81 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Revision is a revision of a helm release as listed in its history.
+type Revision struct {
+	Revision    int
+	Updated     time.Time
+	Status      string
+	Chart       string
+	AppVersion  string
+	Description string
+}
+
+// revisionJSON is a revision in the json output of helm history.
+type revisionJSON struct {
+	Revision    int    `json:"revision"`
+	Updated     string `json:"updated"`
+	Status      string `json:"status"`
+	Chart       string `json:"chart"`
+	AppVersion  string `json:"app_version"`
+	Description string `json:"description"`
+}
+
+// timeFormats are the formats of the updated time used by helm 3 and helm 2.
+var timeFormats = []string{time.RFC3339Nano, time.ANSIC}
+
+// parseHistory parses the json output of helm history. The revisions are sorted from the oldest to the newest.
+func parseHistory(output string) ([]Revision, error) {
+	var entries []revisionJSON
+	if e := json.Unmarshal([]byte(output), &entries); e != nil {
+		return nil, fmt.Errorf("can not parse helm history: %s", e)
+	}
+	revisions := make([]Revision, 0, len(entries))
+	for _, entry := range entries {
+		revision := Revision{
+			Revision:    entry.Revision,
+			Status:      entry.Status,
+			Chart:       entry.Chart,
+			AppVersion:  entry.AppVersion,
+			Description: entry.Description,
+		}
+		for _, format := range timeFormats {
+			if updated, e := time.Parse(format, entry.Updated); e == nil {
+				revision.Updated = updated
+				break
+			}
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// lastRevision returns the newest revision of the history.
+func lastRevision(release string, history []Revision) (*Revision, error) {
+	if len(history) == 0 {
+		return nil, fmt.Errorf("release %s has no revisions", release)
+	}
+	return &history[len(history)-1], nil
+}
+
+// previousRevision returns the revision before the newest one which is the target of a rollback without revision.
+func previousRevision(release string, history []Revision) (int, error) {
+	if len(history) < 2 {
+		return 0, fmt.Errorf("release %s has no previous revision", release)
+	}
+	return history[len(history)-2].Revision, nil
+}
+
+// revisionArgs returns the revision as argument for helm rollback. Without revision no argument is needed.
+func revisionArgs(revision int) []string {
+	if revision <= 0 {
+		return nil
+	}
+	return []string{strconv.Itoa(revision)}
+}
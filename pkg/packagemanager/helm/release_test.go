@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []Revision
+		wantErr bool
+	}{
+		{
+			"helm 3",
+			`[{"revision":1,"updated":"2024-05-02T10:15:30.123456789+02:00","status":"superseded","chart":"ingress-nginx-4.10.0","app_version":"1.10.0","description":"Install complete"},` +
+				`{"revision":2,"updated":"2024-05-03T10:15:30.123456789+02:00","status":"deployed","chart":"ingress-nginx-4.10.1","app_version":"1.10.1","description":"Upgrade complete"}]`,
+			[]Revision{
+				{Revision: 1, Updated: time.Date(2024, 5, 2, 10, 15, 30, 123456789, time.FixedZone("", 7200)), Status: "superseded", Chart: "ingress-nginx-4.10.0", AppVersion: "1.10.0", Description: "Install complete"},
+				{Revision: 2, Updated: time.Date(2024, 5, 3, 10, 15, 30, 123456789, time.FixedZone("", 7200)), Status: "deployed", Chart: "ingress-nginx-4.10.1", AppVersion: "1.10.1", Description: "Upgrade complete"},
+			},
+			false,
+		},
+		{
+			"helm 2",
+			`[{"revision":1,"updated":"Thu May  2 10:15:30 2024","status":"DEPLOYED","chart":"nginx-ingress-1.41.3","description":"Install complete"}]`,
+			[]Revision{{Revision: 1, Updated: time.Date(2024, 5, 2, 10, 15, 30, 0, time.UTC), Status: "DEPLOYED", Chart: "nginx-ingress-1.41.3", Description: "Install complete"}},
+			false,
+		},
+		{"empty", "[]", []Revision{}, false},
+		{"invalid", "Error: release: not found", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := parseHistory(tt.output)
+			if tt.wantErr {
+				assert.Error(t, e)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Len(t, got, len(tt.want))
+			for i := range tt.want {
+				assert.True(t, tt.want[i].Updated.Equal(got[i].Updated), "updated %s != %s", tt.want[i].Updated, got[i].Updated)
+				got[i].Updated = tt.want[i].Updated
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_previousRevision(t *testing.T) {
+	_, e := previousRevision("test", []Revision{{Revision: 1}})
+	assert.Error(t, e)
+
+	revision, e := previousRevision("test", []Revision{{Revision: 1}, {Revision: 3}, {Revision: 4}})
+	assert.NoError(t, e)
+	assert.Equal(t, 3, revision)
+}
@@ -0,0 +1,305 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	helmchart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+)
+
+// sdkTimeout is the time helm waits for the resources of a release if the install should wait.
+var sdkTimeout = 5 * time.Minute
+
+// ReleaseError is returned if an action on a helm release failed.
+type ReleaseError struct {
+	Action    string
+	Release   string
+	Namespace string
+	Err       error
+}
+
+func (e *ReleaseError) Error() string {
+	return fmt.Sprintf("can not %s helm release %s/%s: %s", e.Action, e.Namespace, e.Release, e.Err)
+}
+
+func (e *ReleaseError) Unwrap() error {
+	return e.Err
+}
+
+// RepositoryError is returned if a helm repository can not be added or updated.
+type RepositoryError struct {
+	Name string
+	URL  string
+	Err  error
+}
+
+func (e *RepositoryError) Error() string {
+	return fmt.Sprintf("can not load helm repository %s (%s): %s", e.Name, e.URL, e.Err)
+}
+
+func (e *RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// sdkManager manages helm releases using the helm go sdk. So the helm binary has not to be installed.
+type sdkManager struct {
+	context   kubernetes.ContextHandler
+	settings  *cli.EnvSettings
+	newConfig func(namespace string) (*action.Configuration, error)
+}
+
+// NewSdkManager creates a new helm 3 manager which uses the helm go sdk instead of the helm binary.
+// Repositories and the repository cache are shared with the helm binary.
+func NewSdkManager(context kubernetes.ContextHandler) Manager {
+	m := &sdkManager{
+		context:  context,
+		settings: cli.New(),
+	}
+	m.newConfig = m.actionConfig
+	return m
+}
+
+func (m *sdkManager) Init() error {
+	// do nothing, helm3 do not needs a call to 'helm init' as it do not work with tiller.
+	return nil
+}
+
+func (m *sdkManager) AddRepository(name string, url string) error {
+	file, e := m.loadRepositoryFile()
+	if e != nil {
+		return &RepositoryError{Name: name, URL: url, Err: e}
+	}
+
+	entry := &repo.Entry{Name: name, URL: url}
+	if e := m.downloadIndex(entry); e != nil {
+		return e
+	}
+
+	file.Update(entry)
+	if e := file.WriteFile(m.settings.RepositoryConfig, 0600); e != nil {
+		return &RepositoryError{Name: name, URL: url, Err: e}
+	}
+	logrus.Debugf("Helm repository %s (%s) added.", name, url)
+	return nil
+}
+
+func (m *sdkManager) UpdateRepository() error {
+	file, e := m.loadRepositoryFile()
+	if e != nil {
+		return fmt.Errorf("can not load helm repositories: %s", e)
+	}
+
+	var errs *multierror.Error
+	for _, entry := range file.Repositories {
+		errs = multierror.Append(errs, m.downloadIndex(entry))
+	}
+	return errs.ErrorOrNil()
+}
+
+func (m *sdkManager) ResolveChart(chart string, version string) (*ChartVersion, error) {
+	return resolveChart(m.settings.RepositoryCache, chart, version)
+}
+
+func (m *sdkManager) Install(chart string, version string, release string, namespace string, values map[string]interface{}, wait bool) error {
+	rel, e := m.install(chart, version, release, namespace, values, wait)
+	if e != nil {
+		return e
+	}
+	logrus.Infof("Install of helm chart %s %s as %s/%s was successful. Status: %s, revision: %d", chart, rel.Chart.Metadata.Version, namespace, release, rel.Info.Status, rel.Version)
+	return nil
+}
+
+func (m *sdkManager) Uninstall(release string, namespace string, purge bool) {
+	cfg, e := m.newConfig(namespace)
+	if e != nil {
+		logrus.Errorf("Can not delete helm release %s: %s", release, &ReleaseError{Action: "uninstall", Release: release, Namespace: namespace, Err: e})
+		return
+	}
+
+	uninstall := action.NewUninstall(cfg)
+	uninstall.KeepHistory = !purge
+	response, e := uninstall.Run(release)
+	if e != nil {
+		logrus.Errorf("Can not delete helm release %s: %s", release, &ReleaseError{Action: "uninstall", Release: release, Namespace: namespace, Err: e})
+		return
+	}
+	logrus.Infof("Helm release %s successfully deleted.", release)
+	if response != nil && response.Info != "" {
+		logrus.Debug(response.Info)
+	}
+}
+
+func (m *sdkManager) Status(name string, namespace string) (*Revision, error) {
+	cfg, e := m.newConfig(namespace)
+	if e != nil {
+		return nil, &ReleaseError{Action: "read", Release: name, Namespace: namespace, Err: e}
+	}
+	rel, e := action.NewStatus(cfg).Run(name)
+	if e != nil {
+		return nil, &ReleaseError{Action: "read", Release: name, Namespace: namespace, Err: e}
+	}
+	revision := toRevision(rel)
+	return &revision, nil
+}
+
+func (m *sdkManager) History(name string, namespace string) ([]Revision, error) {
+	cfg, e := m.newConfig(namespace)
+	if e != nil {
+		return nil, &ReleaseError{Action: "read history of", Release: name, Namespace: namespace, Err: e}
+	}
+	releases, e := action.NewHistory(cfg).Run(name)
+	if e != nil {
+		return nil, &ReleaseError{Action: "read history of", Release: name, Namespace: namespace, Err: e}
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Version < releases[j].Version })
+	revisions := make([]Revision, 0, len(releases))
+	for _, rel := range releases {
+		revisions = append(revisions, toRevision(rel))
+	}
+	return revisions, nil
+}
+
+func (m *sdkManager) Rollback(name string, namespace string, revision int) error {
+	cfg, e := m.newConfig(namespace)
+	if e != nil {
+		return &ReleaseError{Action: "roll back", Release: name, Namespace: namespace, Err: e}
+	}
+	rollback := action.NewRollback(cfg)
+	rollback.Version = revision
+	rollback.Wait = true
+	rollback.Timeout = sdkTimeout
+	if e := rollback.Run(name); e != nil {
+		return &ReleaseError{Action: "roll back", Release: name, Namespace: namespace, Err: e}
+	}
+	logrus.Infof("Rollback of helm release %s/%s was successful.", namespace, name)
+	return nil
+}
+
+func (m *sdkManager) GetVersion() string {
+	return "3"
+}
+
+// install installs the chart or upgrades the release if it already exists.
+func (m *sdkManager) install(chartName string, version string, name string, namespace string, values map[string]interface{}, wait bool) (*release.Release, error) {
+	releaseError := func(action string, e error) error {
+		return &ReleaseError{Action: action, Release: name, Namespace: namespace, Err: e}
+	}
+
+	cfg, e := m.newConfig(namespace)
+	if e != nil {
+		return nil, releaseError("install", e)
+	}
+
+	history := action.NewHistory(cfg)
+	history.Max = 1
+	_, e = history.Run(name)
+	if e != nil && !errors.Is(e, driver.ErrReleaseNotFound) {
+		return nil, releaseError("install", e)
+	}
+
+	if e == nil {
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = namespace
+		upgrade.Wait = wait
+		upgrade.Timeout = sdkTimeout
+		upgrade.Version = version
+		chart, e := m.loadChart(&upgrade.ChartPathOptions, chartName)
+		if e != nil {
+			return nil, releaseError("upgrade", e)
+		}
+		rel, e := upgrade.Run(name, chart, values)
+		if e != nil {
+			return nil, releaseError("upgrade", e)
+		}
+		return rel, nil
+	}
+
+	install := action.NewInstall(cfg)
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.CreateNamespace = true
+	install.Wait = wait
+	install.Timeout = sdkTimeout
+	install.Version = version
+	chart, e := m.loadChart(&install.ChartPathOptions, chartName)
+	if e != nil {
+		return nil, releaseError("install", e)
+	}
+	rel, e := install.Run(chart, values)
+	if e != nil {
+		return nil, releaseError("install", e)
+	}
+	return rel, nil
+}
+
+// toRevision converts the helm release into the revision as listed by helm history.
+func toRevision(rel *release.Release) Revision {
+	revision := Revision{Revision: rel.Version}
+	if rel.Info != nil {
+		revision.Updated = rel.Info.LastDeployed.Time
+		revision.Status = rel.Info.Status.String()
+		revision.Description = rel.Info.Description
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		revision.Chart = fmt.Sprintf("%s-%s", rel.Chart.Metadata.Name, rel.Chart.Metadata.Version)
+		revision.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	return revision
+}
+
+// loadChart locates the chart in the local repositories or the filesystem and loads it.
+func (m *sdkManager) loadChart(options *action.ChartPathOptions, name string) (*helmchart.Chart, error) {
+	path, e := options.LocateChart(name, m.settings)
+	if e != nil {
+		return nil, e
+	}
+	return loader.Load(path)
+}
+
+// actionConfig creates the helm configuration for the cluster of the context handler.
+func (m *sdkManager) actionConfig(namespace string) (*action.Configuration, error) {
+	m.settings.KubeConfig = m.context.GetConfigFile()
+	m.settings.KubeContext = m.context.GetContextName()
+	m.settings.SetNamespace(namespace)
+
+	cfg := &action.Configuration{}
+	if e := cfg.Init(m.settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), logrus.Debugf); e != nil {
+		return nil, e
+	}
+	return cfg, nil
+}
+
+func (m *sdkManager) loadRepositoryFile() (*repo.File, error) {
+	file, e := repo.LoadFile(m.settings.RepositoryConfig)
+	if errors.Is(e, os.ErrNotExist) {
+		return repo.NewFile(), nil
+	}
+	return file, e
+}
+
+// downloadIndex downloads the index file of the repository into the repository cache.
+func (m *sdkManager) downloadIndex(entry *repo.Entry) error {
+	repository, e := repo.NewChartRepository(entry, getter.All(m.settings))
+	if e != nil {
+		return &RepositoryError{Name: entry.Name, URL: entry.URL, Err: e}
+	}
+	repository.CachePath = m.settings.RepositoryCache
+	if _, e := repository.DownloadIndexFile(); e != nil {
+		return &RepositoryError{Name: entry.Name, URL: entry.URL, Err: e}
+	}
+	return nil
+}
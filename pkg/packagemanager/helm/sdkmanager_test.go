@@ -0,0 +1,239 @@
+package helm
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func Test_sdkManager_Install(t *testing.T) {
+	chart := writeTestChart(t)
+	m, store := newTestSdkManager(t)
+	hook := test.NewGlobal()
+
+	require.NoError(t, m.Install(chart, "", "test", "mks", map[string]interface{}{"replicas": "2"}, false))
+	testutils.CheckLogEntry(t, hook, "Install of helm chart "+chart+" 0.1.0 as mks/test was successful. Status: deployed, revision: 1")
+
+	require.NoError(t, m.Install(chart, "0.1.0", "test", "mks", map[string]interface{}{"replicas": 3.5}, false))
+	testutils.CheckLogEntry(t, hook, "Install of helm chart "+chart+" 0.1.0 as mks/test was successful. Status: deployed, revision: 2")
+
+	rel, e := store.Last("test")
+	require.NoError(t, e)
+	assert.Equal(t, map[string]interface{}{"replicas": 3.5}, rel.Config)
+	assert.Equal(t, "mks", rel.Namespace)
+
+	e = m.Install(filepath.Join(t.TempDir(), "missing"), "", "other", "mks", nil, false)
+	var releaseError *ReleaseError
+	assert.True(t, errors.As(e, &releaseError))
+}
+
+func Test_sdkManager_install_typedError(t *testing.T) {
+	m, _ := newTestSdkManager(t)
+	_, e := m.install(filepath.Join(t.TempDir(), "missing"), "", "test", "mks", nil, false)
+
+	var releaseError *ReleaseError
+	require.True(t, errors.As(e, &releaseError))
+	assert.Equal(t, "install", releaseError.Action)
+	assert.Equal(t, "test", releaseError.Release)
+	assert.Equal(t, "mks", releaseError.Namespace)
+}
+
+func Test_sdkManager_Uninstall(t *testing.T) {
+	tests := []struct {
+		name        string
+		purge       bool
+		wantHistory bool
+	}{
+		{"keep history", false, true},
+		{"purge", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := writeTestChart(t)
+			m, store := newTestSdkManager(t)
+			hook := test.NewGlobal()
+			require.NoError(t, m.Install(chart, "", "test", "mks", nil, false))
+
+			m.Uninstall("test", "mks", tt.purge)
+			testutils.CheckLogEntry(t, hook, "Helm release test successfully deleted.")
+
+			rel, e := store.Last("test")
+			if tt.wantHistory {
+				require.NoError(t, e)
+				assert.Equal(t, release.StatusUninstalled, rel.Info.Status)
+			} else {
+				assert.True(t, errors.Is(e, driver.ErrReleaseNotFound))
+			}
+
+			m.Uninstall("unknown", "mks", tt.purge)
+			testutils.CheckLogEntry(t, hook, "Can not delete helm release unknown")
+		})
+	}
+}
+
+func Test_sdkManager_AddRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, "apiVersion: v1\nentries: {}\n")
+	}))
+	defer server.Close()
+
+	m, _ := newTestSdkManager(t)
+	require.NoError(t, m.AddRepository("test", server.URL))
+	require.NoError(t, m.UpdateRepository())
+
+	file, e := repo.LoadFile(m.settings.RepositoryConfig)
+	require.NoError(t, e)
+	assert.True(t, file.Has("test"))
+	assert.FileExists(t, filepath.Join(m.settings.RepositoryCache, "test-index.yaml"))
+
+	e = m.AddRepository("broken", server.URL+"/broken")
+	var repositoryError *RepositoryError
+	require.True(t, errors.As(e, &repositoryError))
+	assert.Equal(t, "broken", repositoryError.Name)
+}
+
+func Test_sdkManager_ResolveChart(t *testing.T) {
+	m, _ := newTestSdkManager(t)
+	require.NoError(t, os.MkdirAll(m.settings.RepositoryCache, 0755))
+	index := `apiVersion: v1
+entries:
+  cert-manager:
+  - name: cert-manager
+    version: v1.15.0
+    appVersion: v1.15.0
+  - name: cert-manager
+    version: v1.14.5
+    appVersion: v1.14.5
+`
+	require.NoError(t, os.WriteFile(filepath.Join(m.settings.RepositoryCache, "jetstack-index.yaml"), []byte(index), 0644))
+
+	tests := []struct {
+		name    string
+		chart   string
+		version string
+		want    *ChartVersion
+		wantErr bool
+	}{
+		{"latest", "jetstack/cert-manager", "", &ChartVersion{Chart: "jetstack/cert-manager", Version: "v1.15.0", AppVersion: "v1.15.0"}, false},
+		{"exact", "jetstack/cert-manager", "v1.14.5", &ChartVersion{Chart: "jetstack/cert-manager", Version: "v1.14.5", AppVersion: "v1.14.5"}, false},
+		{"constraint", "jetstack/cert-manager", "~1.14", &ChartVersion{Chart: "jetstack/cert-manager", Version: "v1.14.5", AppVersion: "v1.14.5"}, false},
+		{"unknown version", "jetstack/cert-manager", "v2.0.0", nil, true},
+		{"unknown repository", "other/cert-manager", "", nil, true},
+		{"no repository", "cert-manager", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.ResolveChart(tt.chart, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveChart() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		want    Manager
+		wantErr bool
+	}{
+		{"default", "", &sdkManager{}, false},
+		{"sdk", KindSdk, &sdkManager{}, false},
+		{"unknown", "other", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind := tt.kind
+			m := NewManager(&kind, fake.NewContextHandler(nil, nil)).(*selectingManager)
+			got, err := m.get()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.IsType(t, tt.want, got)
+		})
+	}
+}
+
+// newTestSdkManager creates a sdk manager which stores the releases in memory and does not access a cluster.
+func newTestSdkManager(t *testing.T) (*sdkManager, *storage.Storage) {
+	dir := t.TempDir()
+	store := storage.Init(driver.NewMemory())
+	settings := cli.New()
+	settings.RepositoryConfig = filepath.Join(dir, "repositories.yaml")
+	settings.RepositoryCache = filepath.Join(dir, "cache")
+	m := &sdkManager{
+		context:  fake.NewContextHandler(nil, nil),
+		settings: settings,
+		newConfig: func(_ string) (*action.Configuration, error) {
+			return &action.Configuration{
+				Releases:     store,
+				KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+				Capabilities: chartutil.DefaultCapabilities,
+				Log:          t.Logf,
+			}, nil
+		},
+	}
+	return m, store
+}
+
+// writeTestChart writes a minimal chart into a temporary directory and returns its path.
+func writeTestChart(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "test")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("apiVersion: v2\nname: test\nversion: 0.1.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "configmap.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\ndata:\n  replicas: {{ .Values.replicas | quote }}\n"), 0644))
+	return dir
+}
+
+func Test_sdkManager_Rollback(t *testing.T) {
+	chart := writeTestChart(t)
+	m, _ := newTestSdkManager(t)
+	require.NoError(t, m.Install(chart, "", "test", "mks", map[string]interface{}{"replicas": "1"}, false))
+	require.NoError(t, m.Install(chart, "", "test", "mks", map[string]interface{}{"replicas": "2"}, false))
+
+	assert.NoError(t, m.Rollback("test", "mks", 0))
+
+	history, e := m.History("test", "mks")
+	assert.NoError(t, e)
+	assert.Len(t, history, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{history[0].Revision, history[1].Revision, history[2].Revision})
+	assert.Equal(t, "test-0.1.0", history[2].Chart)
+	assert.Equal(t, "Rollback to 1", history[2].Description)
+
+	status, e := m.Status("test", "mks")
+	assert.NoError(t, e)
+	assert.Equal(t, 3, status.Revision)
+	assert.Equal(t, "deployed", status.Status)
+
+	_, e = m.Status("other", "mks")
+	var releaseError *ReleaseError
+	assert.True(t, errors.As(e, &releaseError))
+	assert.Error(t, m.Rollback("other", "mks", 0))
+}
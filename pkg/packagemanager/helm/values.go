@@ -0,0 +1,197 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/strvals"
+	"sigs.k8s.io/yaml"
+)
+
+// StringValue is a default value which is always passed as string like with helm --set-string. Use it for values
+// like annotations that would be typed as number or bool otherwise.
+type StringValue string
+
+// Overrides are the values files and values given by the user for the helm plugins. They are merged over the
+// default values of the plugins.
+type Overrides struct {
+	// Files are values files given as <plugin>=<file>.
+	Files []string
+	// Set are single values given as <plugin>.<key>=<value> like with helm --set.
+	Set []string
+}
+
+// Values returns the default values of the plugin merged with the values files and values given for it. The
+// defaults use dotted keys and are typed like values passed with --set. Later overrides win.
+func (o *Overrides) Values(plugin string, defaults map[string]interface{}) (map[string]interface{}, error) {
+	values, e := ParseValues(defaults)
+	if e != nil {
+		return nil, e
+	}
+	if o == nil {
+		return values, nil
+	}
+
+	for _, entry := range o.Files {
+		name, file, found := strings.Cut(entry, "=")
+		if !found || name == "" || file == "" {
+			return nil, fmt.Errorf("invalid values file %s, expected <plugin>=<file>", entry)
+		}
+		if name != plugin {
+			continue
+		}
+		fileValues, e := readValuesFile(file)
+		if e != nil {
+			return nil, e
+		}
+		values = MergeValues(values, fileValues)
+	}
+
+	for _, entry := range o.Set {
+		name, value, found := strings.Cut(entry, ".")
+		if !found || name == "" || !strings.Contains(value, "=") {
+			return nil, fmt.Errorf("invalid value %s, expected <plugin>.<key>=<value>", entry)
+		}
+		if name != plugin {
+			continue
+		}
+		setValues := map[string]interface{}{}
+		if e := strvals.ParseInto(value, setValues); e != nil {
+			return nil, fmt.Errorf("can not parse value %s: %s", entry, e)
+		}
+		values = MergeValues(values, setValues)
+	}
+	return values, nil
+}
+
+// Plugins returns the names of all plugins which have overrides.
+func (o *Overrides) Plugins() []string {
+	if o == nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, entry := range o.Files {
+		name, _, _ := strings.Cut(entry, "=")
+		names[name] = true
+	}
+	for _, entry := range o.Set {
+		name, _, _ := strings.Cut(entry, ".")
+		names[name] = true
+	}
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// MergeValues merges the override values deeply over the base values. Nested maps are merged, all other values
+// of the overrides replace the base values. Neither map is modified.
+func MergeValues(base map[string]interface{}, overrides map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overrides {
+		if override, ok := v.(map[string]interface{}); ok {
+			if current, ok := result[k].(map[string]interface{}); ok {
+				result[k] = MergeValues(current, override)
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// readValuesFile reads the values of a yaml values file.
+func readValuesFile(file string) (map[string]interface{}, error) {
+	bytes, e := os.ReadFile(file)
+	if e != nil {
+		return nil, fmt.Errorf("can not read values file %s: %s", file, e)
+	}
+	values := map[string]interface{}{}
+	if e := yaml.Unmarshal(bytes, &values); e != nil {
+		return nil, fmt.Errorf("can not parse values file %s: %s", file, e)
+	}
+	return values, nil
+}
+
+// writeValuesFile writes the values into a temporary yaml values file for the helm binary. The returned function
+// removes the file again. It is a variable, so that tests can write to a known path.
+var writeValuesFile = func(values map[string]interface{}) (string, func(), error) {
+	bytes, e := yaml.Marshal(values)
+	if e != nil {
+		return "", nil, fmt.Errorf("can not marshal values: %s", e)
+	}
+	file, e := os.CreateTemp("", "minikube-support-values-*.yaml")
+	if e != nil {
+		return "", nil, fmt.Errorf("can not create values file: %s", e)
+	}
+	remove := func() { _ = os.Remove(file.Name()) }
+	_, e = file.Write(bytes)
+	if ce := file.Close(); e == nil {
+		e = ce
+	}
+	if e != nil {
+		remove()
+		return "", nil, fmt.Errorf("can not write values file: %s", e)
+	}
+	return file.Name(), remove, nil
+}
+
+// ParseValues converts the values with dotted keys into the nested values of a chart. String values are typed
+// like values passed with `--set`, all other values are kept as they are, including floats, slices and string
+// values of type StringValue.
+func ParseValues(values map[string]interface{}) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := map[string]interface{}{}
+	for _, k := range keys {
+		switch v := values[k].(type) {
+		case string:
+			if e := strvals.ParseInto(k+"="+strings.ReplaceAll(v, ",", "\\,"), result); e != nil {
+				return nil, fmt.Errorf("can not convert value %s: %s", k, e)
+			}
+		case StringValue:
+			if e := strvals.ParseIntoString(k+"="+strings.ReplaceAll(string(v), ",", "\\,"), result); e != nil {
+				return nil, fmt.Errorf("can not convert value %s: %s", k, e)
+			}
+		case map[string]interface{}:
+			nested, e := ParseValues(v)
+			if e != nil {
+				return nil, e
+			}
+			for nk, nv := range nested {
+				if e := setJSON(k+"."+nk, nv, result); e != nil {
+					return nil, e
+				}
+			}
+		default:
+			if e := setJSON(k, v, result); e != nil {
+				return nil, e
+			}
+		}
+	}
+	return result, nil
+}
+
+// setJSON sets the value at the dotted key path.
+func setJSON(key string, value interface{}, dest map[string]interface{}) error {
+	bytes, e := json.Marshal(value)
+	if e != nil {
+		return fmt.Errorf("can not convert value %s: %s", key, e)
+	}
+	if e := strvals.ParseJSON(key+"="+string(bytes), dest); e != nil {
+		return fmt.Errorf("can not convert value %s: %s", key, e)
+	}
+	return nil
+}
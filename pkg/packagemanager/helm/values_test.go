@@ -0,0 +1,155 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{"empty", map[string]interface{}{}, map[string]interface{}{}, false},
+		{
+			"typed strings",
+			map[string]interface{}{"installCRDs": "true", "replicas": "2", "name": "a,b"},
+			map[string]interface{}{"installCRDs": true, "replicas": int64(2), "name": "a,b"},
+			false,
+		},
+		{
+			"string values",
+			map[string]interface{}{"annotations.proxy-body-size": StringValue("0"), "enabled": StringValue("true"), "name": StringValue("a,b")},
+			map[string]interface{}{"annotations": map[string]interface{}{"proxy-body-size": "0"}, "enabled": "true", "name": "a,b"},
+			false,
+		},
+		{
+			"dotted keys",
+			map[string]interface{}{"ingressShim.defaultIssuerName": "ca-issuer", "ingressShim.defaultIssuerKind": "ClusterIssuer"},
+			map[string]interface{}{"ingressShim": map[string]interface{}{"defaultIssuerName": "ca-issuer", "defaultIssuerKind": "ClusterIssuer"}},
+			false,
+		},
+		{
+			"floats and slices",
+			map[string]interface{}{"ratio": 0.5, "hosts": []string{"a.minikube", "b.minikube"}},
+			map[string]interface{}{"ratio": 0.5, "hosts": []interface{}{"a.minikube", "b.minikube"}},
+			false,
+		},
+		{
+			"nested maps",
+			map[string]interface{}{"controller": map[string]interface{}{"service.type": "NodePort", "replicas": 1}},
+			map[string]interface{}{"controller": map[string]interface{}{"service": map[string]interface{}{"type": "NodePort"}, "replicas": float64(1)}},
+			false,
+		},
+		{"invalid value", map[string]interface{}{"a": make(chan int)}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValues(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_writeValuesFile(t *testing.T) {
+	values := map[string]interface{}{
+		"controller": map[string]interface{}{
+			"annotations": map[string]interface{}{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+			"replicas":    2,
+		},
+		"hosts": "a.minikube,b.minikube",
+	}
+
+	file, remove, e := writeValuesFile(values)
+	require.NoError(t, e)
+	read, e := readValuesFile(file)
+	remove()
+
+	require.NoError(t, e)
+	assert.Equal(t, map[string]interface{}{
+		"controller": map[string]interface{}{
+			"annotations": map[string]interface{}{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+			"replicas":    float64(2),
+		},
+		"hosts": "a.minikube,b.minikube",
+	}, read)
+	assert.NoFileExists(t, file)
+}
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]interface{}{
+		"controller": map[string]interface{}{"replicaCount": int64(1), "service": map[string]interface{}{"type": "LoadBalancer"}},
+		"hosts":      []interface{}{"a.minikube"},
+	}
+	overrides := map[string]interface{}{
+		"controller": map[string]interface{}{"service": map[string]interface{}{"type": "NodePort"}, "hostNetwork": true},
+		"hosts":      []interface{}{"b.minikube"},
+	}
+
+	got := MergeValues(base, overrides)
+
+	assert.Equal(t, map[string]interface{}{
+		"controller": map[string]interface{}{"replicaCount": int64(1), "service": map[string]interface{}{"type": "NodePort"}, "hostNetwork": true},
+		"hosts":      []interface{}{"b.minikube"},
+	}, got)
+	assert.Equal(t, "LoadBalancer", base["controller"].(map[string]interface{})["service"].(map[string]interface{})["type"])
+}
+
+func TestOverrides_Values(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "ingress.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("controller:\n  hostNetwork: true\n  resources:\n    requests:\n      cpu: 100m\n  metrics:\n    enabled: \"true\"\n"), 0644))
+	defaults := map[string]interface{}{"controller.publishService.enabled": "true", "controller.metrics.enabled": "false"}
+
+	tests := []struct {
+		name      string
+		overrides *Overrides
+		want      map[string]interface{}
+		wantErr   bool
+	}{
+		{"no overrides", nil, map[string]interface{}{"controller": map[string]interface{}{"publishService": map[string]interface{}{"enabled": true}, "metrics": map[string]interface{}{"enabled": false}}}, false},
+		{"other plugin", &Overrides{Files: []string{"certManager=" + file}, Set: []string{"certManager.replicaCount=2"}}, map[string]interface{}{"controller": map[string]interface{}{"publishService": map[string]interface{}{"enabled": true}, "metrics": map[string]interface{}{"enabled": false}}}, false},
+		{
+			"file and set",
+			&Overrides{Files: []string{"ingress-controller=" + file}, Set: []string{"ingress-controller.controller.replicaCount=2", "ingress-controller.controller.hostNetwork=false"}},
+			map[string]interface{}{"controller": map[string]interface{}{
+				"publishService": map[string]interface{}{"enabled": true},
+				"metrics":        map[string]interface{}{"enabled": "true"},
+				"hostNetwork":    false,
+				"replicaCount":   int64(2),
+				"resources":      map[string]interface{}{"requests": map[string]interface{}{"cpu": "100m"}},
+			}},
+			false,
+		},
+		{"missing file", &Overrides{Files: []string{"ingress-controller=" + filepath.Join(dir, "missing.yaml")}}, nil, true},
+		{"invalid file", &Overrides{Files: []string{file}}, nil, true},
+		{"invalid set", &Overrides{Set: []string{"replicaCount"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := tt.overrides.Values("ingress-controller", defaults)
+			if tt.wantErr {
+				assert.Error(t, e)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOverrides_Plugins(t *testing.T) {
+	o := &Overrides{Files: []string{"ingress-controller=a.yaml", "certManager=b.yaml"}, Set: []string{"ingress-controller.a=b", "registry.c=d"}}
+	assert.Equal(t, []string{"certManager", "ingress-controller", "registry"}, o.Plugins())
+	assert.Empty(t, (*Overrides)(nil).Plugins())
+}
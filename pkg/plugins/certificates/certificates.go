@@ -0,0 +1,397 @@
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/utils"
+)
+
+const (
+	pluginName = "certificates"
+	// certificateNameAnnotation references the certificate of a certificate request.
+	certificateNameAnnotation = "cert-manager.io/certificate-name"
+	highlightStart            = "\033[31m"
+	highlightEnd              = "\033[0m"
+)
+
+var (
+	certificatesResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	requestsResource     = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificaterequests"}
+	// retryDelay is the time to wait before checking again whether cert-manager is installed.
+	retryDelay = 10 * time.Second
+)
+
+// certificates is a plugin that shows the state of all cert-manager certificates including their expiry
+// and highlights failed issuances.
+type certificates struct {
+	contextHandler kubernetes.ContextHandler
+	messageChannel chan *apis.MonitoringMessage
+	watches        []*kubernetes.Watcher
+	certificates   *store
+	requests       *store
+	stop           chan struct{}
+	waiting        sync.WaitGroup
+	mutex          sync.Mutex
+}
+
+// NewCertificates creates the plugin which watches the cert-manager Certificates and CertificateRequests.
+func NewCertificates(handler kubernetes.ContextHandler) apis.StartStopPlugin {
+	c := &certificates{contextHandler: handler, mutex: sync.Mutex{}}
+	c.certificates = &store{plugin: c, objects: map[string]*unstructured.Unstructured{}}
+	c.requests = &store{plugin: c, objects: map[string]*unstructured.Unstructured{}}
+	return c
+}
+
+func (c *certificates) String() string {
+	return pluginName
+}
+
+func (*certificates) IsSingleRunnable() bool {
+	return true
+}
+
+// Start starts watching the certificates and certificate requests of all namespaces. If cert-manager is not
+// installed yet, it is checked again every retryDelay until the watch can be started.
+func (c *certificates) Start(messageChannel chan *apis.MonitoringMessage) (string, error) {
+	client, e := c.contextHandler.GetDynamicClient()
+	if e != nil {
+		return "", fmt.Errorf("can not get dynamic client: %s", e)
+	}
+	c.mutex.Lock()
+	c.messageChannel = messageChannel
+	c.stop = make(chan struct{})
+	stop := c.stop
+	c.mutex.Unlock()
+
+	if !c.installed(client) {
+		c.waiting.Add(1)
+		go c.waitForCertManager(client, stop)
+		return pluginName, nil
+	}
+	if e := c.watch(client); e != nil {
+		_ = c.Stop()
+		return "", e
+	}
+	return pluginName, nil
+}
+
+// Stop stops watching the certificates.
+func (c *certificates) Stop() error {
+	c.mutex.Lock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	c.mutex.Unlock()
+	c.waiting.Wait()
+
+	c.mutex.Lock()
+	watches := c.watches
+	c.watches = nil
+	c.mutex.Unlock()
+	for _, w := range watches {
+		w.Stop()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.certificates.objects = map[string]*unstructured.Unstructured{}
+	c.requests.objects = map[string]*unstructured.Unstructured{}
+	return nil
+}
+
+// installed checks if the certificates can be listed. Otherwise, the error is sent to the box.
+func (c *certificates) installed(client dynamic.Interface) bool {
+	_, e := client.Resource(certificatesResource).List(context.Background(), metav1.ListOptions{Limit: 1})
+	if e == nil {
+		return true
+	}
+	c.mutex.Lock()
+	messageChannel := c.messageChannel
+	c.mutex.Unlock()
+	messageChannel <- &apis.MonitoringMessage{Box: pluginName, Message: fmt.Sprintf("Can not list certificates. Is cert-manager installed?\n%s", e)}
+	return false
+}
+
+// waitForCertManager starts the watch as soon as cert-manager is installed or until the stop channel is closed.
+func (c *certificates) waitForCertManager(client dynamic.Interface, stop chan struct{}) {
+	defer c.waiting.Done()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(retryDelay):
+		}
+		if !c.installed(client) {
+			continue
+		}
+		if e := c.watch(client); e != nil {
+			c.mutex.Lock()
+			messageChannel := c.messageChannel
+			c.mutex.Unlock()
+			messageChannel <- &apis.MonitoringMessage{Box: pluginName, Message: e.Error()}
+		}
+		return
+	}
+}
+
+// watch starts the watchers of the certificates and certificate requests.
+func (c *certificates) watch(client dynamic.Interface) error {
+	informers := kubernetes.NewInformers(kubernetes.DefaultResyncPeriod)
+	for _, watched := range []struct {
+		resource schema.GroupVersionResource
+		store    *store
+	}{{certificatesResource, c.certificates}, {requestsResource, c.requests}} {
+		w, e := informers.Watch(resourceAccessor{client: client, resource: watched.resource}, &metav1.ListOptions{}, watched.store)
+		if e != nil {
+			return fmt.Errorf("can not start watcher for %s: %s", watched.resource.Resource, e)
+		}
+		c.mutex.Lock()
+		c.watches = append(c.watches, w)
+		c.mutex.Unlock()
+	}
+	return nil
+}
+
+// sendStatus sends the certificates as table to the box. Failed issuances are highlighted and their reasons are
+// listed below the table.
+func (c *certificates) sendStatus() {
+	c.mutex.Lock()
+	var lines []string
+	var failures []string
+	failed := map[string]bool{}
+	for key, certificate := range c.certificates.objects {
+		lines = append(lines, fmt.Sprintf("%s\t %s\t %s\t %s\t %s\n", key, readyStatus(certificate), dnsNames(certificate), issuer(certificate), expiry(certificate, time.Now())))
+		if reason := c.failure(certificate); reason != "" {
+			failed[key] = true
+			failures = append(failures, fmt.Sprintf("%s: %s", key, reason))
+		}
+	}
+	messageChannel := c.messageChannel
+	c.mutex.Unlock()
+
+	table, e := utils.FormatAsTable(lines, "Certificate\t Ready\t DNS Names\t Issuer\t Expires\n")
+	if e != nil {
+		table = e.Error()
+	}
+	table = highlight(table, failed)
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		table += "\n" + highlightStart + "Failed issuances:" + highlightEnd + "\n" + strings.Join(failures, "\n") + "\n"
+	}
+	messageChannel <- &apis.MonitoringMessage{Box: pluginName, Message: table}
+}
+
+// failure returns the reason why the issuance of the given certificate failed or an empty string if it has
+// not failed. The caller must hold the mutex.
+func (c *certificates) failure(certificate *unstructured.Unstructured) string {
+	if request := c.latestRequest(certificate); request != nil {
+		for _, conditionType := range []string{"Denied", "InvalidRequest"} {
+			if condition := findCondition(request, conditionType); condition != nil && condition.status == "True" {
+				return fmt.Sprintf("request %s %s", request.GetName(), condition)
+			}
+		}
+		if condition := findCondition(request, "Ready"); condition != nil && condition.status == "False" && condition.reason == "Failed" {
+			return fmt.Sprintf("request %s %s", request.GetName(), condition)
+		}
+	}
+	if condition := findCondition(certificate, "Issuing"); condition != nil && condition.status == "False" && condition.reason == "Failed" {
+		return condition.String()
+	}
+	return ""
+}
+
+// latestRequest returns the newest certificate request of the given certificate. The caller must hold the mutex.
+func (c *certificates) latestRequest(certificate *unstructured.Unstructured) *unstructured.Unstructured {
+	var latest *unstructured.Unstructured
+	for _, request := range c.requests.objects {
+		if request.GetNamespace() != certificate.GetNamespace() || request.GetAnnotations()[certificateNameAnnotation] != certificate.GetName() {
+			continue
+		}
+		if latest == nil || latest.GetCreationTimestamp().Time.Before(request.GetCreationTimestamp().Time) {
+			latest = request
+		}
+	}
+	return latest
+}
+
+// highlight colors all table lines of the given certificates.
+func highlight(table string, keys map[string]bool) string {
+	lines := strings.Split(table, "\n")
+	for i, line := range lines {
+		key, _, _ := strings.Cut(line, " ")
+		if keys[key] {
+			lines[i] = highlightStart + line + highlightEnd
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// condition is a single status condition of a cert-manager resource.
+type condition struct {
+	status  string
+	reason  string
+	message string
+}
+
+func (c *condition) String() string {
+	if c.message == "" {
+		return c.reason
+	}
+	return c.reason + ": " + c.message
+}
+
+// findCondition returns the status condition of the given type or nil if there is none.
+func findCondition(obj *unstructured.Unstructured, conditionType string) *condition {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		values, ok := c.(map[string]interface{})
+		if !ok || values["type"] != conditionType {
+			continue
+		}
+		status, _ := values["status"].(string)
+		reason, _ := values["reason"].(string)
+		message, _ := values["message"].(string)
+		return &condition{status: status, reason: reason, message: message}
+	}
+	return nil
+}
+
+func readyStatus(certificate *unstructured.Unstructured) string {
+	ready := findCondition(certificate, "Ready")
+	if ready == nil {
+		return "Unknown"
+	}
+	if ready.status != "True" && ready.reason != "" {
+		return ready.status + " (" + ready.reason + ")"
+	}
+	return ready.status
+}
+
+func dnsNames(certificate *unstructured.Unstructured) string {
+	names, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+func issuer(certificate *unstructured.Unstructured) string {
+	name, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+	kind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+	if kind == "" {
+		kind = "Issuer"
+	}
+	return kind + "/" + name
+}
+
+// expiry returns the days until the certificate expires.
+func expiry(certificate *unstructured.Unstructured, now time.Time) string {
+	value, _, _ := unstructured.NestedString(certificate.Object, "status", "notAfter")
+	notAfter, e := time.Parse(time.RFC3339, value)
+	if e != nil {
+		return "-"
+	}
+	if !notAfter.After(now) {
+		return "expired"
+	}
+	return fmt.Sprintf("%d days", int(notAfter.Sub(now).Hours()/24))
+}
+
+// store holds the watched objects of one resource.
+type store struct {
+	plugin  *certificates
+	objects map[string]*unstructured.Unstructured
+}
+
+func (s *store) AddedEvent(obj runtime.Object) error {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("can not handle non unstructured object")
+	}
+	s.plugin.mutex.Lock()
+	defer s.plugin.mutex.Unlock()
+	s.objects[object.GetNamespace()+"/"+object.GetName()] = object
+	return nil
+}
+
+func (s *store) UpdatedEvent(obj runtime.Object) error {
+	return s.AddedEvent(obj)
+}
+
+func (s *store) DeletedEvent(obj runtime.Object) error {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("can not handle non unstructured object")
+	}
+	s.plugin.mutex.Lock()
+	defer s.plugin.mutex.Unlock()
+	delete(s.objects, object.GetNamespace()+"/"+object.GetName())
+	return nil
+}
+
+func (s *store) Reconcile(objects []runtime.Object) error {
+	existing := map[string]bool{}
+	for _, obj := range objects {
+		if object, ok := obj.(*unstructured.Unstructured); ok {
+			existing[object.GetNamespace()+"/"+object.GetName()] = true
+		}
+	}
+	s.plugin.mutex.Lock()
+	defer s.plugin.mutex.Unlock()
+	for key := range s.objects {
+		if !existing[key] {
+			delete(s.objects, key)
+		}
+	}
+	return nil
+}
+
+func (s *store) PostEvent() error {
+	s.plugin.sendStatus()
+	return nil
+}
+
+// resourceAccessor provides list and watch access to a cert-manager resource in all namespaces.
+type resourceAccessor struct {
+	client   dynamic.Interface
+	resource schema.GroupVersionResource
+}
+
+// Object returns an empty unstructured object.
+func (resourceAccessor) Object() runtime.Object {
+	return &unstructured.Unstructured{}
+}
+
+// Key identifies the resource.
+func (r resourceAccessor) Key() string {
+	return r.resource.String()
+}
+
+// List returns a list of all objects of the resource.
+func (r resourceAccessor) List(options metav1.ListOptions) (runtime.Object, error) {
+	list, e := r.client.Resource(r.resource).List(context.Background(), options)
+	if e != nil {
+		return nil, fmt.Errorf("can not list %s: %s", r.resource.Resource, e)
+	}
+	return list, nil
+}
+
+// Watch starts the watch process for the objects of the resource.
+func (r resourceAccessor) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return r.client.Resource(r.resource).Watch(context.Background(), options)
+}
@@ -0,0 +1,200 @@
+package certificates
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	k8sTesting "k8s.io/client-go/testing"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+)
+
+func Test_certificates_StartStop(t *testing.T) {
+	notAfter := time.Now().Add(30*24*time.Hour + time.Hour).UTC().Format(time.RFC3339)
+	client := newDynamicClient(
+		certificate("default", "app", "Ready", "True", "Ready", "", notAfter),
+		certificate("default", "broken", "Ready", "False", "DoesNotExist", "Issuing certificate as Secret does not exist", ""),
+		request("default", "broken-1", "broken", time.Now().Add(-time.Hour), "Ready", "False", "Pending", "waiting"),
+		request("default", "broken-2", "broken", time.Now(), "Ready", "False", "Failed", "issuer not found"),
+	)
+	messages := make(chan *apis.MonitoringMessage, 10)
+	plugin := NewCertificates(fake.NewContextHandler(nil, client))
+
+	box, e := plugin.Start(messages)
+	assert.NoError(t, e)
+	assert.Equal(t, "certificates", box)
+
+	message := lastMessage(messages)
+	assert.Contains(t, message, "default/app")
+	assert.Contains(t, message, "app.minikube")
+	assert.Contains(t, message, "ClusterIssuer/ca-issuer")
+	assert.Contains(t, message, "30 days")
+	assert.Contains(t, message, highlightStart+"default/broken ")
+	assert.Contains(t, message, "False (DoesNotExist)")
+	assert.Contains(t, message, "default/broken: request broken-2 Failed: issuer not found")
+	assert.NotContains(t, message, highlightStart+"default/app ")
+
+	assert.NoError(t, plugin.Stop())
+}
+
+func Test_certificates_Start_noCertManager(t *testing.T) {
+	retryDelay = 10 * time.Millisecond
+	client := newDynamicClient(certificate("default", "app", "Ready", "True", "Ready", "", ""))
+	installed := make(chan struct{})
+	client.PrependReactor("list", "certificates", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		select {
+		case <-installed:
+			return false, nil, nil
+		default:
+			return true, nil, errors.NewNotFound(certificatesResource.GroupResource(), "")
+		}
+	})
+	messages := make(chan *apis.MonitoringMessage, 100)
+	plugin := NewCertificates(fake.NewContextHandler(nil, client))
+
+	box, e := plugin.Start(messages)
+
+	assert.NoError(t, e)
+	assert.Equal(t, "certificates", box)
+	assert.True(t, strings.HasPrefix((<-messages).Message, "Can not list certificates. Is cert-manager installed?"))
+
+	close(installed)
+	assert.Eventually(t, func() bool {
+		for {
+			select {
+			case m := <-messages:
+				if strings.Contains(m.Message, "default/app") {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, plugin.Stop())
+}
+
+func Test_certificates_Stop_noCertManager(t *testing.T) {
+	retryDelay = time.Hour
+	client := newDynamicClient()
+	client.PrependReactor("list", "certificates", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(certificatesResource.GroupResource(), "")
+	})
+	plugin := NewCertificates(fake.NewContextHandler(nil, client))
+
+	_, e := plugin.Start(make(chan *apis.MonitoringMessage, 10))
+
+	assert.NoError(t, e)
+	assert.NoError(t, plugin.Stop())
+}
+
+func Test_expiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		notAfter string
+		want     string
+	}{
+		{"valid", "2026-01-11T12:00:00Z", "10 days"},
+		{"expired", "2025-12-31T00:00:00Z", "expired"},
+		{"not issued", "", "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, expiry(certificate("default", "app", "Ready", "True", "", "", tt.notAfter), now))
+		})
+	}
+}
+
+func Test_certificates_failure(t *testing.T) {
+	tests := []struct {
+		name        string
+		certificate *unstructured.Unstructured
+		requests    []*unstructured.Unstructured
+		want        string
+	}{
+		{"ready", certificate("default", "app", "Ready", "True", "Ready", "", ""), nil, ""},
+		{"issuing failed", certificate("default", "app", "Issuing", "False", "Failed", "backoff", ""), nil, "Failed: backoff"},
+		{"request denied", certificate("default", "app", "Ready", "False", "", "", ""),
+			[]*unstructured.Unstructured{request("default", "app-1", "app", time.Now(), "Denied", "True", "Denied", "not approved")},
+			"request app-1 Denied: not approved"},
+		{"request of other certificate", certificate("default", "app", "Ready", "False", "", "", ""),
+			[]*unstructured.Unstructured{request("default", "other-1", "other", time.Now(), "Ready", "False", "Failed", "failed")},
+			""},
+		{"pending", certificate("default", "app", "Ready", "False", "", "", ""),
+			[]*unstructured.Unstructured{request("default", "app-1", "app", time.Now(), "Ready", "False", "Pending", "waiting")},
+			""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCertificates(nil).(*certificates)
+			for _, r := range tt.requests {
+				c.requests.objects[r.GetNamespace()+"/"+r.GetName()] = r
+			}
+			assert.Equal(t, tt.want, c.failure(tt.certificate))
+		})
+	}
+}
+
+func lastMessage(messages chan *apis.MonitoringMessage) string {
+	var message string
+	for {
+		select {
+		case m := <-messages:
+			message = m.Message
+		default:
+			return message
+		}
+	}
+}
+
+func newDynamicClient(objects ...runtime.Object) *dynamicFake.FakeDynamicClient {
+	return dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			certificatesResource: "CertificateList",
+			requestsResource:     "CertificateRequestList",
+		}, objects...)
+}
+
+func certificate(namespace string, name string, conditionType string, status string, reason string, message string, notAfter string) *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretName": name + "-tls",
+			"dnsNames":   []interface{}{name + ".minikube"},
+			"issuerRef":  map[string]interface{}{"name": "ca-issuer", "kind": "ClusterIssuer"},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": conditionType, "status": status, "reason": reason, "message": message}},
+		},
+	}}
+	if notAfter != "" {
+		_ = unstructured.SetNestedField(certificate.Object, notAfter, "status", "notAfter")
+	}
+	certificate.SetGroupVersionKind(certificatesResource.GroupVersion().WithKind("Certificate"))
+	certificate.SetNamespace(namespace)
+	certificate.SetName(name)
+	return certificate
+}
+
+func request(namespace string, name string, certificateName string, created time.Time, conditionType string, status string, reason string, message string) *unstructured.Unstructured {
+	request := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": conditionType, "status": status, "reason": reason, "message": message}},
+		},
+	}}
+	request.SetGroupVersionKind(requestsResource.GroupVersion().WithKind("CertificateRequest"))
+	request.SetNamespace(namespace)
+	request.SetName(name)
+	request.SetAnnotations(map[string]string{certificateNameAnnotation: certificateName})
+	request.SetCreationTimestamp(metav1.NewTime(created))
+	return request
+}
@@ -3,9 +3,6 @@ package certmanager
 import (
 	"context"
 	"fmt"
-	"os"
-	"path"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -17,10 +14,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/ca"
 	"github.com/qaware/minikube-support/pkg/github"
 	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
-	"github.com/qaware/minikube-support/pkg/sh"
 )
 
 type certManager struct {
@@ -28,21 +25,36 @@ type certManager struct {
 	contextHandler kubernetes.ContextHandler
 	namespace      string
 	values         map[string]interface{}
+	overrides      *helm.Overrides
 	ctx            context.Context
 }
 
 const PluginName = "certManager"
 const issuerName = "ca-issuer"
 const releaseName = "cert-manager"
+const (
+	repositoryName = "jetstack"
+	repositoryURL  = "https://charts.jetstack.io"
+	chart          = repositoryName + "/cert-manager"
+)
 
 var groupVersion = schema.GroupVersion{Group: "cert-manager.io", Version: "v1"}
-var helmInstallWaitPeriod = 20 * time.Second
+var waitTimeout = kubernetes.DefaultWaitTimeout
+
+// crds are the custom resource definitions of cert-manager which are used by the plugins.
+var crds = []string{
+	"certificates.cert-manager.io",
+	"certificaterequests.cert-manager.io",
+	"issuers.cert-manager.io",
+	"clusterissuers.cert-manager.io",
+}
 
-func NewCertManager(manager helm.Manager, handler kubernetes.ContextHandler, _ github.Client) apis.InstallablePlugin {
+func NewCertManager(manager helm.Manager, handler kubernetes.ContextHandler, _ github.Client, overrides *helm.Overrides) apis.InstallablePlugin {
 	return &certManager{
 		manager:        manager,
 		contextHandler: handler,
 		values:         map[string]interface{}{},
+		overrides:      overrides,
 		namespace:      "mks",
 		ctx:            context.Background(),
 	}
@@ -53,7 +65,7 @@ func (m *certManager) String() string {
 }
 
 func (m *certManager) Install() {
-	if e := m.manager.AddRepository("jetstack", "https://charts.jetstack.io"); e != nil {
+	if e := m.manager.AddRepository(repositoryName, repositoryURL); e != nil {
 		logrus.Errorf("Unable to add jetstack repository: %s", e)
 		return
 	}
@@ -71,15 +83,21 @@ func (m *certManager) Update() {
 		return
 	}
 
-	m.values["ingressShim.defaultIssuerName"] = issuerName
-	m.values["ingressShim.defaultIssuerKind"] = "ClusterIssuer"
-	m.values["ingressShim.defaultIssuerGroup"] = "cert-manager.io"
-	m.values["installCRDs"] = "true"
-
-	m.manager.Install("jetstack/cert-manager", releaseName, m.namespace, m.values, true)
+	values, e := m.Values()
+	if e != nil {
+		logrus.Errorf("Unable to determine the values of cert manager: %s", e)
+		return
+	}
+	if e := m.manager.Install(chart, "", releaseName, m.namespace, values, true); e != nil {
+		logrus.Errorf("Unable to install cert manager: %s", e)
+		return
+	}
 
+	if e := m.Ready(); e != nil {
+		logrus.Errorf("Cert manager is not ready: %s", e)
+		return
+	}
 	var err *multierror.Error
-	time.Sleep(helmInstallWaitPeriod)
 	err = multierror.Append(err, m.applyCertSecret())
 	err = multierror.Append(err, m.applyClusterIssuer())
 
@@ -116,20 +134,59 @@ func (m *certManager) Phase() apis.Phase {
 	return apis.CLUSTER_TOOLS_INSTALL
 }
 
-func (m *certManager) applyCertSecret() error {
-	caRoot, e := sh.RunCmd("mkcert", "-CAROOT")
+func (m *certManager) Release() (string, string) {
+	return releaseName, m.namespace
+}
+
+// Ready checks if cert-manager and its webhook are available.
+func (m *certManager) Ready() error {
+	return WaitUntilReady(m.contextHandler, m.namespace, waitTimeout)
+}
+
+// Artifacts returns the cert-manager chart.
+func (m *certManager) Artifacts() apis.Artifacts {
+	values, e := m.Values()
 	if e != nil {
-		return fmt.Errorf("unable to get the mkcert CA root: %s", e)
+		logrus.Warnf("Unable to determine the values of cert manager: %s", e)
+	}
+	return apis.Artifacts{Charts: []apis.ChartArtifact{{RepositoryName: repositoryName, RepositoryURL: repositoryURL, Chart: chart, Values: values}}}
+}
+
+// Values returns the default values merged with the values given by the user.
+func (m *certManager) Values() (map[string]interface{}, error) {
+	m.setValues()
+	return m.overrides.Values(PluginName, m.values)
+}
+
+func (m *certManager) setValues() {
+	m.values["ingressShim.defaultIssuerName"] = issuerName
+	m.values["ingressShim.defaultIssuerKind"] = "ClusterIssuer"
+	m.values["ingressShim.defaultIssuerGroup"] = "cert-manager.io"
+	m.values["installCRDs"] = "true"
+}
+
+// WaitUntilReady waits until the custom resource definitions of cert-manager are established, its deployments
+// in the given namespace are available and its webhook accepts requests.
+func WaitUntilReady(handler kubernetes.ContextHandler, namespace string, timeout time.Duration) error {
+	waiter := kubernetes.NewWaiter(handler, timeout)
+	if e := waiter.CRDs(crds...); e != nil {
+		return e
 	}
-	caRoot = strings.Trim(caRoot, "\r\n \t")
+	if e := waiter.Deployments(namespace, "app.kubernetes.io/instance="+releaseName); e != nil {
+		return e
+	}
+	return waiter.Endpoints(namespace, releaseName+"-webhook")
+}
 
-	crt, e := os.ReadFile(path.Join(caRoot, "rootCA.pem"))
+func (m *certManager) applyCertSecret() error {
+	authority, e := ca.Default()
 	if e != nil {
-		return fmt.Errorf("unable to read the mkcert RootCA certificate: %s", e)
+		return fmt.Errorf("unable to load the RootCA: %s", e)
 	}
-	key, e := os.ReadFile(path.Join(caRoot, "rootCA-key.pem"))
+	crt := authority.CertificatePEM()
+	key, e := ca.EncodePrivateKeyPEM(authority.Key)
 	if e != nil {
-		return fmt.Errorf("unable to read the mkcert RootCA key: %s", e)
+		return e
 	}
 
 	clientSet, e := m.contextHandler.GetClientSet()
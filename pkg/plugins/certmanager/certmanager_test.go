@@ -1,9 +1,13 @@
 package certmanager
 
 import (
+	"context"
 	"errors"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/magiconair/properties/assert"
@@ -13,11 +17,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	k8sFake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	testing2 "k8s.io/client-go/testing"
 
+	"github.com/qaware/minikube-support/pkg/ca"
 	"github.com/qaware/minikube-support/pkg/github"
 	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
@@ -40,7 +46,7 @@ func TestNewCertManager(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := NewCertManager(tt.manager, tt.handler, github.NewClient())
+			got := NewCertManager(tt.manager, tt.handler, github.NewClient(), nil)
 			if _, ok := got.(*certManager); ok != tt.wantPlugin {
 				t.Errorf("NewCertManager() got %v, wantPlugin = %v", got, tt.wantPlugin)
 			}
@@ -83,17 +89,21 @@ func Test_certManager_Update(t *testing.T) {
 
 	hook := test.NewGlobal()
 	logrus.SetLevel(logrus.DebugLevel)
-	helmInstallWaitPeriod = 0
+	waitTimeout = 50 * time.Millisecond
 	tests := []struct {
 		name                   string
 		latestVersion          string
 		latestVersionError     error
 		kApplyStatus           int
 		repoUpdateError        error
+		installError           error
+		ready                  bool
 		expectedLogEntryPrefix string
 	}{
-		{"ok", "1.0", nil, 0, nil, "CertSecret 'ca-issuer' successfully added"},
-		{"failed update repos", "1.0", nil, 0, errors.New("no repo update"), "Unable to update helm repositories"},
+		{"ok", "1.0", nil, 0, nil, nil, true, "CertSecret 'ca-issuer' successfully added"},
+		{"failed update repos", "1.0", nil, 0, errors.New("no repo update"), nil, true, "Unable to update helm repositories"},
+		{"failed install", "1.0", nil, 0, nil, &helm.ReleaseError{Action: "install", Release: releaseName, Namespace: "mks", Err: errors.New("timed out")}, true, "Unable to install cert manager: can not install helm release mks/cert-manager: timed out"},
+		{"not ready", "1.0", nil, 0, nil, nil, false, "Cert manager is not ready: custom resource definitions not ready within 50ms"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -101,10 +111,11 @@ func Test_certManager_Update(t *testing.T) {
 			defer ctrl.Finish()
 
 			helmManager := helmFake.NewMockManager(ctrl)
-			handler := fake.NewContextHandler(k8sFake.NewSimpleClientset(), dynamicFake.NewSimpleDynamicClient(scheme.Scheme))
-			testutils.SetTestProcessResponse(testutils.TestProcessResponse{
-				Command: "mkcert", Args: []string{"-CAROOT"}, ResponseStatus: 0, Stdout: "fixtures/"},
-			)
+			handler := fake.NewContextHandler(k8sFake.NewSimpleClientset(), fake.NewDynamicClient(nil))
+			if tt.ready {
+				handler = readyHandler()
+			}
+			t.Setenv(ca.DirEnv, t.TempDir())
 
 			m := &certManager{
 				manager:        helmManager,
@@ -119,7 +130,8 @@ func Test_certManager_Update(t *testing.T) {
 				MinTimes(0).
 				MaxTimes(1)
 			helmManager.EXPECT().
-				Install("jetstack/cert-manager", releaseName, "mks", gomock.Any(), true).
+				Install("jetstack/cert-manager", "", releaseName, "mks", gomock.Any(), true).
+				Return(tt.installError).
 				MinTimes(0).
 				MaxTimes(1)
 			helmManager.EXPECT().
@@ -133,6 +145,17 @@ func Test_certManager_Update(t *testing.T) {
 	}
 }
 
+func readyHandler() *fake.ContextHandler {
+	labels := map[string]string{"app.kubernetes.io/instance": releaseName}
+	var objects []runtime.Object
+	for _, crd := range crds {
+		objects = append(objects, fake.EstablishedCRD(crd))
+	}
+	return fake.NewContextHandler(
+		k8sFake.NewClientset(fake.AvailableDeployment("mks", "cert-manager", labels), fake.ReadyEndpointSlice("mks", "cert-manager-webhook")),
+		fake.NewDynamicClient(nil, objects...))
+}
+
 func Test_certManager_Uninstall(t *testing.T) {
 	sh.ExecCommand = testutils.FakeExecCommand
 	defer func() { sh.ExecCommand = exec.Command }()
@@ -174,7 +197,7 @@ func Test_certManager_Uninstall(t *testing.T) {
 			defer ctrl.Finish()
 
 			manager := helmFake.NewMockManager(ctrl)
-			m := NewCertManager(manager, tt.handler, github.NewClient())
+			m := NewCertManager(manager, tt.handler, github.NewClient(), nil)
 			if tt.expectHelmUninstall {
 				manager.EXPECT().Uninstall(releaseName, "mks", true)
 			}
@@ -189,18 +212,16 @@ func Test_certManager_Uninstall(t *testing.T) {
 }
 
 func Test_certManager_applyCertSecret(t *testing.T) {
-	sh.ExecCommand = testutils.FakeExecCommand
-	defer func() { sh.ExecCommand = exec.Command }()
 	tests := []struct {
 		name           string
 		existingSecret *corev1.Secret
-		mkcertRoot     string
+		caFiles        map[string]string
 		wantAction     string
 		wantErr        bool
 	}{
 		{"ok, create",
 			nil,
-			"fixtures/",
+			nil,
 			"create",
 			false,
 		},
@@ -215,29 +236,29 @@ func Test_certManager_applyCertSecret(t *testing.T) {
 					Name:      issuerName,
 				},
 			},
-			"fixtures/",
+			nil,
 			"update",
 			false,
 		},
-		{"failed mkcert",
+		{"invalid CA certificate",
 			nil,
-			"",
+			map[string]string{ca.CertFile: "invalid", ca.KeyFile: "invalid"},
 			"",
 			true,
 		},
-		{"no cert",
+		{"no CA key",
 			nil,
-			"invailid-fixtures/",
+			map[string]string{ca.CertFile: "invalid"},
 			"",
 			true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.mkcertRoot != "" {
-				testutils.SetTestProcessResponse(testutils.TestProcessResponse{Command: "mkcert", Args: []string{"-CAROOT"}, ResponseStatus: 0, Stdout: tt.mkcertRoot})
-			} else {
-				testutils.SetTestProcessResponse(testutils.TestProcessResponse{Command: "mkcert", Args: []string{"-CAROOT"}, ResponseStatus: 1})
+			dir := t.TempDir()
+			t.Setenv(ca.DirEnv, dir)
+			for name, content := range tt.caFiles {
+				_ = os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
 			}
 
 			var fakeClientSet *k8sFake.Clientset
@@ -246,7 +267,7 @@ func Test_certManager_applyCertSecret(t *testing.T) {
 			} else {
 				fakeClientSet = k8sFake.NewSimpleClientset()
 			}
-			o := NewCertManager(nil, fake.NewContextHandler(fakeClientSet, nil), github.NewClient())
+			o := NewCertManager(nil, fake.NewContextHandler(fakeClientSet, nil), github.NewClient(), nil)
 			m := o.(*certManager)
 			if err := m.applyCertSecret(); (err != nil) != tt.wantErr {
 				t.Errorf("certManager.applyCertSecret() error = %v, wantErr %v", err, tt.wantErr)
@@ -255,6 +276,11 @@ func Test_certManager_applyCertSecret(t *testing.T) {
 			if len(actions) > 1 {
 				assert.Equal(t, tt.wantAction, actions[1].GetVerb())
 			}
+			if !tt.wantErr {
+				secret, _ := fakeClientSet.CoreV1().Secrets("mks").Get(context.Background(), issuerName, metav1.GetOptions{})
+				_, e := ca.Import(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+				assert2.NoError(t, e)
+			}
 		})
 	}
 }
@@ -275,7 +301,7 @@ func Test_certManager_applyClusterIssuer(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			handler := fake.NewContextHandler(k8sFake.NewSimpleClientset(), tt.dynamicClient)
-			o := NewCertManager(nil, handler, github.NewClient())
+			o := NewCertManager(nil, handler, github.NewClient(), nil)
 			m := o.(*certManager)
 
 			if err := m.applyClusterIssuer(); (err != nil) != tt.wantErr {
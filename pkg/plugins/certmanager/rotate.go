@@ -0,0 +1,134 @@
+package certmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+)
+
+// Rotator updates the cluster after the CA was rotated.
+type Rotator interface {
+	// ApplySecret replaces the CA of the ca-issuer with the current one.
+	ApplySecret() error
+	// Applied checks if the ca-issuer uses the given CA.
+	Applied(authority *ca.CA) (bool, error)
+	// Reissue deletes the secrets of all certificates issued by the ca-issuer, so that cert-manager issues
+	// them again. It returns the names of the certificates.
+	Reissue() ([]string, error)
+	// Pending returns the names of all certificates of the ca-issuer which are not yet signed by the given CA.
+	Pending(authority *ca.CA) ([]string, error)
+}
+
+// NewRotator creates a Rotator for the cluster of the given context handler.
+func NewRotator(handler kubernetes.ContextHandler) Rotator {
+	return &certManager{
+		contextHandler: handler,
+		namespace:      "mks",
+		ctx:            context.Background(),
+	}
+}
+
+func (m *certManager) ApplySecret() error {
+	return m.applyCertSecret()
+}
+
+func (m *certManager) Applied(authority *ca.CA) (bool, error) {
+	clientSet, e := m.contextHandler.GetClientSet()
+	if e != nil {
+		return false, fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	secret, e := clientSet.CoreV1().Secrets(m.namespace).Get(m.ctx, issuerName, metav1.GetOptions{})
+	if errors.IsNotFound(e) {
+		return false, nil
+	}
+	if e != nil {
+		return false, fmt.Errorf("can not get secret %s/%s: %s", m.namespace, issuerName, e)
+	}
+	return bytes.Equal(secret.Data[v1.TLSCertKey], authority.CertificatePEM()), nil
+}
+
+func (m *certManager) Reissue() ([]string, error) {
+	certificates, e := m.issuedCertificates()
+	if e != nil {
+		return nil, e
+	}
+	clientSet, e := m.contextHandler.GetClientSet()
+	if e != nil {
+		return nil, fmt.Errorf("unable to get k8s client: %s", e)
+	}
+
+	var names []string
+	for _, certificate := range certificates {
+		secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+		e := clientSet.CoreV1().Secrets(certificate.GetNamespace()).Delete(m.ctx, secretName, metav1.DeleteOptions{})
+		if e != nil && !errors.IsNotFound(e) {
+			return names, fmt.Errorf("can not delete secret %s/%s: %s", certificate.GetNamespace(), secretName, e)
+		}
+		names = append(names, certificate.GetNamespace()+"/"+certificate.GetName())
+	}
+	return names, nil
+}
+
+func (m *certManager) Pending(authority *ca.CA) ([]string, error) {
+	certificates, e := m.issuedCertificates()
+	if e != nil {
+		return nil, e
+	}
+	clientSet, e := m.contextHandler.GetClientSet()
+	if e != nil {
+		return nil, fmt.Errorf("unable to get k8s client: %s", e)
+	}
+
+	var pending []string
+	for _, certificate := range certificates {
+		secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+		secret, e := clientSet.CoreV1().Secrets(certificate.GetNamespace()).Get(m.ctx, secretName, metav1.GetOptions{})
+		if e != nil && !errors.IsNotFound(e) {
+			return nil, fmt.Errorf("can not get secret %s/%s: %s", certificate.GetNamespace(), secretName, e)
+		}
+		if e != nil || !signedBy(secret.Data[v1.TLSCertKey], authority) {
+			pending = append(pending, certificate.GetNamespace()+"/"+certificate.GetName())
+		}
+	}
+	return pending, nil
+}
+
+// issuedCertificates returns all certificates in all namespaces which are issued by the ca-issuer.
+func (m *certManager) issuedCertificates() ([]unstructured.Unstructured, error) {
+	client, e := m.contextHandler.GetDynamicClient()
+	if e != nil {
+		return nil, e
+	}
+	list, e := client.Resource(groupVersion.WithResource("certificates")).List(m.ctx, metav1.ListOptions{})
+	if e != nil {
+		return nil, fmt.Errorf("can not list certificates: %s", e)
+	}
+
+	var certificates []unstructured.Unstructured
+	for _, certificate := range list.Items {
+		name, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+		kind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+		if name == issuerName && kind == "ClusterIssuer" {
+			certificates = append(certificates, certificate)
+		}
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].GetNamespace()+"/"+certificates[i].GetName() < certificates[j].GetNamespace()+"/"+certificates[j].GetName()
+	})
+	return certificates, nil
+}
+
+// signedBy checks if the first certificate of the given PEM data is signed by the given CA.
+func signedBy(data []byte, authority *ca.CA) bool {
+	certificates, e := ca.ParseCertificates(data)
+	return e == nil && certificates[0].CheckSignatureFrom(authority.Certificate) == nil
+}
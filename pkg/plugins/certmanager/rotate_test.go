@@ -0,0 +1,113 @@
+package certmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+)
+
+func Test_certManager_Reissue(t *testing.T) {
+	handler := fake.NewContextHandler(
+		k8sFake.NewClientset(tlsSecret("default", "app-tls", nil), tlsSecret("default", "other-tls", nil)),
+		newDynamicClient(
+			certificate("default", "app", "app-tls", issuerName, "ClusterIssuer"),
+			certificate("default", "other", "other-tls", "other-issuer", "ClusterIssuer"),
+			certificate("test", "missing", "missing-tls", issuerName, "ClusterIssuer"),
+		))
+
+	names, e := NewRotator(handler).Reissue()
+
+	assert.NoError(t, e)
+	assert.Equal(t, []string{"default/app", "test/missing"}, names)
+	secrets, _ := handler.ClientSet.CoreV1().Secrets("default").List(t.Context(), metav1.ListOptions{})
+	assert.Len(t, secrets.Items, 1)
+	assert.Equal(t, "other-tls", secrets.Items[0].Name)
+}
+
+func Test_certManager_Applied(t *testing.T) {
+	authority, _ := ca.Generate()
+	previous, _ := ca.Generate()
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    bool
+	}{
+		{"current", []runtime.Object{tlsSecret("mks", issuerName, authority.CertificatePEM())}, true},
+		{"previous", []runtime.Object{tlsSecret("mks", issuerName, previous.CertificatePEM())}, false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := fake.NewContextHandler(k8sFake.NewClientset(tt.objects...), nil)
+
+			applied, e := NewRotator(handler).Applied(authority)
+
+			assert.NoError(t, e)
+			assert.Equal(t, tt.want, applied)
+		})
+	}
+}
+
+func Test_certManager_Pending(t *testing.T) {
+	authority, _ := ca.Generate()
+	previous, _ := ca.Generate()
+	current, _ := authority.Issue([]string{"app.minikube"}, ca.DefaultValidity)
+	old, _ := previous.Issue([]string{"old.minikube"}, ca.DefaultValidity)
+
+	handler := fake.NewContextHandler(
+		k8sFake.NewClientset(tlsSecret("default", "app-tls", current.CertificatePEM()), tlsSecret("default", "old-tls", old.CertificatePEM())),
+		newDynamicClient(
+			certificate("default", "app", "app-tls", issuerName, "ClusterIssuer"),
+			certificate("default", "old", "old-tls", issuerName, "ClusterIssuer"),
+			certificate("default", "namespaced", "old-tls", issuerName, "Issuer"),
+			certificate("test", "missing", "missing-tls", issuerName, "ClusterIssuer"),
+		))
+
+	pending, e := NewRotator(handler).Pending(authority)
+
+	assert.NoError(t, e)
+	assert.Equal(t, []string{"default/old", "test/missing"}, pending)
+}
+
+func Test_certManager_Pending_noClient(t *testing.T) {
+	authority, _ := ca.Generate()
+	_, e := NewRotator(fake.NewContextHandler(nil, nil)).Pending(authority)
+	assert.EqualError(t, e, "no dynamic client")
+}
+
+func newDynamicClient(objects ...runtime.Object) *dynamicFake.FakeDynamicClient {
+	return dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{groupVersion.WithResource("certificates"): "CertificateList"},
+		objects...)
+}
+
+func certificate(namespace string, name string, secretName string, issuer string, kind string) *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretName": secretName,
+			"issuerRef":  map[string]interface{}{"name": issuer, "kind": kind},
+		},
+	}}
+	certificate.SetGroupVersionKind(groupVersion.WithKind("Certificate"))
+	certificate.SetNamespace(namespace)
+	certificate.SetName(name)
+	return certificate
+}
+
+func tlsSecret(namespace string, name string, crt []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: crt},
+	}
+}
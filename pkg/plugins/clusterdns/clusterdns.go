@@ -0,0 +1,151 @@
+package clusterdns
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/plugins/ingress"
+)
+
+const (
+	PluginName         = "cluster-dns"
+	configMapNamespace = "kube-system"
+	configMapName      = "coredns"
+	corefileKey        = "Corefile"
+	beginMarker        = "# BEGIN minikube-support"
+	endMarker          = "# END minikube-support"
+	defaultDomain      = "cluster.local"
+)
+
+// serverBlock is the server block added to the Corefile of the cluster. It rewrites all *.minikube names to the
+// service of the ingress controller so that pods can reach ingresses by their public host names.
+const serverBlock = beginMarker + `
+minikube:53 {
+    errors
+    rewrite name regex (.+)\.minikube\.? %s.%s.svc.%s answer auto
+    kubernetes %s in-addr.arpa ip6.arpa {
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    cache 30
+}
+` + endMarker + "\n"
+
+var kubernetesPluginPattern = regexp.MustCompile(`(?m)^\s*kubernetes\s+(\S+)`)
+
+type clusterDns struct {
+	contextHandler kubernetes.ContextHandler
+	ingress        *ingress.Selector
+	ctx            context.Context
+}
+
+// NewClusterDns creates the plugin which configures the CoreDNS of the cluster to resolve *.minikube names to
+// the service of the selected ingress controller.
+func NewClusterDns(handler kubernetes.ContextHandler, selector *ingress.Selector) apis.InstallablePlugin {
+	return &clusterDns{contextHandler: handler, ingress: selector, ctx: context.Background()}
+}
+
+func (c *clusterDns) String() string {
+	return PluginName
+}
+
+func (c *clusterDns) Install() {
+	c.Update()
+}
+
+func (c *clusterDns) Update() {
+	controller, e := c.ingress.Controller()
+	if e != nil {
+		logrus.Errorf("Unable to select the ingress controller: %s", e)
+		return
+	}
+	changed, e := c.modifyCorefile(func(corefile string) string {
+		return addServerBlock(corefile, controller.ServiceName())
+	})
+	if e != nil {
+		logrus.Errorf("Unable to configure the cluster dns for *.minikube: %s", e)
+		return
+	}
+	if changed {
+		logrus.Info("Cluster dns is configured to resolve *.minikube to the ingress controller.")
+	} else {
+		logrus.Debug("Cluster dns is already configured to resolve *.minikube.")
+	}
+}
+
+func (c *clusterDns) Uninstall(_ bool) {
+	if _, e := c.modifyCorefile(removeServerBlock); e != nil {
+		logrus.Errorf("Unable to remove the *.minikube configuration from the cluster dns: %s", e)
+		return
+	}
+	logrus.Info("Cluster dns plugin successfully uninstalled.")
+}
+
+func (c *clusterDns) Phase() apis.Phase {
+	return apis.CLUSTER_CONFIG
+}
+
+// modifyCorefile applies the given modification to the Corefile of the cluster. The config map is only
+// updated if the Corefile has changed.
+func (c *clusterDns) modifyCorefile(modify func(corefile string) string) (bool, error) {
+	clientSet, e := c.contextHandler.GetClientSet()
+	if e != nil {
+		return false, fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	configMaps := clientSet.CoreV1().ConfigMaps(configMapNamespace)
+	configMap, e := configMaps.Get(c.ctx, configMapName, metav1.GetOptions{})
+	if e != nil {
+		return false, fmt.Errorf("can not get config map %s/%s: %s", configMapNamespace, configMapName, e)
+	}
+
+	corefile, ok := configMap.Data[corefileKey]
+	if !ok {
+		return false, fmt.Errorf("config map %s/%s contains no %s", configMapNamespace, configMapName, corefileKey)
+	}
+	modified := modify(corefile)
+	if modified == corefile {
+		return false, nil
+	}
+
+	configMap.Data[corefileKey] = modified
+	if _, e := configMaps.Update(c.ctx, configMap, metav1.UpdateOptions{}); e != nil {
+		return false, fmt.Errorf("can not update config map %s/%s: %s", configMapNamespace, configMapName, e)
+	}
+	return true, nil
+}
+
+// addServerBlock replaces an existing minikube server block in the given Corefile with one that rewrites the
+// names to the given service of the ingress controller.
+func addServerBlock(corefile string, service string) string {
+	corefile = removeServerBlock(corefile)
+	domain := defaultDomain
+	if match := kubernetesPluginPattern.FindStringSubmatch(corefile); match != nil {
+		domain = match[1]
+	}
+	block := fmt.Sprintf(serverBlock, service, ingress.Namespace, domain, domain)
+	return strings.TrimRight(corefile, "\n") + "\n" + block
+}
+
+// removeServerBlock removes the minikube server block from the given Corefile.
+func removeServerBlock(corefile string) string {
+	begin := strings.Index(corefile, beginMarker)
+	if begin < 0 {
+		return corefile
+	}
+	end := strings.Index(corefile[begin:], endMarker)
+	if end < 0 {
+		return corefile
+	}
+	end += begin + len(endMarker)
+	if end < len(corefile) && corefile[end] == '\n' {
+		end++
+	}
+	return corefile[:begin] + corefile[end:]
+}
@@ -0,0 +1,119 @@
+package clusterdns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/plugins/ingress"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+const corefile = `.:53 {
+    errors
+    health
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+    }
+    forward . /etc/resolv.conf
+    cache 30
+    reload
+}
+`
+
+const patchedCorefile = corefile + `# BEGIN minikube-support
+minikube:53 {
+    errors
+    rewrite name regex (.+)\.minikube\.? nginx-ingress-ingress-nginx-controller.mks.svc.cluster.local answer auto
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    cache 30
+}
+# END minikube-support
+`
+
+func Test_addServerBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		corefile string
+		want     string
+	}{
+		{"add", corefile, patchedCorefile},
+		{"idempotent", patchedCorefile, patchedCorefile},
+		{"replace outdated block", corefile + "# BEGIN minikube-support\nminikube:53 {\n}\n# END minikube-support\n", patchedCorefile},
+		{"custom cluster domain", "kubernetes k8s.example in-addr.arpa\n", "kubernetes k8s.example in-addr.arpa\n" + `# BEGIN minikube-support
+minikube:53 {
+    errors
+    rewrite name regex (.+)\.minikube\.? nginx-ingress-ingress-nginx-controller.mks.svc.k8s.example answer auto
+    kubernetes k8s.example in-addr.arpa ip6.arpa {
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    cache 30
+}
+# END minikube-support
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, addServerBlock(tt.corefile, "nginx-ingress-ingress-nginx-controller"))
+		})
+	}
+}
+
+func Test_removeServerBlock(t *testing.T) {
+	assert.Equal(t, corefile, removeServerBlock(patchedCorefile))
+	assert.Equal(t, corefile, removeServerBlock(corefile))
+	assert.Equal(t, "# BEGIN minikube-support\nno end", removeServerBlock("# BEGIN minikube-support\nno end"))
+}
+
+func Test_clusterDns_InstallUninstall(t *testing.T) {
+	hook := test.NewGlobal()
+	logrus.SetLevel(logrus.DebugLevel)
+	clientSet := k8sFake.NewClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns"},
+		Data:       map[string]string{"Corefile": corefile},
+	})
+	handler := fake.NewContextHandler(clientSet, nil)
+	kind := ingress.KindNginx
+	plugin := NewClusterDns(handler, ingress.NewSelector(&kind, handler))
+
+	plugin.Install()
+	testutils.CheckLogEntry(t, hook, "Cluster dns is configured to resolve *.minikube")
+	assert.Equal(t, patchedCorefile, currentCorefile(t, clientSet))
+
+	clientSet.ClearActions()
+	plugin.Update()
+	testutils.CheckLogEntry(t, hook, "Cluster dns is already configured")
+	assert.Len(t, clientSet.Actions(), 1, "config map must not be updated again")
+
+	plugin.Uninstall(false)
+	testutils.CheckLogEntry(t, hook, "Cluster dns plugin successfully uninstalled.")
+	assert.Equal(t, corefile, currentCorefile(t, clientSet))
+}
+
+func Test_clusterDns_Update_noConfigMap(t *testing.T) {
+	hook := test.NewGlobal()
+	handler := fake.NewContextHandler(k8sFake.NewClientset(), nil)
+	plugin := NewClusterDns(handler, ingress.NewSelector(nil, handler))
+
+	plugin.Update()
+
+	testutils.CheckLogEntry(t, hook, "Unable to configure the cluster dns for *.minikube: can not get config map kube-system/coredns")
+}
+
+func currentCorefile(t *testing.T, clientSet *k8sFake.Clientset) string {
+	configMap, e := clientSet.CoreV1().ConfigMaps("kube-system").Get(context.Background(), "coredns", metav1.GetOptions{})
+	assert.NoError(t, e)
+	return configMap.Data["Corefile"]
+}
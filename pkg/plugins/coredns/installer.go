@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"runtime"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
@@ -14,20 +13,27 @@ import (
 
 	"github.com/qaware/minikube-support/pkg/apis"
 	"github.com/qaware/minikube-support/pkg/github"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/utils/sudos"
 )
 
 type installer struct {
-	ghClient github.Client
-	prefix   prefix
+	ghClient       github.Client
+	prefix         prefix
+	contextHandler kubernetes.ContextHandler
 }
 
 const PluginName = "coredns"
 
-func NewInstaller(prefix string, ghClient github.Client) apis.InstallablePlugin {
+// defaultHostGateway is the address of the host in the network of minikube using the hyperkit driver. It is used
+// if the address of the host can not be determined from the local cluster.
+const defaultHostGateway = "192.168.64.1"
+
+func NewInstaller(prefix string, ghClient github.Client, handler kubernetes.ContextHandler) apis.InstallablePlugin {
 	return &installer{
-		ghClient: ghClient,
-		prefix:   newCoreDnsPaths(prefix),
+		ghClient:       ghClient,
+		prefix:         newCoreDnsPaths(prefix),
+		contextHandler: handler,
 	}
 }
 
@@ -73,15 +79,63 @@ func (i *installer) Phase() apis.Phase {
 	return apis.LOCAL_TOOLS_CONFIG
 }
 
+// Artifacts returns the coredns release.
+func (i *installer) Artifacts() apis.Artifacts {
+	return apis.Artifacts{Releases: []apis.ReleaseArtifact{{Org: "coredns", Repository: "coredns", Asset: assetName}}}
+}
+
+// coreFileContent creates the coredns configuration. Coredns forwards all dns requests from the cluster
+// nodes, sent to the address of the host within the network of the local cluster, to the dns servers of the host.
+func (i *installer) coreFileContent() string {
+	config := `
+. {
+    reload
+    health :8054
+    bind 127.0.0.1
+    bind ::1
+    log
+
+    grpc minikube 127.0.0.1:8053
+}
+`
+	return config + i.hostGateway() + `:53  {
+    forward . /etc/resolv.conf
+}
+`
+}
+
+// hostGateway returns the address of the host as seen from the local cluster. If it is unknown,
+// defaultHostGateway is returned.
+func (i *installer) hostGateway() string {
+	gateway, e := i.clusterHostGateway()
+	if e != nil {
+		logrus.Warnf("Can not determine the address of the host in the cluster network. Forwarding the dns requests sent to %s instead: %s", defaultHostGateway, e)
+		return defaultHostGateway
+	}
+	return gateway
+}
+
+// clusterHostGateway asks the provider of the local cluster for the address of the host.
+func (i *installer) clusterHostGateway() (string, error) {
+	if i.contextHandler == nil {
+		return "", fmt.Errorf("no context handler")
+	}
+	provider, e := i.contextHandler.GetClusterProvider()
+	if e != nil {
+		return "", fmt.Errorf("can not determine the local cluster: %s", e)
+	}
+	if provider == nil {
+		return "", fmt.Errorf("context is not set to a local cluster")
+	}
+	return provider.HostGateway()
+}
+
 func (i *installer) downloadCoreDns() error {
 	tagName, e := i.ghClient.GetLatestReleaseTag("coredns", "coredns")
 	if e != nil {
 		return fmt.Errorf("can not get latest coredns version: %s", e)
 	}
-	version := strings.TrimPrefix(tagName, "v")
-
-	assetName := fmt.Sprintf("coredns_%s_%s_%s.tgz", version, runtime.GOOS, runtime.GOARCH)
-	bytes, e := i.ghClient.DownloadReleaseAsset("coredns", "coredns", tagName, assetName)
+	bytes, e := i.ghClient.DownloadReleaseAsset("coredns", "coredns", tagName, assetName(tagName, apis.LocalPlatform()))
 	if e != nil {
 		return fmt.Errorf("can not download coredns binary: %s", e)
 	}
@@ -118,3 +172,8 @@ func (i *installer) downloadCoreDns() error {
 	}
 	return nil
 }
+
+// assetName returns the name of the release asset of coredns for the platform.
+func assetName(tag string, platform apis.Platform) string {
+	return fmt.Sprintf("coredns_%s_%s_%s.tgz", strings.TrimPrefix(tag, "v"), platform.OS, platform.Arch)
+}
@@ -64,21 +64,7 @@ func (i *installer) uninstallSpecific() error {
 }
 
 func (i *installer) writeConfig() error {
-	config := `
-. {
-    reload
-    health :8054
-    bind 127.0.0.1
-    bind ::1
-    log
-
-    grpc minikube 127.0.0.1:8053
-}
-192.168.64.1:53  {
-    forward . /etc/resolv.conf
-}
-`
-	return os.WriteFile(i.prefix.coreFile(), []byte(config), 0644)
+	return os.WriteFile(i.prefix.coreFile(), []byte(i.coreFileContent()), 0644)
 }
 
 func (i *installer) writeLaunchCtlConfig() error {
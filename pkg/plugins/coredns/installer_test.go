@@ -5,10 +5,15 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/qaware/minikube-support/pkg/cluster"
 	"github.com/qaware/minikube-support/pkg/github/fake"
+	k8sfake "github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/testutils"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -67,3 +72,51 @@ func Test_installer_downloadCoreDns(t *testing.T) {
 		})
 	}
 }
+
+func Test_installer_coreFileContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    cluster.Provider
+		wantForward string
+	}{
+		{"no cluster", nil, "192.168.64.1:53  {"},
+		{"kind", &testProvider{gateway: "172.18.0.1"}, "172.18.0.1:53  {"},
+		{"unknown gateway", &testProvider{}, "192.168.64.1:53  {"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := test.NewGlobal()
+			handler := k8sfake.NewContextHandler(nil, nil)
+			handler.Provider = tt.provider
+			i := &installer{contextHandler: handler}
+
+			got := i.coreFileContent()
+
+			assert.Contains(t, got, "grpc minikube 127.0.0.1:8053")
+			assert.Contains(t, got, tt.wantForward)
+			if strings.HasPrefix(tt.wantForward, defaultHostGateway) {
+				testutils.CheckLogEntry(t, hook, "Can not determine the address of the host in the cluster network.")
+			} else {
+				assert.Nil(t, hook.LastEntry())
+			}
+		})
+	}
+}
+
+type testProvider struct {
+	gateway string
+}
+
+func (p *testProvider) String() string                             { return "test" }
+func (p *testProvider) ClusterName() string                        { return "test" }
+func (p *testProvider) NodeIPs() ([]string, error)                 { return []string{"172.18.0.2"}, nil }
+func (p *testProvider) LoadBalancer() cluster.LoadBalancerStrategy { return cluster.LoadBalancerNone }
+func (p *testProvider) TunnelCommand() []string                    { return nil }
+func (p *testProvider) LoadImage(string) error                     { return nil }
+
+func (p *testProvider) HostGateway() (string, error) {
+	if p.gateway == "" {
+		return "", fmt.Errorf("no gateway")
+	}
+	return p.gateway, nil
+}
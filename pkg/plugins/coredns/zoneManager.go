@@ -0,0 +1,50 @@
+package coredns
+
+import "strings"
+
+// topLevelDomain is the top level domain of all records served by the backend.
+const topLevelDomain = "minikube"
+
+// zoneManager is a Manager that moves all host names of the minikube top level
+// domain into a sub zone before passing them to the wrapped manager.
+type zoneManager struct {
+	manager Manager
+	zone    string
+}
+
+// NewZoneManager initializes a Manager that moves all host names of the minikube top level domain into the
+// given zone. For example "keycloak.minikube" becomes "keycloak.app.minikube" for the zone "app.minikube".
+// Other host names are passed unchanged.
+func NewZoneManager(manager Manager, zone string) Manager {
+	return &zoneManager{manager: manager, zone: strings.Trim(zone, ".")}
+}
+
+func (m *zoneManager) AddHost(hostName string, ip string) error {
+	return m.manager.AddHost(m.toZone(hostName), ip)
+}
+
+func (m *zoneManager) AddAlias(hostName string, target string) error {
+	return m.manager.AddAlias(m.toZone(hostName), target)
+}
+
+func (m *zoneManager) RemoveHost(hostName string) {
+	m.manager.RemoveHost(m.toZone(hostName))
+}
+
+// toZone rewrites the given host name into the zone of this manager.
+func (m *zoneManager) toZone(hostName string) string {
+	suffix := ""
+	name := hostName
+	if strings.HasSuffix(name, ".") {
+		suffix = "."
+		name = strings.TrimSuffix(name, ".")
+	}
+
+	if name == topLevelDomain {
+		return m.zone + suffix
+	}
+	if strings.HasSuffix(name, "."+topLevelDomain) && !strings.HasSuffix(name, "."+m.zone) {
+		return strings.TrimSuffix(name, topLevelDomain) + m.zone + suffix
+	}
+	return hostName
+}
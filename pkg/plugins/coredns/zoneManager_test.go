@@ -0,0 +1,28 @@
+package coredns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_zoneManager_toZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostName string
+		want     string
+	}{
+		{"ingress", "keycloak.minikube", "keycloak.app.minikube"},
+		{"fqdn", "svc.ns.svc.minikube.", "svc.ns.svc.app.minikube."},
+		{"tld", "minikube", "app.minikube"},
+		{"already in zone", "keycloak.app.minikube", "keycloak.app.minikube"},
+		{"other domain", "keycloak.mk.local", "keycloak.mk.local"},
+		{"suffix only", "myminikube", "myminikube"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewZoneManager(NewNoOpManager(), "app.minikube.").(*zoneManager)
+			assert.Equal(t, tt.want, m.toZone(tt.hostName))
+		})
+	}
+}
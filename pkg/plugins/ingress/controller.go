@@ -0,0 +1,215 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+)
+
+// The kinds of ingress controllers which can be selected.
+const (
+	// KindNginx selects the ingress-nginx controller.
+	KindNginx = "nginx"
+	// KindTraefik selects the Traefik proxy.
+	KindTraefik = "traefik"
+	// KindContour selects Contour using Envoy as proxy.
+	KindContour = "contour"
+	// DefaultKind is used if no kind is selected and no known controller is installed.
+	DefaultKind = KindNginx
+)
+
+// DefaultClassAnnotation marks the ingress class which is used for ingresses without ingressClassName.
+const DefaultClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+
+// Controller is an implementation of an ingress controller which can be installed by the ingress plugin.
+type Controller interface {
+	// String returns the kind of the controller.
+	String() string
+	// Chart returns the chart which installs the controller.
+	Chart() apis.ChartArtifact
+	// ReleaseName returns the name of the helm release in the namespace of the plugin.
+	ReleaseName() string
+	// ServiceName returns the name of the service which receives the traffic of the ingresses.
+	ServiceName() string
+	// ClassName returns the name of the ingress class handled by the controller.
+	ClassName() string
+	// Values returns the default values to install the chart.
+	Values() map[string]interface{}
+	// Ready waits until the controller is available and accepts ingresses.
+	Ready(handler kubernetes.ContextHandler, timeout time.Duration) error
+}
+
+type controller struct {
+	kind        string
+	chart       apis.ChartArtifact
+	releaseName string
+	serviceName string
+	className   string
+	// selector selects the deployments of the controller.
+	selector string
+	// admissionService is the service of the validating webhook. It is empty if the controller has none.
+	admissionService string
+	values           map[string]interface{}
+}
+
+var controllers = map[string]*controller{
+	KindNginx: {
+		kind:             KindNginx,
+		chart:            apis.ChartArtifact{RepositoryName: "ingress-nginx", RepositoryURL: "https://kubernetes.github.io/ingress-nginx", Chart: "ingress-nginx/ingress-nginx"},
+		releaseName:      "nginx-ingress",
+		serviceName:      "nginx-ingress-ingress-nginx-controller",
+		className:        "nginx",
+		selector:         "app.kubernetes.io/instance=nginx-ingress",
+		admissionService: "nginx-ingress-ingress-nginx-controller-admission",
+		values: map[string]interface{}{
+			"controller.publishService.enabled": "true",
+			// reference the images only by tag, so that images loaded from a bundle are used
+			"controller.image.digest":                         "",
+			"controller.image.digestChroot":                   "",
+			"controller.admissionWebhooks.patch.image.digest": "",
+		},
+	},
+	KindTraefik: {
+		kind:        KindTraefik,
+		chart:       apis.ChartArtifact{RepositoryName: "traefik", RepositoryURL: "https://traefik.github.io/charts", Chart: "traefik/traefik"},
+		releaseName: "traefik",
+		serviceName: "traefik",
+		className:   "traefik",
+		selector:    "app.kubernetes.io/name=traefik",
+		values: map[string]interface{}{
+			"ingressClass.enabled":                                 "true",
+			"providers.kubernetesIngress.publishedService.enabled": "true",
+		},
+	},
+	KindContour: {
+		kind:        KindContour,
+		chart:       apis.ChartArtifact{RepositoryName: "contour", RepositoryURL: "https://projectcontour.github.io/helm-charts", Chart: "contour/contour"},
+		releaseName: "contour",
+		serviceName: "contour-envoy",
+		className:   "contour",
+		selector:    "app.kubernetes.io/instance=contour",
+		values: map[string]interface{}{
+			"contour.ingressClass.create": "true",
+			"contour.ingressClass.name":   "contour",
+			// use the images published by the projects
+			"contour.image.registry":   "ghcr.io",
+			"contour.image.repository": "projectcontour/contour",
+			"envoy.image.registry":     "docker.io",
+			"envoy.image.repository":   "envoyproxy/envoy",
+		},
+	},
+}
+
+// GetController returns the controller of the given kind.
+func GetController(kind string) (Controller, error) {
+	c, ok := controllers[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown ingress controller %s, use one of %s", kind, strings.Join(Kinds(), ", "))
+	}
+	return c, nil
+}
+
+// Kinds returns the sorted kinds of all known controllers.
+func Kinds() []string {
+	kinds := make([]string, 0, len(controllers))
+	for kind := range controllers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func (c *controller) String() string {
+	return c.kind
+}
+
+func (c *controller) Chart() apis.ChartArtifact {
+	return c.chart
+}
+
+func (c *controller) ReleaseName() string {
+	return c.releaseName
+}
+
+func (c *controller) ServiceName() string {
+	return c.serviceName
+}
+
+func (c *controller) ClassName() string {
+	return c.className
+}
+
+func (c *controller) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(c.values))
+	for k, v := range c.values {
+		values[k] = v
+	}
+	return values
+}
+
+func (c *controller) Ready(handler kubernetes.ContextHandler, timeout time.Duration) error {
+	waiter := kubernetes.NewWaiter(handler, timeout)
+	if e := waiter.Deployments(Namespace, c.selector); e != nil {
+		return e
+	}
+	if c.admissionService == "" {
+		return nil
+	}
+	return waiter.Endpoints(Namespace, c.admissionService)
+}
+
+// Selector selects the ingress controller used by the plugins.
+type Selector struct {
+	kind    *string
+	handler kubernetes.ContextHandler
+}
+
+// NewSelector creates a selector for the controller of the given kind. The kind is read when the controller is
+// selected.
+func NewSelector(kind *string, handler kubernetes.ContextHandler) *Selector {
+	return &Selector{kind: kind, handler: handler}
+}
+
+// Controller returns the selected controller. If no kind is selected, the controller whose ingress class is the
+// default class of the cluster is returned. If there is none, the controller of DefaultKind is used.
+func (s *Selector) Controller() (Controller, error) {
+	if s.kind != nil && *s.kind != "" {
+		return GetController(*s.kind)
+	}
+	kind, e := s.installedKind()
+	if e != nil {
+		logrus.Debugf("Unable to determine the installed ingress controller, using %s: %s", DefaultKind, e)
+	}
+	return GetController(kind)
+}
+
+// installedKind returns the kind of the controller whose ingress class is the default class of the cluster.
+func (s *Selector) installedKind() (string, error) {
+	clientSet, e := s.handler.GetClientSet()
+	if e != nil {
+		return DefaultKind, e
+	}
+	classes, e := clientSet.NetworkingV1().IngressClasses().List(context.Background(), metav1.ListOptions{})
+	if e != nil {
+		return DefaultKind, fmt.Errorf("can not list ingress classes: %s", e)
+	}
+	for _, class := range classes.Items {
+		if class.Annotations[DefaultClassAnnotation] != "true" {
+			continue
+		}
+		for kind, c := range controllers {
+			if c.className == class.Name {
+				return kind, nil
+			}
+		}
+	}
+	return DefaultKind, nil
+}
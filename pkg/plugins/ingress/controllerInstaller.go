@@ -1,25 +1,44 @@
 package ingress
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
 )
 
+const (
+	// Namespace is the namespace of the ingress controller.
+	Namespace = "mks"
+)
+
+var waitTimeout = kubernetes.DefaultWaitTimeout
+
 type controllerInstaller struct {
-	manager     helm.Manager
-	releaseName string
-	namespace   string
-	values      map[string]interface{}
+	manager   helm.Manager
+	handler   kubernetes.ContextHandler
+	selector  *Selector
+	namespace string
+	overrides *helm.Overrides
+	ctx       context.Context
 }
 
-func NewControllerInstaller(manager helm.Manager) apis.InstallablePlugin {
+// NewControllerInstaller creates the plugin which installs the ingress controller chosen by the selector and
+// makes its ingress class the default class of the cluster.
+func NewControllerInstaller(manager helm.Manager, handler kubernetes.ContextHandler, selector *Selector, overrides *helm.Overrides) apis.InstallablePlugin {
 	return &controllerInstaller{
-		manager:     manager,
-		releaseName: "nginx-ingress",
-		values:      map[string]interface{}{},
-		namespace:   "mks",
+		manager:   manager,
+		handler:   handler,
+		selector:  selector,
+		overrides: overrides,
+		namespace: Namespace,
+		ctx:       context.Background(),
 	}
 }
 
@@ -28,8 +47,14 @@ func (*controllerInstaller) String() string {
 }
 
 func (i *controllerInstaller) Install() {
-	if e := i.manager.AddRepository("ingress-nginx", "https://kubernetes.github.io/ingress-nginx"); e != nil {
-		logrus.Errorf("Unable to add nginx-ingress repository: %s", e)
+	controller, e := i.selector.Controller()
+	if e != nil {
+		logrus.Errorf("Unable to select the ingress controller: %s", e)
+		return
+	}
+	chart := controller.Chart()
+	if e := i.manager.AddRepository(chart.RepositoryName, chart.RepositoryURL); e != nil {
+		logrus.Errorf("Unable to add %s repository: %s", chart.RepositoryName, e)
 		return
 	}
 	i.Update()
@@ -40,15 +65,132 @@ func (i *controllerInstaller) Update() {
 		logrus.Errorf("Unable to update helm repositories %s", e)
 		return
 	}
-	i.values["controller.publishService.enabled"] = "true"
+	controller, e := i.selector.Controller()
+	if e != nil {
+		logrus.Errorf("Unable to select the ingress controller: %s", e)
+		return
+	}
+	values, e := i.Values()
+	if e != nil {
+		logrus.Errorf("Unable to determine the values of the ingress controller: %s", e)
+		return
+	}
+	if e := i.manager.Install(controller.Chart().Chart, "", controller.ReleaseName(), i.namespace, values, false); e != nil {
+		logrus.Errorf("Unable to install the %s ingress controller: %s", controller, e)
+		return
+	}
+	for _, release := range i.otherReleases(controller.ReleaseName()) {
+		logrus.Infof("Removing the release %s of the replaced ingress controller.", release)
+		i.manager.Uninstall(release, i.namespace, true)
+	}
 
-	i.manager.Install("ingress-nginx/ingress-nginx", i.releaseName, i.namespace, i.values, false)
+	if e := i.setDefaultClass(controller.ClassName()); e != nil {
+		logrus.Errorf("Unable to make %s the default ingress class: %s", controller.ClassName(), e)
+	}
 }
 
+// Uninstall removes the release of the selected controller and the ones of all other controllers which are
+// installed.
 func (i *controllerInstaller) Uninstall(_ bool) {
-	i.manager.Uninstall(i.releaseName, i.namespace, true)
+	release, namespace := i.Release()
+	i.manager.Uninstall(release, namespace, true)
+	for _, other := range i.otherReleases(release) {
+		i.manager.Uninstall(other, namespace, true)
+	}
+}
+
+// otherReleases returns the installed releases of all known controllers except the given one.
+func (i *controllerInstaller) otherReleases(release string) []string {
+	var releases []string
+	for _, kind := range Kinds() {
+		other := controllers[kind].ReleaseName()
+		if other == release {
+			continue
+		}
+		if _, e := i.manager.Status(other, i.namespace); e == nil {
+			releases = append(releases, other)
+		}
+	}
+	return releases
 }
 
 func (*controllerInstaller) Phase() apis.Phase {
 	return apis.CLUSTER_TOOLS_INSTALL
 }
+
+func (i *controllerInstaller) Release() (string, string) {
+	controller, e := i.selector.Controller()
+	if e != nil {
+		logrus.Warnf("Unable to select the ingress controller, using %s: %s", DefaultKind, e)
+		controller = controllers[DefaultKind]
+	}
+	return controller.ReleaseName(), i.namespace
+}
+
+// Ready checks if the ingress controller and its admission webhook are available.
+func (i *controllerInstaller) Ready() error {
+	controller, e := i.selector.Controller()
+	if e != nil {
+		return e
+	}
+	return controller.Ready(i.handler, waitTimeout)
+}
+
+// Artifacts returns the chart of the selected ingress controller.
+func (i *controllerInstaller) Artifacts() apis.Artifacts {
+	controller, e := i.selector.Controller()
+	if e != nil {
+		logrus.Warnf("Unable to select the ingress controller: %s", e)
+		return apis.Artifacts{}
+	}
+	values, e := i.Values()
+	if e != nil {
+		logrus.Warnf("Unable to determine the values of the ingress controller: %s", e)
+	}
+	chart := controller.Chart()
+	chart.Values = values
+	return apis.Artifacts{Charts: []apis.ChartArtifact{chart}}
+}
+
+// Values returns the default values of the selected controller merged with the values given by the user.
+func (i *controllerInstaller) Values() (map[string]interface{}, error) {
+	controller, e := i.selector.Controller()
+	if e != nil {
+		return nil, e
+	}
+	return i.overrides.Values(i.String(), controller.Values())
+}
+
+// setDefaultClass marks the ingress class as default class of the cluster and removes the mark from all other
+// classes, so that ingresses without ingressClassName are served by the installed controller.
+func (i *controllerInstaller) setDefaultClass(className string) error {
+	clientSet, e := i.handler.GetClientSet()
+	if e != nil {
+		return fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	classes := clientSet.NetworkingV1().IngressClasses()
+	list, e := classes.List(i.ctx, metav1.ListOptions{})
+	if e != nil {
+		return fmt.Errorf("can not list ingress classes: %s", e)
+	}
+
+	found := false
+	for _, class := range list.Items {
+		isDefault := class.Name == className
+		found = found || isDefault
+		if (class.Annotations[DefaultClassAnnotation] == "true") == isDefault {
+			continue
+		}
+		if class.Annotations == nil {
+			class.Annotations = map[string]string{}
+		}
+		class.Annotations[DefaultClassAnnotation] = strconv.FormatBool(isDefault)
+		if _, e := classes.Update(i.ctx, &class, metav1.UpdateOptions{}); e != nil {
+			return fmt.Errorf("can not update ingress class %s: %s", class.Name, e)
+		}
+	}
+	if !found {
+		return fmt.Errorf("ingress class %s not found", className)
+	}
+	return nil
+}
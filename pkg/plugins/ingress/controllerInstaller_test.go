@@ -0,0 +1,108 @@
+package ingress
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	helmFake "github.com/qaware/minikube-support/pkg/packagemanager/helm/fake"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func Test_controllerInstaller_Install(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		chart        string
+		release      string
+		classes      []runtime.Object
+		installed    string
+		wantDefault  map[string]string
+		lastLogEntry string
+	}{
+		{"nginx", KindNginx, "ingress-nginx/ingress-nginx", "nginx-ingress", []runtime.Object{ingressClass("nginx", false)}, "", map[string]string{"nginx": "true"}, ""},
+		{"switch to traefik", KindTraefik, "traefik/traefik", "traefik", []runtime.Object{ingressClass("nginx", true), ingressClass("traefik", false)}, "nginx-ingress", map[string]string{"nginx": "false", "traefik": "true"}, ""},
+		{"contour", KindContour, "contour/contour", "contour", []runtime.Object{ingressClass("contour", true)}, "", map[string]string{"contour": "true"}, ""},
+		{"no ingress class", KindTraefik, "traefik/traefik", "traefik", nil, "", map[string]string{}, "Unable to make traefik the default ingress class: ingress class traefik not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := test.NewGlobal()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			clientSet := k8sFake.NewClientset(tt.classes...)
+			handler := fake.NewContextHandler(clientSet, nil)
+			controller, _ := GetController(tt.kind)
+
+			manager := helmFake.NewMockManager(ctrl)
+			manager.EXPECT().AddRepository(controller.Chart().RepositoryName, controller.Chart().RepositoryURL).Return(nil)
+			manager.EXPECT().UpdateRepository().Return(nil)
+			manager.EXPECT().Install(tt.chart, "", tt.release, "mks", gomock.Any(), false)
+			expectInstalled(manager, tt.installed)
+
+			NewControllerInstaller(manager, handler, NewSelector(&tt.kind, handler), nil).Install()
+
+			if tt.lastLogEntry != "" {
+				testutils.CheckLogEntry(t, hook, tt.lastLogEntry)
+			}
+			classes, e := clientSet.NetworkingV1().IngressClasses().List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, e)
+			got := map[string]string{}
+			for _, class := range classes.Items {
+				got[class.Name] = class.Annotations[DefaultClassAnnotation]
+			}
+			assert.Equal(t, tt.wantDefault, got)
+		})
+	}
+}
+
+func Test_controllerInstaller_Uninstall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	handler := fake.NewContextHandler(k8sFake.NewClientset(ingressClass("traefik", true)), nil)
+	manager := helmFake.NewMockManager(ctrl)
+	manager.EXPECT().Uninstall("traefik", "mks", true)
+	expectInstalled(manager, "nginx-ingress")
+
+	NewControllerInstaller(manager, handler, NewSelector(nil, handler), nil).Uninstall(false)
+}
+
+// expectInstalled expects the status of the releases of the other controllers of which only the given one is
+// installed and expects that it is uninstalled.
+func expectInstalled(manager *helmFake.MockManager, release string) {
+	manager.EXPECT().Status(gomock.Any(), "mks").AnyTimes().DoAndReturn(func(name string, _ string) (*helm.Revision, error) {
+		if name == release {
+			return &helm.Revision{Revision: 1}, nil
+		}
+		return nil, errors.New("release: not found")
+	})
+	if release != "" {
+		manager.EXPECT().Uninstall(release, "mks", true)
+	}
+}
+
+func Test_controllerInstaller_Values(t *testing.T) {
+	kind := KindTraefik
+	handler := fake.NewContextHandler(nil, nil)
+	overrides := &helm.Overrides{Set: []string{"ingress-controller.ports.web.nodePort=30080"}}
+	installer := NewControllerInstaller(nil, handler, NewSelector(&kind, handler), overrides).(*controllerInstaller)
+
+	values, e := installer.Values()
+
+	assert.NoError(t, e)
+	assert.Equal(t, map[string]interface{}{"enabled": true}, values["ingressClass"])
+	assert.Equal(t, map[string]interface{}{"web": map[string]interface{}{"nodePort": int64(30080)}}, values["ports"])
+
+	kind = "haproxy"
+	_, e = installer.Values()
+	assert.Error(t, e)
+}
@@ -0,0 +1,110 @@
+package ingress
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+)
+
+func TestGetController(t *testing.T) {
+	for _, kind := range Kinds() {
+		c, e := GetController(kind)
+		assert.NoError(t, e)
+		assert.Equal(t, kind, c.String())
+		assert.NotEmpty(t, c.Chart().Chart)
+		assert.NotEmpty(t, c.ServiceName())
+		assert.NotEmpty(t, c.ClassName())
+	}
+
+	_, e := GetController("haproxy")
+	assert.EqualError(t, e, "unknown ingress controller haproxy, use one of contour, nginx, traefik")
+}
+
+// Test_controller_Contour_images renders the contour chart to check that all images are published by the projects.
+// It is skipped if the chart can not be downloaded.
+func Test_controller_Contour_images(t *testing.T) {
+	c, _ := GetController(KindContour)
+	chart := c.Chart()
+	pull := action.NewPullWithOpts(action.WithConfig(&action.Configuration{}))
+	pull.Settings = cli.New()
+	pull.RepoURL = chart.RepositoryURL
+	pull.DestDir = t.TempDir()
+	if _, e := pull.Run(strings.TrimPrefix(chart.Chart, chart.RepositoryName+"/")); e != nil {
+		t.Skipf("can not download chart %s: %s", chart.Chart, e)
+	}
+	archives, _ := filepath.Glob(filepath.Join(pull.DestDir, "*.tgz"))
+	require.Len(t, archives, 1)
+	values, e := helm.ParseValues(c.Values())
+	require.NoError(t, e)
+
+	images, e := helm.ChartImages(archives[0], values)
+
+	require.NoError(t, e)
+	assert.NotEmpty(t, images)
+	for _, image := range images {
+		assert.True(t, strings.HasPrefix(image, "ghcr.io/projectcontour/contour:") || strings.HasPrefix(image, "docker.io/envoyproxy/envoy:"), "unexpected image %s", image)
+	}
+}
+
+func Test_controller_Values(t *testing.T) {
+	c, _ := GetController(KindNginx)
+	values := c.Values()
+	values["controller.publishService.enabled"] = "false"
+
+	assert.Equal(t, "true", c.Values()["controller.publishService.enabled"], "defaults must not be modified")
+}
+
+func TestSelector_Controller(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		classes []runtime.Object
+		noK8s   bool
+		want    string
+		wantErr bool
+	}{
+		{"selected", KindContour, []runtime.Object{ingressClass("traefik", true)}, false, KindContour, false},
+		{"unknown", "haproxy", nil, false, "", true},
+		{"installed", "", []runtime.Object{ingressClass("nginx", false), ingressClass("traefik", true)}, false, KindTraefik, false},
+		{"unknown default class", "", []runtime.Object{ingressClass("other", true)}, false, DefaultKind, false},
+		{"nothing installed", "", nil, false, DefaultKind, false},
+		{"no cluster", "", nil, true, DefaultKind, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := fake.NewContextHandler(k8sFake.NewClientset(tt.classes...), nil)
+			if tt.noK8s {
+				handler = fake.NewContextHandler(nil, nil)
+			}
+
+			got, e := NewSelector(&tt.kind, handler).Controller()
+			if (e != nil) != tt.wantErr {
+				t.Errorf("Controller() error = %v, wantErr %v", e, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got.String())
+			}
+		})
+	}
+}
+
+func ingressClass(name string, isDefault bool) *networkingv1.IngressClass {
+	class := &networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if isDefault {
+		class.Annotations = map[string]string{DefaultClassAnnotation: "true"}
+	}
+	return class
+}
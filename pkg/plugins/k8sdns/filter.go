@@ -0,0 +1,121 @@
+package k8sdns
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Filter restricts the kubernetes objects which are watched by one access type.
+// The zero value does not filter anything.
+type Filter struct {
+	// IncludeNamespaces is the list of namespaces to watch. If empty all namespaces will be watched.
+	IncludeNamespaces []string
+	// ExcludeNamespaces is the list of namespaces that should never be watched.
+	ExcludeNamespaces []string
+	// LabelSelector is a kubernetes label selector like "app=web,tier!=db".
+	LabelSelector string
+	// FieldSelector is a kubernetes field selector like "metadata.name!=kubernetes".
+	FieldSelector string
+}
+
+// Validate checks if the label and field selectors can be parsed.
+func (f *Filter) Validate() error {
+	if _, e := labels.Parse(f.LabelSelector); e != nil {
+		return fmt.Errorf("invalid label selector %q: %s", f.LabelSelector, e)
+	}
+	if _, e := fields.ParseSelector(f.fieldSelector()); e != nil {
+		return fmt.Errorf("invalid field selector %q: %s", f.FieldSelector, e)
+	}
+	return nil
+}
+
+// ListOptions creates the list options containing the label and field selectors of this filter.
+func (f *Filter) ListOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: f.LabelSelector,
+		FieldSelector: f.fieldSelector(),
+	}
+}
+
+// fieldSelector combines the configured field selector with the selectors for excluded namespaces.
+func (f *Filter) fieldSelector() string {
+	var selectors []string
+	if f.FieldSelector != "" {
+		selectors = append(selectors, f.FieldSelector)
+	}
+	for _, ns := range f.ExcludeNamespaces {
+		selectors = append(selectors, "metadata.namespace!="+ns)
+	}
+	return strings.Join(selectors, ",")
+}
+
+// namespace returns the namespace to use for list and watch requests.
+// Only if exactly one namespace is included the requests can be limited to it.
+func (f *Filter) namespace() string {
+	if len(f.IncludeNamespaces) == 1 {
+		return f.IncludeNamespaces[0]
+	}
+	return v1.NamespaceAll
+}
+
+// key identifies the filtering done by the accessors themselves. The selectors are part of the list options.
+func (f *Filter) key() string {
+	return "namespaces=" + strings.Join(f.IncludeNamespaces, ",")
+}
+
+// matchesNamespace checks if the given object is located in one of the included namespaces.
+func (f *Filter) matchesNamespace(obj runtime.Object) bool {
+	if len(f.IncludeNamespaces) == 0 {
+		return true
+	}
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return false
+	}
+	for _, ns := range f.IncludeNamespaces {
+		if object.GetNamespace() == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// filterList removes all items of the given list that are not located in one of the included namespaces.
+func (f *Filter) filterList(list runtime.Object) (runtime.Object, error) {
+	if len(f.IncludeNamespaces) < 2 {
+		return list, nil
+	}
+	items, e := meta.ExtractList(list)
+	if e != nil {
+		return nil, e
+	}
+	var result []runtime.Object
+	for _, obj := range items {
+		if f.matchesNamespace(obj) {
+			result = append(result, obj)
+		}
+	}
+	return list, meta.SetList(list, result)
+}
+
+// filterWatch wraps the given watch so that only events for objects in the included namespaces are emitted.
+// Error and bookmark events are always passed.
+func (f *Filter) filterWatch(w watch.Interface) watch.Interface {
+	if len(f.IncludeNamespaces) < 2 {
+		return w
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		if in.Type == watch.Error || in.Type == watch.Bookmark {
+			return in, true
+		}
+		return in, f.matchesNamespace(in.Object)
+	})
+}
@@ -0,0 +1,113 @@
+package k8sdns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{"empty", Filter{}, false},
+		{"valid", Filter{LabelSelector: "app=web,tier!=db", FieldSelector: "metadata.name!=kubernetes", ExcludeNamespaces: []string{"kube-system"}}, false},
+		{"invalid label selector", Filter{LabelSelector: "app=(web"}, true},
+		{"invalid field selector", Filter{FieldSelector: "metadata.name"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_ListOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   metav1.ListOptions
+	}{
+		{"empty", Filter{}, metav1.ListOptions{}},
+		{"label", Filter{LabelSelector: "app=web"}, metav1.ListOptions{LabelSelector: "app=web"}},
+		{"field", Filter{FieldSelector: "metadata.name=abc"}, metav1.ListOptions{FieldSelector: "metadata.name=abc"}},
+		{
+			"field and excludes",
+			Filter{FieldSelector: "metadata.name=abc", ExcludeNamespaces: []string{"a", "b"}},
+			metav1.ListOptions{FieldSelector: "metadata.name=abc,metadata.namespace!=a,metadata.namespace!=b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.ListOptions())
+		})
+	}
+}
+
+func TestFilter_namespace(t *testing.T) {
+	assert.Equal(t, v1.NamespaceAll, (&Filter{}).namespace())
+	assert.Equal(t, "a", (&Filter{IncludeNamespaces: []string{"a"}}).namespace())
+	assert.Equal(t, v1.NamespaceAll, (&Filter{IncludeNamespaces: []string{"a", "b"}}).namespace())
+}
+
+func TestFilter_filterList(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []v1.Service
+	}{
+		{"no includes", Filter{}, []v1.Service{*createDummyService("s1", "a"), *createDummyService("s2", "b"), *createDummyService("s3", "c")}},
+		{"one include", Filter{IncludeNamespaces: []string{"a"}}, []v1.Service{*createDummyService("s1", "a"), *createDummyService("s2", "b"), *createDummyService("s3", "c")}},
+		{"two includes", Filter{IncludeNamespaces: []string{"a", "c"}}, []v1.Service{*createDummyService("s1", "a"), *createDummyService("s3", "c")}},
+		{"no match", Filter{IncludeNamespaces: []string{"d", "e"}}, []v1.Service{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &v1.ServiceList{Items: []v1.Service{*createDummyService("s1", "a"), *createDummyService("s2", "b"), *createDummyService("s3", "c")}}
+			got, err := tt.filter.filterList(list)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.(*v1.ServiceList).Items)
+		})
+	}
+}
+
+func TestFilter_filterWatch(t *testing.T) {
+	filter := Filter{IncludeNamespaces: []string{"a", "b"}}
+	fakeWatch := watch.NewFake()
+	w := filter.filterWatch(fakeWatch)
+
+	go func() {
+		fakeWatch.Add(createDummyService("s1", "a"))
+		fakeWatch.Add(createDummyService("s2", "c"))
+		fakeWatch.Delete(createDummyService("s3", "b"))
+		fakeWatch.Stop()
+	}()
+
+	var names []string
+	for event := range w.ResultChan() {
+		names = append(names, event.Object.(*v1.Service).Name)
+	}
+	assert.Equal(t, []string{"s1", "s3"}, names)
+}
+
+func Test_serviceAccessor_List_filtered(t *testing.T) {
+	cs := fake.NewClientset(createDummyService("s1", "a"), createDummyService("s2", "b"), createDummyService("s3", "c"))
+	s := serviceAccessor{clientSet: cs, filter: Filter{IncludeNamespaces: []string{"a", "c"}}}
+
+	got, err := s.List(metav1.ListOptions{})
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []v1.Service{*createDummyService("s1", "a"), *createDummyService("s3", "c")}, got.(*v1.ServiceList).Items)
+}
+
+func createDummyService(name string, ns string) *v1.Service {
+	return &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+}
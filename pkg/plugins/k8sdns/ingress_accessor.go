@@ -14,33 +14,42 @@ import (
 // ingressAccessor provides list and watch access to ingresses.
 type ingressAccessor struct {
 	clientSet kubernetes.Interface
+	filter    Filter
 }
 
-// PreFetch returns a list of all ingresses and the corresponding list interface.
-func (i ingressAccessor) PreFetch() ([]runtime.Object, metaV1.ListInterface, error) {
+// Object returns an empty ingress.
+func (ingressAccessor) Object() runtime.Object {
+	return &networkingV1.Ingress{}
+}
+
+// Key identifies the ingresses including the namespace filter.
+func (i ingressAccessor) Key() string {
+	return "ingresses/" + i.filter.key()
+}
+
+// List returns a list of all ingresses matching the given options.
+func (i ingressAccessor) List(options metaV1.ListOptions) (runtime.Object, error) {
 	ingresses := i.clientSet.
 		NetworkingV1().
-		Ingresses(metaV1.NamespaceAll)
-	ingressList, e := ingresses.List(context.Background(), metaV1.ListOptions{})
+		Ingresses(i.filter.namespace())
+	ingressList, e := ingresses.List(context.Background(), options)
 	if e != nil {
-		return nil, nil, fmt.Errorf("can not list ingresses: %s", e)
+		return nil, fmt.Errorf("can not list ingresses: %s", e)
 	}
-	var items []runtime.Object
-	for _, ingress := range ingressList.Items {
-		ing := ingress
-		items = append(items, &ing)
-	}
-
-	return items, ingressList, nil
+	return i.filter.filterList(ingressList)
 }
 
 // Watch starts the watch process for ingresses.
 func (i ingressAccessor) Watch(options metaV1.ListOptions) (watch.Interface, error) {
 	ingresses := i.clientSet.
 		NetworkingV1().
-		Ingresses(metaV1.NamespaceAll)
+		Ingresses(i.filter.namespace())
 
-	return ingresses.Watch(context.Background(), options)
+	w, e := ingresses.Watch(context.Background(), options)
+	if e != nil {
+		return nil, e
+	}
+	return i.filter.filterWatch(w), nil
 }
 
 // ConvertToEntry converts a k8s ingress into the entry by flatten everything.
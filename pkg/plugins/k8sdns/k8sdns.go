@@ -9,7 +9,6 @@ import (
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/qaware/minikube-support/pkg/apis"
 	"github.com/qaware/minikube-support/pkg/kubernetes"
@@ -21,9 +20,11 @@ type k8sDns struct {
 	ctxHandler     kubernetes.ContextHandler
 	messageChannel chan *apis.MonitoringMessage
 	recordManager  coredns.Manager
+	informers      *kubernetes.Informers
 	watch          *kubernetes.Watcher
 	accessType     AccessType
 	accessor       accessor
+	filter         *Filter
 
 	currentEntries map[string]*entry
 }
@@ -32,11 +33,8 @@ type AccessType string
 
 // accessor is an abstraction for accessing different types of k8s objects. For example services vs. ingresses.
 type accessor interface {
-	// PreFetch returns a list of all services, ingresses or other k8s objects and the corresponding list interface.
-	PreFetch() ([]runtime.Object, metav1.ListInterface, error)
-
-	// Watch starts the watch process for services, ingresses or other k8s objects.
-	Watch(options metav1.ListOptions) (watch.Interface, error)
+	// Source provides list and watch access to services, ingresses or other k8s objects.
+	kubernetes.Source
 
 	// ConvertToEntry converts the given object into the entry by flatten everything.
 	ConvertToEntry(runtime.Object) (*entry, error)
@@ -52,15 +50,25 @@ const pluginName = "k8sdns-"
 
 // NewK8sDns will initialize a new ingress plugin.
 // It allows to configure the functions to add and remove the hosts in the dns backend.
-func NewK8sDns(contextHandler kubernetes.ContextHandler, recordManager coredns.Manager, accessType AccessType) apis.StartStopPlugin {
+// The filter is evaluated on every start, so it can be changed until then. A nil filter watches everything.
+// The informers are shared between all plugins. If nil, the plugin uses its own ones.
+func NewK8sDns(contextHandler kubernetes.ContextHandler, recordManager coredns.Manager, accessType AccessType, filter *Filter, informers *kubernetes.Informers) apis.StartStopPlugin {
 	if recordManager == nil {
 		recordManager = coredns.NewNoOpManager()
 	}
+	if filter == nil {
+		filter = &Filter{}
+	}
+	if informers == nil {
+		informers = kubernetes.NewInformers(kubernetes.DefaultResyncPeriod)
+	}
 
 	return &k8sDns{
 		ctxHandler:     contextHandler,
 		recordManager:  recordManager,
 		accessType:     accessType,
+		filter:         filter,
+		informers:      informers,
 		currentEntries: make(map[string]*entry),
 	}
 }
@@ -82,52 +90,48 @@ func (k8s *k8sDns) Start(messageChannel chan *apis.MonitoringMessage) (string, e
 		return "", fmt.Errorf("can not get clientSet: %s", e)
 	}
 
+	if e := k8s.filter.Validate(); e != nil {
+		return "", e
+	}
+
 	switch k8s.accessType {
 	case AccessTypeIngress:
-		k8s.accessor = ingressAccessor{clientSet: clientSet}
+		k8s.accessor = ingressAccessor{clientSet: clientSet, filter: *k8s.filter}
 	case AccessTypeService:
-		k8s.accessor = serviceAccessor{clientSet: clientSet}
+		k8s.accessor = serviceAccessor{clientSet: clientSet, filter: *k8s.filter}
 	default:
 		return "", fmt.Errorf("invalid access type given: %s", k8s.accessType)
 	}
 
-	objects, list, e := k8s.accessor.PreFetch()
-	if e != nil {
-		return "", e
-	}
-
-	for _, element := range objects {
-		e := k8s.AddedEvent(element)
-		if e != nil {
-			logrus.Warnf("Can not add entries for %s: %s", k8s.accessType, e)
-		}
-	}
-	_ = k8s.PostEvent()
-
-	k8s.watch, e = kubernetes.NewWatcher(k8s, nil, list.GetResourceVersion())
+	options := k8s.filter.ListOptions()
+	k8s.watch, e = k8s.informers.Watch(k8s.accessor, &options, k8s)
 	if e != nil {
 		return "", fmt.Errorf("can not start watcher: %s", e)
 	}
 
-	k8s.watch.Start()
 	return k8s.String(), nil
 }
 
 // Stop stopps the plugin.
-// It will shutdown the ingress watcher.
+// It will shutdown the ingress watcher and removes all dns records, so that the
+// plugin can be started again against another cluster.
 func (k8s *k8sDns) Stop() error {
 	if k8s.watch != nil {
 		k8s.watch.Stop()
+		k8s.watch = nil
+	}
+	for key, entry := range k8s.currentEntries {
+		for _, host := range entry.hostNames {
+			k8s.recordManager.RemoveHost(host)
+		}
+		delete(k8s.currentEntries, key)
 	}
 	return nil
 }
 
-func (k8s *k8sDns) PreWatch(options metav1.ListOptions) (watch.Interface, error) {
-	return k8s.accessor.Watch(options)
-}
-
 // AddedEvent adds the given ingress and adds all the host names to the dns
-// backend if they point to a target.
+// backend if they point to a target. If the ingress is already known it will be
+// handled as update.
 func (k8s *k8sDns) AddedEvent(obj runtime.Object) error {
 	if !k8s.accessor.MatchesPreconditions(obj) {
 		object, ok := obj.(metav1.Object)
@@ -143,6 +147,9 @@ func (k8s *k8sDns) AddedEvent(obj runtime.Object) error {
 	if e != nil {
 		return e
 	}
+	if _, ok := k8s.currentEntries[entry.String()]; ok {
+		return k8s.UpdatedEvent(obj)
+	}
 
 	if !entry.hasTargets() {
 		k8s.currentEntries[entry.String()] = entry
@@ -231,6 +238,37 @@ func (k8s *k8sDns) DeletedEvent(obj runtime.Object) error {
 	return nil
 }
 
+// Reconcile removes the entries and dns records of all objects that are missing
+// in the given list of all existing objects. The records of the other objects
+// are kept untouched, their changes are handled by the update events.
+func (k8s *k8sDns) Reconcile(objects []runtime.Object) error {
+	var errors *multierror.Error
+	existing := make(map[string]bool)
+	for _, obj := range objects {
+		if !k8s.accessor.MatchesPreconditions(obj) {
+			continue
+		}
+		entry, e := k8s.accessor.ConvertToEntry(obj)
+		if e != nil {
+			errors = multierror.Append(errors, e)
+			continue
+		}
+		existing[entry.String()] = true
+	}
+
+	for key, entry := range k8s.currentEntries {
+		if !existing[key] {
+			for _, host := range entry.hostNames {
+				k8s.recordManager.RemoveHost(host)
+			}
+			delete(k8s.currentEntries, key)
+			logrus.Infof("DNS records for stale %s %s removed", entry.typ, entry)
+		}
+	}
+	//noinspection GoNilness
+	return errors.ErrorOrNil()
+}
+
 // PostEvent generates a short overview about the currently handled ingresses and services.
 func (k8s *k8sDns) PostEvent() error {
 	var entryStrings []string
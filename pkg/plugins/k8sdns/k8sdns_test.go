@@ -6,7 +6,9 @@ import (
 
 	"github.com/qaware/minikube-support/pkg/apis"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	networkingV1 "k8s.io/api/networking/v1"
 )
@@ -349,3 +351,64 @@ func Test_k8sIngress_PostEvent(t *testing.T) {
 		})
 	}
 }
+
+func Test_k8sDns_Reconcile(t *testing.T) {
+	manager := newTestManager(t)
+	k8s := &k8sDns{
+		recordManager: manager,
+		currentEntries: map[string]*entry{
+			"t/stale": {name: "stale", namespace: "t", hostNames: []string{"stale"}, targetIps: []string{"127.0.0.1"}},
+			"t/known": {name: "known", namespace: "t", hostNames: []string{"known"}, targetIps: []string{"127.0.0.1"}},
+		},
+		accessor: ingressAccessor{},
+	}
+
+	err := k8s.Reconcile([]runtime.Object{
+		createDummyIngress("known", "t", "127.0.0.2", "", "known"),
+		createDummyIngress("new", "t", "127.0.0.1", "", "new"),
+		&v1.Service{},
+	})
+
+	assert.NoError(t, err)
+	sort.Strings(manager.removedHosts)
+	sort.Strings(manager.addedHosts)
+	assert.Equal(t, []string{"stale"}, manager.removedHosts)
+	assert.Empty(t, manager.addedHosts)
+	assert.Len(t, k8s.currentEntries, 1)
+	assert.Equal(t, []string{"127.0.0.1"}, k8s.currentEntries["t/known"].targetIps)
+}
+
+func Test_k8sDns_AddedEvent_known(t *testing.T) {
+	manager := newTestManager(t)
+	k8s := &k8sDns{
+		recordManager: manager,
+		currentEntries: map[string]*entry{
+			"t/t": {name: "t", namespace: "t", hostNames: []string{"1"}, targetIps: []string{"127.0.0.1"}},
+		},
+		accessor: ingressAccessor{},
+	}
+
+	err := k8s.AddedEvent(createDummyIngress("t", "t", "127.0.0.1", "", "1"))
+
+	assert.NoError(t, err)
+	assert.Empty(t, manager.addedHosts)
+	assert.Empty(t, manager.removedHosts)
+}
+
+func Test_k8sDns_Stop(t *testing.T) {
+	manager := newTestManager(t)
+	k8s := &k8sDns{
+		recordManager: manager,
+		currentEntries: map[string]*entry{
+			"t/a": {name: "a", namespace: "t", hostNames: []string{"a1", "a2"}, targetIps: []string{"127.0.0.1"}},
+			"t/b": {name: "b", namespace: "t", hostNames: []string{"b"}, targetIps: []string{"127.0.0.1"}},
+		},
+	}
+
+	err := k8s.Stop()
+
+	assert.NoError(t, err)
+	sort.Strings(manager.removedHosts)
+	assert.Equal(t, []string{"a1", "a2", "b"}, manager.removedHosts)
+	assert.Empty(t, k8s.currentEntries)
+}
@@ -14,32 +14,41 @@ import (
 // serviceAccessor provides list and watch access to services.
 type serviceAccessor struct {
 	clientSet kubernetes.Interface
+	filter    Filter
 }
 
-// PreFetch returns a list of all services and the corresponding list interface.
-func (s serviceAccessor) PreFetch() ([]runtime.Object, metav1.ListInterface, error) {
+// Object returns an empty service.
+func (serviceAccessor) Object() runtime.Object {
+	return &v1.Service{}
+}
+
+// Key identifies the services including the namespace filter.
+func (s serviceAccessor) Key() string {
+	return "services/" + s.filter.key()
+}
+
+// List returns a list of all services matching the given options.
+func (s serviceAccessor) List(options metav1.ListOptions) (runtime.Object, error) {
 	services := s.clientSet.
 		CoreV1().
-		Services(v1.NamespaceAll)
-	serviceList, e := services.List(context.Background(), metav1.ListOptions{})
+		Services(s.filter.namespace())
+	serviceList, e := services.List(context.Background(), options)
 	if e != nil {
-		return nil, nil, fmt.Errorf("can not list services: %s", e)
+		return nil, fmt.Errorf("can not list services: %s", e)
 	}
-	var items []runtime.Object
-	for _, service := range serviceList.Items {
-		srv := service
-		items = append(items, &srv)
-	}
-
-	return items, serviceList, nil
+	return s.filter.filterList(serviceList)
 }
 
 // Watch starts the watch process for services.
 func (s serviceAccessor) Watch(options metav1.ListOptions) (watch.Interface, error) {
 	services := s.clientSet.
 		CoreV1().
-		Services(v1.NamespaceAll)
-	return services.Watch(context.Background(), options)
+		Services(s.filter.namespace())
+	w, e := services.Watch(context.Background(), options)
+	if e != nil {
+		return nil, e
+	}
+	return s.filter.filterWatch(w), nil
 }
 
 // ConvertToEntry converts a k8s service into the entry by flatten everything.
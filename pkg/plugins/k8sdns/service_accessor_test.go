@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -13,7 +14,7 @@ import (
 	testing2 "k8s.io/client-go/testing"
 )
 
-func Test_serviceAccessor_PreFetch(t *testing.T) {
+func Test_serviceAccessor_List(t *testing.T) {
 	tests := []struct {
 		name              string
 		clientSetResponse []v1.Service
@@ -144,15 +145,16 @@ func Test_serviceAccessor_PreFetch(t *testing.T) {
 				return false, nil, nil
 			})
 
-			got, got1, err := i.PreFetch()
+			got1, err := i.List(metav1.ListOptions{})
 			if (err != nil) != tt.wantErr {
-				t.Errorf("PreFetch() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			assert.ElementsMatch(t, tt.want, got)
 			if tt.want1 == nil {
 				assert.Nil(t, got1)
 			} else {
+				got, _ := meta.ExtractList(got1)
+				assert.ElementsMatch(t, tt.want, got)
 				expectedList := tt.want1.(*v1.ServiceList)
 				actualList, ok := got1.(*v1.ServiceList)
 				if assert.True(t, ok) {
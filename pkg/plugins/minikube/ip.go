@@ -1,41 +1,59 @@
 package minikube
 
 import (
-	"strings"
+	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/qaware/minikube-support/pkg/apis"
 	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/plugins/coredns"
-	"github.com/qaware/minikube-support/pkg/sh"
+	"github.com/qaware/minikube-support/pkg/utils"
 )
 
-// ip is a simple plugin which adds a new resource entry for "vm.minikube." to the minikube ip address.
+// ipPollInterval is the interval in which the node addresses are checked for changes.
+var ipPollInterval = 10 * time.Second
+
+// ip is a plugin which adds a resource entry for "vm.minikube." to the ip address of the primary
+// cluster node and "<node>.node.minikube." for every node. The addresses are polled, so that the
+// records are updated after the cluster was restarted.
 type ip struct {
 	mutex             sync.Mutex
-	addIpTimer        *time.Timer
+	stop              chan struct{}
+	running           sync.WaitGroup
+	records           map[string]string
+	lastError         string
 	dnsBackendManager coredns.Manager
 	contextHandler    kubernetes.ContextHandler
 }
 
-const ipPluginName = "minikube-ip"
+const (
+	ipPluginName   = "minikube-ip"
+	vmHostName     = "vm.minikube"
+	nodeHostSuffix = ".node.minikube"
+)
 
 // NewIpPlugin initializes the minikube ip address plugin.
 func NewIpPlugin(manager coredns.Manager, handler kubernetes.ContextHandler) apis.StartStopPlugin {
-	return &ip{dnsBackendManager: manager, contextHandler: handler, mutex: sync.Mutex{}}
+	return &ip{dnsBackendManager: manager, contextHandler: handler, mutex: sync.Mutex{}, records: map[string]string{}}
 }
 
 func (i *ip) String() string {
 	return ipPluginName
 }
 
-func (i *ip) Start(chan *apis.MonitoringMessage) (boxName string, err error) {
+func (i *ip) Start(messageChannel chan *apis.MonitoringMessage) (boxName string, err error) {
 	i.mutex.Lock()
 	defer i.mutex.Unlock()
-	i.addIpTimer = time.AfterFunc(10*time.Second, i.addVmIp)
+	i.stop = make(chan struct{})
+	i.running.Add(1)
+	go i.poll(messageChannel, i.stop)
 	return ipPluginName, nil
 }
 
@@ -43,35 +61,148 @@ func (*ip) IsSingleRunnable() bool {
 	return false
 }
 
+// Stop stops polling and removes all added records.
 func (i *ip) Stop() error {
 	i.mutex.Lock()
-	defer i.mutex.Unlock()
-	i.addIpTimer.Stop()
+	if i.stop != nil {
+		close(i.stop)
+		i.stop = nil
+	}
+	i.mutex.Unlock()
+	i.running.Wait()
+
+	for host := range i.records {
+		i.dnsBackendManager.RemoveHost(host)
+		delete(i.records, host)
+	}
+	i.lastError = ""
 	return nil
 }
 
-// addVmIp tries to get the current minikube ip and adds a new resource entry "vm.minikube" to this ip.
-func (i *ip) addVmIp() {
-	isMinikube, e := i.contextHandler.IsMinikube()
+// poll updates the records after every poll interval until the stop channel is closed.
+func (i *ip) poll(messageChannel chan *apis.MonitoringMessage, stop chan struct{}) {
+	defer i.running.Done()
+	timer := time.NewTimer(ipPollInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+			if message, changed := i.update(); changed {
+				select {
+				case messageChannel <- &apis.MonitoringMessage{Box: ipPluginName, Message: message}:
+				case <-stop:
+					return
+				}
+			}
+			timer.Reset(ipPollInterval)
+		}
+	}
+}
+
+// update determines the current node addresses and updates all changed records.
+// It returns the new content of the box and if it has changed.
+func (i *ip) update() (string, bool) {
+	records, e := i.currentRecords()
 	if e != nil {
-		logrus.Errorf("can not determ if running in minikube: %s", e)
-		return
+		if e.Error() == i.lastError {
+			return "", false
+		}
+		logrus.Warnf("Can not determ the node addresses: %s", e)
+		i.lastError = e.Error()
+		return e.Error(), true
+	}
+
+	changed := i.lastError != ""
+	i.lastError = ""
+	for host := range i.records {
+		if _, ok := records[host]; !ok {
+			i.dnsBackendManager.RemoveHost(host)
+			delete(i.records, host)
+			changed = true
+		}
+	}
+	for host, address := range records {
+		if i.records[host] == address {
+			continue
+		}
+		i.dnsBackendManager.RemoveHost(host)
+		if e := i.dnsBackendManager.AddHost(host, address); e != nil {
+			logrus.Errorf("unable to add record for %s: %s", host, e)
+			continue
+		}
+		i.records[host] = address
+		changed = true
 	}
-	if !isMinikube {
-		logrus.Info("Context is not set to minikube. Do not add A-record for vm.minikube.")
-		return
+	if !changed {
+		return "", false
 	}
+	return i.formatRecords(), true
+}
 
-	ip, e := sh.RunCmd("minikube", "ip")
+// currentRecords returns the expected records. The record for vm.minikube points to the primary
+// node of the cluster provider. The node records are created from the kubernetes node objects.
+func (i *ip) currentRecords() (map[string]string, error) {
+	records := map[string]string{}
+	provider, e := i.contextHandler.GetClusterProvider()
 	if e != nil {
-		logrus.Errorf("can not determ minikube ip: %s", e)
-		return
+		return nil, fmt.Errorf("can not determ the local cluster: %s", e)
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("context is not set to a local cluster")
 	}
 
-	ip = strings.Trim(ip, "\n\r \t")
-	e = i.dnsBackendManager.AddHost("vm.minikube", ip)
+	ips, e := provider.NodeIPs()
+	if e != nil {
+		return nil, fmt.Errorf("can not determ %s node ip: %s", provider, e)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%s cluster %s has no nodes", provider, provider.ClusterName())
+	}
+	records[vmHostName] = ips[0]
+
+	clientSet, e := i.contextHandler.GetClientSet()
+	if e != nil {
+		return nil, e
+	}
+	nodes, e := clientSet.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if e != nil {
+		return nil, fmt.Errorf("can not list nodes: %s", e)
+	}
+	for _, node := range nodes.Items {
+		if address := internalIP(node); address != "" {
+			records[node.Name+nodeHostSuffix] = address
+		}
+	}
+	return records, nil
+}
+
+// internalIP returns the internal ip address of the given node or an empty string if it has none.
+func internalIP(node v1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == v1.NodeInternalIP {
+			return address.Address
+		}
+	}
+	return ""
+}
+
+// formatRecords formats all records as table sorted by the host name.
+func (i *ip) formatRecords() string {
+	var hosts []string
+	for host := range i.records {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	var lines []string
+	for _, host := range hosts {
+		lines = append(lines, fmt.Sprintf("%s\t %s\n", host, i.records[host]))
+	}
+	table, e := utils.FormatAsTable(lines, "Hostname\t IP\n")
 	if e != nil {
-		logrus.Errorf("unable to add record for vm.minikube: %s", e)
-		return
+		return e.Error()
 	}
+	return table
 }
@@ -1,39 +1,140 @@
 package minikube
 
 import (
-	"os/exec"
+	"context"
 	"testing"
+	"time"
 
-	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
-	"github.com/qaware/minikube-support/pkg/sh"
-	"github.com/qaware/minikube-support/pkg/testutils"
 	"github.com/stretchr/testify/assert"
-)
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 
-func Test_ip_addVmIp(t *testing.T) {
-	sh.ExecCommand = testutils.FakeExecCommand
-	defer func() { sh.ExecCommand = exec.Command }()
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/cluster"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+)
 
+func Test_ip_update(t *testing.T) {
 	manager := newTestManager(t)
-	handler := fake.NewContextHandler(nil, nil)
-	handler.MiniKube = true
+	clientSet := k8sfake.NewClientset(createNode("minikube", "192.168.64.3"), createNode("minikube-m02", "192.168.64.4"))
+	handler := fake.NewContextHandler(clientSet, nil)
+	provider := &testProvider{ips: []string{"192.168.64.3", "192.168.64.4"}}
+	handler.Provider = provider
 	i := NewIpPlugin(manager, handler).(*ip)
-	i.addVmIp()
 
-	assert.Len(t, manager.addedHosts, 1)
-	assert.Equal(t, "vm.minikube", manager.addedHosts[0])
+	message, changed := i.update()
+
+	assert.True(t, changed)
+	assert.Contains(t, message, "minikube-m02.node.minikube")
+	assert.ElementsMatch(t, []string{"vm.minikube", "minikube.node.minikube", "minikube-m02.node.minikube"}, manager.addedHosts)
+	assert.Equal(t, map[string]string{
+		"vm.minikube":                "192.168.64.3",
+		"minikube.node.minikube":     "192.168.64.3",
+		"minikube-m02.node.minikube": "192.168.64.4",
+	}, i.records)
+
+	_, changed = i.update()
+	assert.False(t, changed)
+
+	// the cluster was restarted with new addresses and without the second node
+	provider.ips = []string{"192.168.64.5"}
+	_ = clientSet.CoreV1().Nodes().Delete(context.Background(), "minikube-m02", metav1.DeleteOptions{})
+	_, _ = clientSet.CoreV1().Nodes().Update(context.Background(), createNode("minikube", "192.168.64.5"), metav1.UpdateOptions{})
+	manager.addedHosts = []string{}
+
+	_, changed = i.update()
+	assert.True(t, changed)
+	assert.ElementsMatch(t, []string{"vm.minikube", "minikube.node.minikube"}, manager.addedHosts)
+	assert.Equal(t, map[string]string{"vm.minikube": "192.168.64.5", "minikube.node.minikube": "192.168.64.5"}, i.records)
 }
 
-func Test_ip_addVmIp_noMinikube(t *testing.T) {
+func Test_ip_update_noLocalCluster(t *testing.T) {
 	manager := newTestManager(t)
 	handler := fake.NewContextHandler(nil, nil)
 	handler.MiniKube = false
 	i := NewIpPlugin(manager, handler).(*ip)
-	i.addVmIp()
 
+	message, changed := i.update()
+	assert.True(t, changed)
+	assert.Equal(t, "context is not set to a local cluster", message)
+
+	_, changed = i.update()
+	assert.False(t, changed, "error is only reported once")
 	assert.Len(t, manager.addedHosts, 0)
 }
 
+func Test_ip_Start(t *testing.T) {
+	ipPollInterval = 10 * time.Millisecond
+	defer func() { ipPollInterval = 10 * time.Second }()
+
+	manager := newTestManager(t)
+	handler := fake.NewContextHandler(k8sfake.NewClientset(createNode("minikube", "192.168.64.3")), nil)
+	handler.Provider = &testProvider{ips: []string{"192.168.64.3"}}
+	i := NewIpPlugin(manager, handler)
+	messageChannel := make(chan *apis.MonitoringMessage, 1)
+
+	box, err := i.Start(messageChannel)
+	assert.NoError(t, err)
+	assert.Equal(t, "minikube-ip", box)
+	message := <-messageChannel
+	assert.Equal(t, "minikube-ip", message.Box)
+	assert.Contains(t, message.Message, "192.168.64.3")
+
+	assert.NoError(t, i.Stop())
+	assert.ElementsMatch(t, []string{"vm.minikube", "minikube.node.minikube"}, manager.addedHosts)
+}
+
+func Test_ip_Stop_blockedChannel(t *testing.T) {
+	ipPollInterval = 10 * time.Millisecond
+	defer func() { ipPollInterval = 10 * time.Second }()
+
+	handler := fake.NewContextHandler(k8sfake.NewClientset(createNode("minikube", "192.168.64.3")), nil)
+	handler.Provider = &testProvider{ips: []string{"192.168.64.3"}}
+	i := NewIpPlugin(newTestManager(t), handler)
+	_, err := i.Start(make(chan *apis.MonitoringMessage))
+	assert.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+
+	stopped := make(chan error)
+	go func() { stopped <- i.Stop() }()
+	select {
+	case e := <-stopped:
+		assert.NoError(t, e)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Stop is blocked by the unread message")
+	}
+}
+
+func Test_ip_Stop(t *testing.T) {
+	manager := newTestManager(t)
+	i := NewIpPlugin(manager, fake.NewContextHandler(nil, nil)).(*ip)
+	i.records["vm.minikube"] = "192.168.64.3"
+
+	assert.NoError(t, i.Stop())
+	assert.Equal(t, []string{"vm.minikube"}, manager.removedHosts)
+	assert.Empty(t, i.records)
+}
+
+func createNode(name string, ip string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeHostName, Address: name}, {Type: v1.NodeInternalIP, Address: ip}}},
+	}
+}
+
+type testProvider struct {
+	ips []string
+}
+
+func (p *testProvider) String() string                             { return "test" }
+func (p *testProvider) ClusterName() string                        { return "test" }
+func (p *testProvider) NodeIPs() ([]string, error)                 { return p.ips, nil }
+func (p *testProvider) LoadBalancer() cluster.LoadBalancerStrategy { return cluster.LoadBalancerNone }
+func (p *testProvider) TunnelCommand() []string                    { return nil }
+func (p *testProvider) LoadImage(string) error                     { return nil }
+func (p *testProvider) HostGateway() (string, error)               { return "192.168.64.1", nil }
+
 type testManager struct {
 	t            *testing.T
 	addedHosts   []string
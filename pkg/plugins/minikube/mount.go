@@ -0,0 +1,288 @@
+package minikube
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/sh"
+	"github.com/qaware/minikube-support/pkg/utils"
+)
+
+const (
+	mountPluginName = "mount"
+	mountBoxName    = "minikube-mount"
+)
+
+// mountRestartDelay is the time to wait before a terminated mount is started again.
+var mountRestartDelay = 5 * time.Second
+
+// Mount is the definition of a single minikube mount.
+type Mount struct {
+	// HostPath is the absolute path of the mounted folder on the host.
+	HostPath string
+	// GuestPath is the path of the mount inside the minikube machine.
+	GuestPath string
+}
+
+// ParseMount parses a mount definition in the format of `minikube mount`: "<host path>:<guest path>".
+// The host path is made absolute and has to exist.
+func ParseMount(definition string) (Mount, error) {
+	i := strings.LastIndex(definition, ":")
+	if i <= 0 || i == len(definition)-1 {
+		return Mount{}, fmt.Errorf("invalid mount %q: expected <host path>:<guest path>", definition)
+	}
+	guestPath := definition[i+1:]
+	if !strings.HasPrefix(guestPath, "/") {
+		return Mount{}, fmt.Errorf("invalid mount %q: guest path must be absolute", definition)
+	}
+	hostPath, e := filepath.Abs(definition[:i])
+	if e != nil {
+		return Mount{}, fmt.Errorf("invalid host path in mount %q: %s", definition, e)
+	}
+	if _, e := os.Stat(hostPath); e != nil {
+		return Mount{}, fmt.Errorf("invalid host path in mount %q: %s", definition, e)
+	}
+	return Mount{HostPath: hostPath, GuestPath: guestPath}, nil
+}
+
+// String returns the mount in the format of `minikube mount`.
+func (m Mount) String() string {
+	return m.HostPath + ":" + m.GuestPath
+}
+
+// mountState is the current state of a single supervised mount process.
+type mountState struct {
+	mount    Mount
+	state    string
+	address  string
+	message  string
+	restarts int
+	command  *exec.Cmd
+}
+
+// update updates the state with a single output line of `minikube mount`.
+func (s *mountState) update(line string) {
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.Contains(line, "Successfully mounted"):
+		s.state = "mounted"
+		s.message = ""
+	case strings.Contains(line, "Bind Address:"):
+		_, address, _ := strings.Cut(line, "Bind Address:")
+		s.address = strings.TrimSpace(address)
+	case strings.Contains(line, "Exiting due to") || strings.HasPrefix(line, "Error") || strings.HasPrefix(line, "❌"):
+		s.message = strings.TrimSpace(strings.TrimPrefix(line, "❌"))
+	}
+}
+
+// mount is a plugin that runs `minikube mount` for every configured mount and restarts it if it terminates,
+// for example after the laptop was sleeping.
+type mount struct {
+	contextHandler kubernetes.ContextHandler
+	definitions    *[]string
+	messageChannel chan *apis.MonitoringMessage
+	states         []*mountState
+	stop           chan struct{}
+	running        sync.WaitGroup
+	mutex          sync.Mutex
+}
+
+// NewMount initializes the mount plugin for the mounts defined by the given flag values.
+func NewMount(handler kubernetes.ContextHandler, definitions *[]string) apis.StartStopPlugin {
+	return &mount{contextHandler: handler, definitions: definitions, mutex: sync.Mutex{}}
+}
+
+func (m *mount) String() string {
+	return mountPluginName
+}
+
+func (*mount) IsSingleRunnable() bool {
+	return true
+}
+
+// Start starts one supervised `minikube mount` process per configured mount.
+func (m *mount) Start(monitoringChannel chan *apis.MonitoringMessage) (boxName string, err error) {
+	var mounts []Mount
+	for _, definition := range *m.definitions {
+		mount, e := ParseMount(definition)
+		if e != nil {
+			return "", e
+		}
+		mounts = append(mounts, mount)
+	}
+	if len(mounts) == 0 {
+		monitoringChannel <- &apis.MonitoringMessage{Box: mountBoxName, Message: "No mounts configured. Use --mount to define them."}
+		return mountBoxName, nil
+	}
+
+	profile, e := m.contextHandler.GetMinikubeProfile()
+	if e != nil {
+		return "", e
+	}
+	if profile == nil {
+		monitoringChannel <- &apis.MonitoringMessage{Box: mountBoxName, Message: "Context is no minikube cluster. Folders are not mounted."}
+		return mountBoxName, nil
+	}
+
+	m.mutex.Lock()
+	m.messageChannel = monitoringChannel
+	m.stop = make(chan struct{})
+	m.states = nil
+	for _, mount := range mounts {
+		state := &mountState{mount: mount, state: "starting"}
+		m.states = append(m.states, state)
+		m.running.Add(1)
+		go m.supervise(state, profile.Args("mount", mount.String()), m.stop)
+	}
+	m.mutex.Unlock()
+
+	m.sendStates()
+	return mountBoxName, nil
+}
+
+// Stop terminates all running mount processes.
+func (m *mount) Stop() error {
+	m.mutex.Lock()
+	if m.stop != nil {
+		close(m.stop)
+		m.stop = nil
+	}
+	for _, state := range m.states {
+		if state.command != nil && state.command.Process != nil {
+			_ = state.command.Process.Signal(syscall.SIGTERM)
+		}
+	}
+	m.mutex.Unlock()
+	m.running.Wait()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.states = nil
+	return nil
+}
+
+// Health returns an error if a mount has failed.
+func (m *mount) Health() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var failed []string
+	for _, state := range m.states {
+		if state.state == "failed" {
+			failed = append(failed, fmt.Sprintf("%s (%s)", state.mount, state.message))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("mounts are not available: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// supervise runs the mount process and restarts it after it terminated until the stop channel is closed.
+func (m *mount) supervise(state *mountState, args []string, stop chan struct{}) {
+	defer m.running.Done()
+	for {
+		e := m.run(state, args, stop)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		m.mutex.Lock()
+		logrus.Warnf("minikube mount %s terminated: %s", state.mount, e)
+		state.state = "failed"
+		if state.message == "" {
+			state.message = e.Error()
+		}
+		state.restarts++
+		state.command = nil
+		m.mutex.Unlock()
+		m.sendStates()
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(mountRestartDelay):
+		}
+	}
+}
+
+// run starts a single `minikube mount` process and updates the state with its output until it terminates.
+func (m *mount) run(state *mountState, args []string, stop chan struct{}) error {
+	command := sh.ExecCommand("minikube", args...)
+	command.Env = append(command.Env, os.Environ()...)
+	output, e := command.StdoutPipe()
+	if e != nil {
+		return fmt.Errorf("can not open stdout: %s", e)
+	}
+	command.Stderr = command.Stdout
+
+	m.mutex.Lock()
+	select {
+	case <-stop:
+		m.mutex.Unlock()
+		return nil
+	default:
+	}
+	if e := command.Start(); e != nil {
+		m.mutex.Unlock()
+		return fmt.Errorf("can not start minikube mount: %s", e)
+	}
+	state.command = command
+	state.state = "starting"
+	state.message = ""
+	m.mutex.Unlock()
+
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		m.mutex.Lock()
+		previous := *state
+		state.update(scanner.Text())
+		changed := previous.state != state.state || previous.address != state.address || previous.message != state.message
+		m.mutex.Unlock()
+		if changed {
+			m.sendStates()
+		}
+	}
+
+	if e := command.Wait(); e != nil {
+		return e
+	}
+	return fmt.Errorf("minikube mount ended")
+}
+
+// sendStates sends the states of all mounts as table to the box.
+func (m *mount) sendStates() {
+	m.mutex.Lock()
+	var lines []string
+	for _, state := range m.states {
+		status := state.state
+		if state.message != "" {
+			status += ": " + state.message
+		}
+		address := state.address
+		if address == "" {
+			address = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%s\t %s\t %s\t %d\t %s\n", state.mount.HostPath, state.mount.GuestPath, address, state.restarts, status))
+	}
+	messageChannel := m.messageChannel
+	m.mutex.Unlock()
+
+	table, e := utils.FormatAsTable(lines, "Host Path\t Guest Path\t Address\t Restarts\t State\n")
+	if e != nil {
+		table = e.Error()
+	}
+	messageChannel <- &apis.MonitoringMessage{Box: mountBoxName, Message: table}
+}
@@ -0,0 +1,155 @@
+package minikube
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/sh"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func TestParseMount(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		definition string
+		want       Mount
+		wantErr    bool
+	}{
+		{"valid", dir + ":/src", Mount{HostPath: dir, GuestPath: "/src"}, false},
+		{"relative host path", ".:/src", Mount{HostPath: mustAbs(t, "."), GuestPath: "/src"}, false},
+		{"missing host path", filepath.Join(dir, "missing") + ":/src", Mount{}, true},
+		{"relative guest path", dir + ":src", Mount{}, true},
+		{"no guest path", dir + ":", Mount{}, true},
+		{"no separator", dir, Mount{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := ParseMount(tt.definition)
+			if tt.wantErr {
+				assert.Error(t, e)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_mountState_update(t *testing.T) {
+	state := &mountState{state: "starting"}
+	for _, line := range []string{
+		"📁  Mounting host path /Users/dev/src into VM as /src ...",
+		"    ▪ Bind Address: 192.168.64.1:53925",
+		"✅  Successfully mounted /Users/dev/src to /src",
+	} {
+		state.update(line)
+	}
+	assert.Equal(t, "mounted", state.state)
+	assert.Equal(t, "192.168.64.1:53925", state.address)
+	assert.Empty(t, state.message)
+
+	state.update("❌  Exiting due to GUEST_MOUNT: mount failed")
+	assert.Equal(t, "Exiting due to GUEST_MOUNT: mount failed", state.message)
+}
+
+func Test_mount_Start(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+
+	dir := t.TempDir()
+	handler := fake.NewContextHandler(nil, nil)
+	handler.MiniKube = true
+	m := NewMount(handler, &[]string{dir + ":/src"})
+	messages := make(chan *apis.MonitoringMessage, 100)
+
+	box, e := m.Start(messages)
+	assert.NoError(t, e)
+	assert.Equal(t, "minikube-mount", box)
+	message := waitForMountMessage(t, messages, "mounted")
+	assert.Contains(t, message, "192.168.64.1:53925")
+	assert.NoError(t, apis.CheckHealth(m))
+
+	assert.NoError(t, m.Stop())
+}
+
+func Test_mount_Start_restart(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	mountRestartDelay = 10 * time.Millisecond
+	defer func() {
+		sh.ExecCommand = exec.Command
+		mountRestartDelay = 5 * time.Second
+	}()
+
+	handler := fake.NewContextHandler(nil, nil)
+	handler.MiniKube = true
+	m := NewMount(handler, &[]string{t.TempDir() + ":/fail"})
+	messages := make(chan *apis.MonitoringMessage, 100)
+
+	_, e := m.Start(messages)
+	assert.NoError(t, e)
+	waitForMountMessage(t, messages, "failed: Exiting due to GUEST_MOUNT")
+	// the restart counter is increased after every terminated process
+	waitForMountMessage(t, messages, " 2 ")
+	assert.ErrorContains(t, apis.CheckHealth(m), "mounts are not available")
+
+	assert.NoError(t, m.Stop())
+}
+
+func Test_mount_Start_noMounts(t *testing.T) {
+	m := NewMount(fake.NewContextHandler(nil, nil), &[]string{})
+	messages := make(chan *apis.MonitoringMessage, 1)
+
+	box, e := m.Start(messages)
+	assert.NoError(t, e)
+	assert.Equal(t, "minikube-mount", box)
+	assert.Contains(t, (<-messages).Message, "No mounts configured")
+	assert.NoError(t, m.Stop())
+}
+
+func Test_mount_Start_noMinikube(t *testing.T) {
+	handler := fake.NewContextHandler(nil, nil)
+	handler.MiniKube = false
+	m := NewMount(handler, &[]string{t.TempDir() + ":/src"})
+	messages := make(chan *apis.MonitoringMessage, 1)
+
+	_, e := m.Start(messages)
+	assert.NoError(t, e)
+	assert.Contains(t, (<-messages).Message, "Context is no minikube cluster")
+	assert.NoError(t, m.Stop())
+}
+
+func Test_mount_Start_invalidMount(t *testing.T) {
+	m := NewMount(fake.NewContextHandler(nil, nil), &[]string{"invalid"})
+
+	_, e := m.Start(make(chan *apis.MonitoringMessage, 1))
+	assert.Error(t, e)
+}
+
+func waitForMountMessage(t *testing.T, messages chan *apis.MonitoringMessage, content string) string {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case message := <-messages:
+			if strings.Contains(message.Message, content) {
+				return message.Message
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for message %q", content)
+			return ""
+		}
+	}
+}
+
+func mustAbs(t *testing.T, path string) string {
+	abs, e := filepath.Abs(path)
+	assert.NoError(t, e)
+	return abs
+}
@@ -7,12 +7,14 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"syscall"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/cluster"
 	"github.com/qaware/minikube-support/pkg/kubernetes"
 	"github.com/qaware/minikube-support/pkg/sh"
 )
@@ -21,12 +23,12 @@ type tunnel struct {
 	command        *exec.Cmd
 	contextHandler kubernetes.ContextHandler
 	runWait        *sync.WaitGroup
+	status         *TunnelStatus
+	statusMutex    sync.RWMutex
 }
 
 func NewTunnel(handler kubernetes.ContextHandler) apis.StartStopPlugin {
-	group := &sync.WaitGroup{}
-	group.Add(1)
-	return &tunnel{contextHandler: handler, runWait: group}
+	return &tunnel{contextHandler: handler, runWait: &sync.WaitGroup{}}
 }
 
 const tunnelBoxName = "minikube-tunnel"
@@ -42,23 +44,40 @@ func (t *tunnel) Start(monitoringChannel chan *apis.MonitoringMessage) (boxName
 		return "", fmt.Errorf("unable to enter sudo mode for minikube tunnel: %e", e)
 	}
 
-	isMinikube, e := t.contextHandler.IsMinikube()
+	provider, e := t.contextHandler.GetClusterProvider()
 	if e != nil {
 		return "", e
 	}
-	if !isMinikube {
-		return "", nil
+	if provider == nil {
+		monitoringChannel <- &apis.MonitoringMessage{Box: tunnelBoxName, Message: "Context is no local cluster. LoadBalancer services are not tunneled."}
+		return tunnelBoxName, nil
+	}
+	if provider.LoadBalancer() != cluster.LoadBalancerTunnel {
+		monitoringChannel <- &apis.MonitoringMessage{
+			Box:     tunnelBoxName,
+			Message: fmt.Sprintf("%s cluster %s does not support LoadBalancer services (strategy: %s).", provider, provider.ClusterName(), provider.LoadBalancer()),
+		}
+		return tunnelBoxName, nil
 	}
 
-	t.command = sh.ExecSudoCommand("minikube", "tunnel")
+	tunnelCommand := provider.TunnelCommand()
+	t.runWait.Add(1)
+	t.command = sh.ExecSudoCommand(tunnelCommand[0], tunnelCommand[1:]...)
 	t.command.Env = append(t.command.Env, os.Environ()...)
 	stdoutPipe, e := t.command.StdoutPipe()
 	if e != nil {
+		t.runWait.Done()
+		t.command = nil
 		return "", fmt.Errorf("can not open stdout: %s", e)
 	}
 
 	scanner := initScanner(stdoutPipe)
-	go scanForStatusMessages(scanner, monitoringChannel)
+	_, isMinikube := provider.(*cluster.Minikube)
+	if !isMinikube {
+		// only minikube prints its state in status blocks
+		scanner.Split(bufio.ScanLines)
+	}
+	go t.scanForStatusMessages(scanner, monitoringChannel, isMinikube)
 
 	go func() {
 		e := t.command.Start()
@@ -75,13 +94,28 @@ func (t *tunnel) Start(monitoringChannel chan *apis.MonitoringMessage) (boxName
 	return tunnelBoxName, nil
 }
 
-func scanForStatusMessages(scanner *bufio.Scanner, monitoringChannel chan *apis.MonitoringMessage) {
+// scanForStatusMessages forwards the output of the tunnel to the box. If parse is set, the output
+// consists of minikube status blocks which are shown as table below the health of the tunnel.
+func (t *tunnel) scanForStatusMessages(scanner *bufio.Scanner, monitoringChannel chan *apis.MonitoringMessage, parse bool) {
 	for scanner.Scan() {
 		text := scanner.Text()
 		if text == "" {
 			continue
 		}
 
+		if parse {
+			status, e := ParseTunnelStatus(text)
+			if e != nil {
+				logrus.Debugf("Can not parse minikube tunnel status: %s", e)
+			} else {
+				t.setStatus(status)
+				text = status.Table()
+				if e := t.Health(); e != nil {
+					text = fmt.Sprintf("Unhealthy: %s\n\n%s", e, text)
+				}
+			}
+		}
+
 		monitoringChannel <- &apis.MonitoringMessage{
 			Box:     tunnelBoxName,
 			Message: text,
@@ -89,9 +123,57 @@ func scanForStatusMessages(scanner *bufio.Scanner, monitoringChannel chan *apis.
 	}
 }
 
+// setStatus stores the given status and logs all errors that were not reported by the previous status.
+func (t *tunnel) setStatus(status *TunnelStatus) {
+	t.statusMutex.Lock()
+	defer t.statusMutex.Unlock()
+
+	for component, e := range status.Errors {
+		if t.status == nil || t.status.Errors[component] != e {
+			logrus.Warnf("minikube tunnel %s: %s", component, e)
+		}
+	}
+	if t.status != nil && t.status.MachineState != status.MachineState {
+		logrus.Infof("minikube machine %s is %s", status.Machine, status.MachineState)
+	}
+	t.status = status
+}
+
+// Status returns the last reported status of the minikube tunnel or nil if there is none.
+func (t *tunnel) Status() *TunnelStatus {
+	t.statusMutex.RLock()
+	defer t.statusMutex.RUnlock()
+	return t.status
+}
+
+// Health returns an error if the last reported status of the tunnel is not healthy.
+func (t *tunnel) Health() error {
+	status := t.Status()
+	if status == nil || status.Healthy() {
+		return nil
+	}
+	if len(status.Errors) == 0 {
+		return fmt.Errorf("minikube machine %s is %s", status.Machine, status.MachineState)
+	}
+	return fmt.Errorf("minikube tunnel has errors: %s", strings.Join(status.ErrorMessages(), ", "))
+}
+
+// Stop terminates the running tunnel. It does nothing if the tunnel was not
+// started because the cluster does not need one.
 func (t *tunnel) Stop() error {
 	t.runWait.Wait()
-	return t.command.Process.Signal(syscall.SIGTERM)
+	if t.command == nil {
+		return nil
+	}
+	command := t.command
+	t.command = nil
+	t.statusMutex.Lock()
+	t.status = nil
+	t.statusMutex.Unlock()
+	if command.Process == nil {
+		return nil
+	}
+	return command.Process.Signal(syscall.SIGTERM)
 }
 func (t *tunnel) String() string {
 	return tunnelBoxName
@@ -0,0 +1,125 @@
+package minikube
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const noErrors = "no errors"
+
+// TunnelStatus is the parsed status block that is printed periodically by `minikube tunnel`.
+type TunnelStatus struct {
+	// Machine is the name of the minikube machine.
+	Machine string
+	// MachineState is the state of the machine like "Running".
+	MachineState string
+	// Pid is the process id of the tunnel.
+	Pid int
+	// Route is the route for the service cidr like "10.96.0.0/12 -> 192.168.64.13".
+	Route string
+	// MinikubeIP is the gateway of the route.
+	MinikubeIP string
+	// Services contains the names of all tunneled LoadBalancer services.
+	Services []string
+	// Errors contains the error of every tunnel component that reported one, for example
+	// "router" -> "conflicting route: ...".
+	Errors map[string]string
+}
+
+// ParseTunnelStatus parses a single status block of `minikube tunnel` without the leading "Status:".
+func ParseTunnelStatus(text string) (*TunnelStatus, error) {
+	status := &TunnelStatus{Services: []string{}, Errors: map[string]string{}}
+	values := map[string]string{}
+	inErrors := false
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid tunnel status line: %s", line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if key == "errors" {
+			inErrors = true
+			continue
+		}
+		if inErrors {
+			if value != noErrors {
+				status.Errors[key] = value
+			}
+			continue
+		}
+		values[key] = value
+	}
+
+	status.Machine = values["machine"]
+	if status.Machine == "" {
+		return nil, fmt.Errorf("tunnel status contains no machine: %s", text)
+	}
+	status.MachineState = values[status.Machine]
+	status.Route = values["route"]
+	if _, gateway, found := strings.Cut(status.Route, "->"); found {
+		status.MinikubeIP = strings.TrimSpace(gateway)
+	}
+	if pid, e := strconv.Atoi(values["pid"]); e == nil {
+		status.Pid = pid
+	}
+	for _, service := range strings.Split(strings.Trim(values["services"], "[]"), ",") {
+		if service = strings.TrimSpace(service); service != "" {
+			status.Services = append(status.Services, service)
+		}
+	}
+	return status, nil
+}
+
+// Healthy returns true if the machine is running and no component reported an error.
+func (s *TunnelStatus) Healthy() bool {
+	return s.MachineState == "Running" && len(s.Errors) == 0
+}
+
+// ErrorMessages returns all errors formatted as "<component>: <error>" sorted by the component.
+func (s *TunnelStatus) ErrorMessages() []string {
+	var messages []string
+	for component, e := range s.Errors {
+		messages = append(messages, component+": "+e)
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// Table formats the status as table for the dashboard.
+func (s *TunnelStatus) Table() string {
+	services := "-"
+	if len(s.Services) > 0 {
+		services = strings.Join(s.Services, ", ")
+	}
+	errors := []string{noErrors}
+	if len(s.Errors) > 0 {
+		errors = s.ErrorMessages()
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(buffer, 0, 0, 1, ' ', tabwriter.Debug)
+	_, _ = fmt.Fprintf(writer, "Machine\t %s (%s)\n", s.Machine, s.MachineState)
+	_, _ = fmt.Fprintf(writer, "Minikube IP\t %s\n", s.MinikubeIP)
+	_, _ = fmt.Fprintf(writer, "Route\t %s\n", s.Route)
+	_, _ = fmt.Fprintf(writer, "Services\t %s\n", services)
+	for i, e := range errors {
+		label := ""
+		if i == 0 {
+			label = "Errors"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t %s\n", label, e)
+	}
+	_ = writer.Flush()
+	return buffer.String()
+}
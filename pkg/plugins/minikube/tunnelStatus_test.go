@@ -0,0 +1,80 @@
+package minikube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTunnelStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    *TunnelStatus
+		wantErr bool
+	}{
+		{
+			"no errors",
+			"	machine: minikube\n	pid: 68980\n	route: 10.96.0.0/12 -> 192.168.64.13\n	minikube: Running\n	services: []\n    errors: \n		minikube: no errors\n		router: no errors\n		loadbalancer emulator: no errors\n",
+			&TunnelStatus{
+				Machine:      "minikube",
+				MachineState: "Running",
+				Pid:          68980,
+				Route:        "10.96.0.0/12 -> 192.168.64.13",
+				MinikubeIP:   "192.168.64.13",
+				Services:     []string{},
+				Errors:       map[string]string{},
+			},
+			false,
+		}, {
+			"conflicting route",
+			"	machine: app\n	pid: 123\n	route: 10.96.0.0/12 -> 192.168.64.14\n	app: Stopped\n	services: [nginx, keycloak]\n    errors: \n		minikube: no errors\n		router: conflicting route: 10.96.0.0/12 via 192.168.64.13\n		loadbalancer emulator: no errors\n",
+			&TunnelStatus{
+				Machine:      "app",
+				MachineState: "Stopped",
+				Pid:          123,
+				Route:        "10.96.0.0/12 -> 192.168.64.14",
+				MinikubeIP:   "192.168.64.14",
+				Services:     []string{"nginx", "keycloak"},
+				Errors:       map[string]string{"router": "conflicting route: 10.96.0.0/12 via 192.168.64.13"},
+			},
+			false,
+		},
+		{"no machine", "	pid: 123\n", nil, true},
+		{"invalid line", "	machine: minikube\n	something\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTunnelStatus(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTunnelStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTunnelStatus_Healthy(t *testing.T) {
+	assert.True(t, (&TunnelStatus{MachineState: "Running"}).Healthy())
+	assert.False(t, (&TunnelStatus{MachineState: "Stopped"}).Healthy())
+	assert.False(t, (&TunnelStatus{MachineState: "Running", Errors: map[string]string{"router": "conflicting route"}}).Healthy())
+}
+
+func TestTunnelStatus_Table(t *testing.T) {
+	status := &TunnelStatus{
+		Machine:      "minikube",
+		MachineState: "Running",
+		Route:        "10.96.0.0/12 -> 192.168.64.13",
+		MinikubeIP:   "192.168.64.13",
+		Services:     []string{"nginx", "keycloak"},
+		Errors:       map[string]string{"router": "conflicting route", "minikube": "stopped"},
+	}
+
+	assert.Equal(t, "Machine     | minikube (Running)\n"+
+		"Minikube IP | 192.168.64.13\n"+
+		"Route       | 10.96.0.0/12 -> 192.168.64.13\n"+
+		"Services    | nginx, keycloak\n"+
+		"Errors      | minikube: stopped\n"+
+		"            | router: conflicting route\n", status.Table())
+}
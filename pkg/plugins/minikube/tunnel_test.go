@@ -2,6 +2,7 @@ package minikube
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"reflect"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/cluster"
 	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
 	"github.com/qaware/minikube-support/pkg/sh"
 	"github.com/qaware/minikube-support/pkg/testutils"
@@ -86,19 +88,37 @@ func Test_tunnel_Start_noMinikube(t *testing.T) {
 	handler := fake.NewContextHandler(nil, nil)
 	handler.MiniKube = false
 	mkt := NewTunnel(handler)
-	var count = 0
+	var count int32 = 0
 	go func() {
 		for message := range monitoringChannel {
 			assert.Equal(t, wantBoxName, message.Box)
 			assert.NotEmpty(t, message.Message)
-			count++
+			atomic.AddInt32(&count, 1)
 		}
 	}()
 	gotBoxName, err := mkt.Start(monitoringChannel)
-	assert.Equal(t, "", gotBoxName)
+	assert.Equal(t, wantBoxName, gotBoxName)
 	assert.NoError(t, err)
 	time.Sleep(100 * time.Millisecond)
-	assert.Equal(t, 1, count)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+}
+
+func Test_tunnel_Start_noLoadBalancer(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+
+	monitoringChannel := make(chan *apis.MonitoringMessage, 2)
+	handler := fake.NewContextHandler(nil, nil)
+	handler.Provider = cluster.NewKind("dev")
+	mkt := NewTunnel(handler)
+
+	gotBoxName, err := mkt.Start(monitoringChannel)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "minikube-tunnel", gotBoxName)
+	<-monitoringChannel
+	assert.Contains(t, (<-monitoringChannel).Message, "kind cluster dev does not support LoadBalancer services")
+	assert.NoError(t, mkt.Stop())
 }
 
 func Test_tunnel_Stop(t *testing.T) {
@@ -119,6 +139,54 @@ func Test_tunnel_Stop(t *testing.T) {
 	}
 }
 
+func Test_tunnel_Stop_noMinikube(t *testing.T) {
+	monitoringChannel := make(chan *apis.MonitoringMessage, 4)
+	handler := fake.NewContextHandler(nil, nil)
+	handler.MiniKube = false
+	mkt := NewTunnel(handler)
+
+	_, _ = mkt.Start(monitoringChannel)
+
+	assert.NoError(t, mkt.Stop())
+}
+
+func Test_tunnel_Health(t *testing.T) {
+	mkt := NewTunnel(fake.NewContextHandler(nil, nil)).(*tunnel)
+	assert.NoError(t, mkt.Health(), "no status yet")
+
+	mkt.setStatus(&TunnelStatus{Machine: "minikube", MachineState: "Running"})
+	assert.NoError(t, mkt.Health())
+
+	mkt.setStatus(&TunnelStatus{Machine: "minikube", MachineState: "Stopped"})
+	assert.EqualError(t, mkt.Health(), "minikube machine minikube is Stopped")
+
+	mkt.setStatus(&TunnelStatus{Machine: "minikube", MachineState: "Running", Errors: map[string]string{"router": "conflicting route"}})
+	assert.EqualError(t, mkt.Health(), "minikube tunnel has errors: router: conflicting route")
+	assert.Equal(t, "Running", mkt.Status().MachineState)
+}
+
+func Test_tunnel_scanForStatusMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		wantTop string
+	}{
+		{"healthy", "Running", "Machine"},
+		{"stopped", "Stopped", "Unhealthy: minikube machine minikube is Stopped\n\nMachine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mkt := NewTunnel(fake.NewContextHandler(nil, nil)).(*tunnel)
+			output := "Status:\n\tmachine: minikube\n\tpid: 1\n\troute: 10.96.0.0/12 -> 192.168.64.13\n\tminikube: " + tt.state + "\n\tservices: []\n"
+			messages := make(chan *apis.MonitoringMessage, 10)
+
+			mkt.scanForStatusMessages(initScanner(io.NopCloser(strings.NewReader(output))), messages, true)
+
+			assert.True(t, strings.HasPrefix((<-messages).Message, tt.wantTop))
+		})
+	}
+}
+
 func TestHelperProcess(*testing.T) {
 	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
 		return
@@ -131,6 +199,7 @@ func TestHelperProcess(*testing.T) {
 		cmd, args := args[0], args[1:]
 		switch cmd {
 		case "minikube":
+			args = skipProfile(args)
 			cmd, _ := args[0], args[1:]
 			switch cmd {
 			case "tunnel":
@@ -143,18 +212,36 @@ func TestHelperProcess(*testing.T) {
 			_, _ = fmt.Fprint(os.Stdout, strings.Join(args, " "))
 		}
 	case "minikube":
-		cmd, _ := args[0], args[1:]
-		switch cmd {
+		args = skipProfile(args)
+		switch args[0] {
 		case "ip":
 			_, _ = fmt.Fprintln(os.Stdout, "127.0.0.1")
+		case "mount":
+			if strings.HasSuffix(args[1], ":/fail") {
+				_, _ = fmt.Fprintln(os.Stderr, "❌  Exiting due to GUEST_MOUNT: mount failed: connection refused")
+				os.Exit(80)
+			}
+			bytes, _ := os.ReadFile("minikube-mount.txt")
+			_, _ = fmt.Fprint(os.Stdout, string(bytes))
+			time.Sleep(10 * time.Second)
 		}
 	case "which":
 		cmd, _ := args[0], args[1:]
 		switch cmd {
 		case "sudo":
 			_, _ = fmt.Fprintln(os.Stdout, "sudo")
+		default:
+			os.Exit(1)
 		}
 	default:
 		os.Exit(1)
 	}
 }
+
+// skipProfile removes the profile flag from the given minikube arguments.
+func skipProfile(args []string) []string {
+	if len(args) > 2 && args[0] == "-p" {
+		return args[2:]
+	}
+	return args
+}
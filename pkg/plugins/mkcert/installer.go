@@ -2,14 +2,19 @@ package mkcert
 
 import (
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/ca"
 	"github.com/qaware/minikube-support/pkg/packagemanager"
 	"github.com/qaware/minikube-support/pkg/sh"
 )
 
+// mkCertInstaller installs mkcert and uses it to add the minikube-support CA to the trust stores of the
+// local os and the browsers.
 type mkCertInstaller struct {
 }
 
@@ -37,25 +42,35 @@ func (i *mkCertInstaller) Install() {
 }
 
 func (i *mkCertInstaller) Update() {
-	command := sh.ExecCommand("mkcert", "-install")
-	command.Env = append(command.Env, os.Environ()...)
-	output, e := command.CombinedOutput()
+	if e := ensureCA(); e != nil {
+		logrus.Errorf("Can not create the Root CA: %s", e)
+		return
+	}
+	dir, e := ca.DefaultDir()
+	if e != nil {
+		logrus.Errorf("Can not install / update the current Root CA: %s", e)
+		return
+	}
+	output, e := InstallTrust(dir)
 	if e != nil {
-		logrus.Errorf("Can not install / update the current Root CA. Error: %s\nOutput: %s", e, string(output))
+		logrus.Errorf("Can not install / update the current Root CA. Error: %s\nOutput: %s", e, output)
 		return
 	}
-	logrus.Infof("Root CA successfully installed in browsers.\n%s", string(output))
+	logrus.Infof("Root CA successfully installed in browsers.\n%s", output)
 }
 
 func (i *mkCertInstaller) Uninstall(purge bool) {
-	command := sh.ExecCommand("mkcert", "-uninstall")
-	command.Env = append(command.Env, os.Environ()...)
-	output, e := command.CombinedOutput()
+	dir, e := ca.DefaultDir()
+	if e != nil {
+		logrus.Errorf("Can not uninstall the current Root CA: %s", e)
+		return
+	}
+	output, e := UninstallTrust(dir)
 	if e != nil {
-		logrus.Errorf("Can not uninstall the current Root CA. Error: %s\nOutput: %s", e, string(output))
+		logrus.Errorf("Can not uninstall the current Root CA. Error: %s\nOutput: %s", e, output)
 		return
 	}
-	logrus.Infof("Root CA successfully removed from browsers.\n%s", string(output))
+	logrus.Infof("Root CA successfully removed from browsers.\n%s", output)
 
 	if purge && !packagemanager.SelfInstalledUsingPackageManager() {
 		manager := packagemanager.GetPackageManager()
@@ -75,3 +90,47 @@ func (i *mkCertInstaller) Uninstall(purge bool) {
 func (*mkCertInstaller) Phase() apis.Phase {
 	return apis.LOCAL_TOOLS_INSTALL
 }
+
+// InstallTrust installs the CA stored in the given directory into the trust stores of the local os and the
+// browsers. It returns the output of mkcert.
+func InstallTrust(dir string) (string, error) {
+	output, e := trustCommand(dir, "-install").CombinedOutput()
+	return string(output), e
+}
+
+// UninstallTrust removes the CA stored in the given directory from the trust stores of the local os and the
+// browsers. It returns the output of mkcert.
+func UninstallTrust(dir string) (string, error) {
+	output, e := trustCommand(dir, "-uninstall").CombinedOutput()
+	return string(output), e
+}
+
+// trustCommand runs mkcert with the given CA directory as CAROOT, so that mkcert installs or removes this CA
+// instead of its own one.
+func trustCommand(dir string, arg string) *exec.Cmd {
+	command := sh.ExecCommand("mkcert", arg)
+	command.Env = append(command.Env, os.Environ()...)
+	command.Env = append(command.Env, "CAROOT="+dir)
+	return command
+}
+
+// ensureCA creates the minikube-support CA if it does not exist. An existing CA of mkcert is imported,
+// so that certificates issued before stay trusted.
+func ensureCA() error {
+	dir, e := ca.DefaultDir()
+	if e != nil {
+		return e
+	}
+	if ca.Exists(dir) {
+		return nil
+	}
+	if mkcertRoot, e := sh.RunCmd("mkcert", "-CAROOT"); e == nil {
+		mkcertRoot = strings.Trim(mkcertRoot, "\r\n \t")
+		if authority, e := ca.Load(mkcertRoot); e == nil {
+			logrus.Infof("Importing the mkcert CA from %s", mkcertRoot)
+			return authority.Save(dir)
+		}
+	}
+	_, e = ca.LoadOrCreate(dir)
+	return e
+}
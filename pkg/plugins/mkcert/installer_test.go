@@ -1,12 +1,17 @@
 package mkcert
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/qaware/minikube-support/pkg/ca"
 	"github.com/qaware/minikube-support/pkg/packagemanager"
 	"github.com/qaware/minikube-support/pkg/packagemanager/fake"
 	"github.com/qaware/minikube-support/pkg/sh"
@@ -14,6 +19,7 @@ import (
 )
 
 func Test_mkCertInstaller_Install(t *testing.T) {
+	t.Setenv(ca.DirEnv, t.TempDir())
 	sh.ExecCommand = testutils.FakeExecCommand
 	defer func() { sh.ExecCommand = exec.Command }()
 	tests := []struct {
@@ -54,6 +60,7 @@ func Test_mkCertInstaller_Install(t *testing.T) {
 }
 
 func Test_mkCertInstaller_Update(t *testing.T) {
+	t.Setenv(ca.DirEnv, t.TempDir())
 	sh.ExecCommand = testutils.FakeExecCommand
 	defer func() { sh.ExecCommand = exec.Command }()
 	ctrl := gomock.NewController(t)
@@ -67,6 +74,7 @@ func Test_mkCertInstaller_Update(t *testing.T) {
 }
 
 func Test_mkCertInstaller_Uninstall(t *testing.T) {
+	t.Setenv(ca.DirEnv, t.TempDir())
 	sh.ExecCommand = testutils.FakeExecCommand
 	defer func() { sh.ExecCommand = exec.Command }()
 	tests := []struct {
@@ -99,6 +107,35 @@ func Test_mkCertInstaller_Uninstall(t *testing.T) {
 	}
 }
 
+func Test_ensureCA(t *testing.T) {
+	sh.ExecCommand = testutils.FakeExecCommand
+	defer func() { sh.ExecCommand = exec.Command }()
+
+	mkcertRoot := t.TempDir()
+	existing, _ := ca.Generate()
+	_ = existing.Save(mkcertRoot)
+	tests := []struct {
+		name       string
+		mkcertRoot string
+		wantImport bool
+	}{
+		{"import mkcert CA", mkcertRoot, true},
+		{"no mkcert CA", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "ca")
+			t.Setenv(ca.DirEnv, dir)
+			t.Setenv("TEST_MKCERT_CAROOT", tt.mkcertRoot)
+
+			assert.NoError(t, ensureCA())
+			authority, e := ca.Load(dir)
+			assert.NoError(t, e)
+			assert.Equal(t, tt.wantImport, bytes.Equal(existing.Certificate.Raw, authority.Certificate.Raw))
+		})
+	}
+}
+
 func TestHelperProcess(*testing.T) {
 	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
 		return
@@ -110,6 +147,12 @@ func TestHelperProcess(*testing.T) {
 	case "mkcert":
 		cmd, _ := args[0], args[1:]
 		switch cmd {
+		case "-CAROOT":
+			root := os.Getenv("TEST_MKCERT_CAROOT")
+			if root == "" {
+				os.Exit(1)
+			}
+			_, _ = fmt.Fprintln(os.Stdout, root)
 		case "-install":
 		case "-uninstall":
 			os.Exit(0)
@@ -0,0 +1,139 @@
+package plugins
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+)
+
+// ContextPluginFactory creates a new instance of a plugin for the given kube context.
+type ContextPluginFactory func(context kubernetes.KubeContext) apis.StartStopPlugin
+
+// MultiContextPlugin is a plugin that runs one instance of a plugin per configured
+// kube context. If no contexts are configured it just runs the default plugin.
+type MultiContextPlugin struct {
+	defaultPlugin apis.StartStopPlugin
+	contexts      kubernetes.KubeContextSupplier
+	factory       ContextPluginFactory
+	running       []apis.StartStopPlugin
+	// defaultStarted is set if the default plugin was started instead of the plugins per context.
+	defaultStarted bool
+	done           chan struct{}
+	mutex          sync.Mutex
+}
+
+// NewMultiContextPlugin creates a new plugin that starts the default plugin or one plugin per context that
+// was created by the factory. The contexts are evaluated on start.
+func NewMultiContextPlugin(defaultPlugin apis.StartStopPlugin, contexts kubernetes.KubeContextSupplier, factory ContextPluginFactory) *MultiContextPlugin {
+	return &MultiContextPlugin{
+		defaultPlugin: defaultPlugin,
+		contexts:      contexts,
+		factory:       factory,
+		mutex:         sync.Mutex{},
+	}
+}
+
+// String returns the name of the default plugin.
+func (p *MultiContextPlugin) String() string {
+	return p.defaultPlugin.String()
+}
+
+func (p *MultiContextPlugin) IsSingleRunnable() bool {
+	return p.defaultPlugin.IsSingleRunnable()
+}
+
+// Start starts one plugin per context. Their messages are tagged with the context name.
+// If a plugin can not be started, the already started ones are stopped again.
+func (p *MultiContextPlugin) Start(messageChannel chan *apis.MonitoringMessage) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	contexts := p.contexts()
+	if len(contexts) == 0 {
+		box, e := p.defaultPlugin.Start(messageChannel)
+		p.defaultStarted = e == nil
+		return box, e
+	}
+
+	var errors *multierror.Error
+	p.done = make(chan struct{})
+	for _, context := range contexts {
+		plugin := p.factory(context)
+		contextChannel := make(chan *apis.MonitoringMessage)
+		go forwardMessages(contextChannel, messageChannel, context.Name, p.done)
+		if _, e := plugin.Start(contextChannel); e != nil {
+			errors = multierror.Append(errors, fmt.Errorf("can not start %s for context %s: %s", plugin, context.Name, e))
+			continue
+		}
+		p.running = append(p.running, plugin)
+	}
+	if errors != nil {
+		errors = multierror.Append(errors, p.stopRunning())
+	}
+	return p.String(), errors.ErrorOrNil()
+}
+
+// Stop stops all running plugins. It does nothing if no plugin is running.
+func (p *MultiContextPlugin) Stop() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.defaultStarted {
+		p.defaultStarted = false
+		return p.defaultPlugin.Stop()
+	}
+	if len(p.running) == 0 {
+		return nil
+	}
+	return p.stopRunning()
+}
+
+// stopRunning stops all plugins per context and their message forwarding. The caller must hold the mutex.
+func (p *MultiContextPlugin) stopRunning() error {
+	var errors *multierror.Error
+	for _, plugin := range p.running {
+		logrus.Debugf("Terminating plugin: %s", plugin)
+		errors = multierror.Append(errors, plugin.Stop())
+	}
+	close(p.done)
+	p.running = nil
+	return errors.ErrorOrNil()
+}
+
+// Health returns the health of the default plugin or the combined health of all plugins per context.
+func (p *MultiContextPlugin) Health() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.defaultStarted {
+		return apis.CheckHealth(p.defaultPlugin)
+	}
+	var errors *multierror.Error
+	for _, plugin := range p.running {
+		errors = multierror.Append(errors, apis.CheckHealth(plugin))
+	}
+	return errors.ErrorOrNil()
+}
+
+// forwardMessages forwards all messages from the given source to the target channel and sets the context.
+func forwardMessages(source chan *apis.MonitoringMessage, target chan *apis.MonitoringMessage, context string, done chan struct{}) {
+	for {
+		select {
+		case message := <-source:
+			forwarded := apis.CloneMonitoringMessage(message)
+			forwarded.Context = context
+			select {
+			case target <- &forwarded:
+			case <-done:
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package plugins
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+)
+
+func TestMultiContextPlugin_Start_default(t *testing.T) {
+	defaultPlugin := &DummyPlugin{}
+	p := NewMultiContextPlugin(defaultPlugin, func() []kubernetes.KubeContext { return nil }, func(kubernetes.KubeContext) apis.StartStopPlugin {
+		assert.Fail(t, "factory must not be called")
+		return nil
+	})
+
+	box, err := p.Start(make(chan *apis.MonitoringMessage, 1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultPlugin.String(), box)
+	assert.NoError(t, p.Stop())
+}
+
+func TestMultiContextPlugin_Start_contexts(t *testing.T) {
+	contexts := []kubernetes.KubeContext{{Name: "app", Zone: "app.minikube"}, {Name: "infra", Zone: "infra.minikube"}}
+	var created []string
+	p := NewMultiContextPlugin(&DummyPlugin{}, func() []kubernetes.KubeContext { return contexts }, func(context kubernetes.KubeContext) apis.StartStopPlugin {
+		created = append(created, context.Name)
+		return &messagePlugin{}
+	})
+	messageChannel := make(chan *apis.MonitoringMessage, 2)
+
+	_, err := p.Start(messageChannel)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "infra"}, created)
+	assert.Equal(t, &apis.MonitoringMessage{Box: "message", Message: "started", Context: "app"}, <-messageChannel)
+	assert.Equal(t, &apis.MonitoringMessage{Box: "message", Message: "started", Context: "infra"}, <-messageChannel)
+	assert.NoError(t, p.Stop())
+	assert.Empty(t, p.running)
+}
+
+func TestMultiContextPlugin_Start_fails(t *testing.T) {
+	p := NewMultiContextPlugin(&DummyPlugin{}, func() []kubernetes.KubeContext { return []kubernetes.KubeContext{{Name: "app"}} }, func(kubernetes.KubeContext) apis.StartStopPlugin {
+		return &failingStartStopPlugin{}
+	})
+
+	_, err := p.Start(make(chan *apis.MonitoringMessage))
+
+	assert.Error(t, err)
+}
+
+func TestMultiContextPlugin_Start_stopsStartedOnFailure(t *testing.T) {
+	started := &messagePlugin{}
+	defaultPlugin := &messagePlugin{health: errors.New("not started")}
+	p := NewMultiContextPlugin(defaultPlugin, func() []kubernetes.KubeContext {
+		return []kubernetes.KubeContext{{Name: "app"}, {Name: "infra"}}
+	}, func(context kubernetes.KubeContext) apis.StartStopPlugin {
+		if context.Name == "app" {
+			return started
+		}
+		return &failingStartStopPlugin{}
+	})
+
+	_, err := p.Start(make(chan *apis.MonitoringMessage, 1))
+
+	assert.Error(t, err)
+	assert.True(t, started.stopped)
+	assert.Empty(t, p.running)
+	assert.NoError(t, p.Health())
+	assert.NoError(t, p.Stop())
+	assert.False(t, defaultPlugin.stopped)
+}
+
+func TestMultiContextPlugin_Health(t *testing.T) {
+	contexts := []kubernetes.KubeContext{{Name: "app"}, {Name: "infra"}}
+	p := NewMultiContextPlugin(&DummyPlugin{}, func() []kubernetes.KubeContext { return contexts }, func(context kubernetes.KubeContext) apis.StartStopPlugin {
+		return &messagePlugin{health: errors.New(context.Name + " is unhealthy")}
+	})
+	assert.NoError(t, p.Health())
+
+	_, _ = p.Start(make(chan *apis.MonitoringMessage, 2))
+	err := p.Health()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "app is unhealthy")
+	assert.Contains(t, err.Error(), "infra is unhealthy")
+	assert.NoError(t, p.Stop())
+}
+
+type messagePlugin struct {
+	health  error
+	stopped bool
+}
+
+func (m *messagePlugin) Health() error {
+	return m.health
+}
+
+func (m *messagePlugin) String() string {
+	return "message"
+}
+
+func (m *messagePlugin) Start(messageChannel chan *apis.MonitoringMessage) (string, error) {
+	messageChannel <- &apis.MonitoringMessage{Box: "message", Message: "started"}
+	return "message", nil
+}
+
+func (m *messagePlugin) Stop() error {
+	m.stopped = true
+	return nil
+}
+
+func (m *messagePlugin) IsSingleRunnable() bool {
+	return false
+}
@@ -0,0 +1,101 @@
+package portforward
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Forward is the definition of a single port forward.
+type Forward struct {
+	// Namespace is the namespace of the service or pods.
+	Namespace string
+	// Service is the name of the service whose pods should be forwarded. Either Service or Selector is set.
+	Service string
+	// Selector is the label selector of the pods to forward. Either Service or Selector is set.
+	Selector string
+	// Ports are the forwarded ports in the format of kubectl: "[local:]remote".
+	// For services the remote port is a service port.
+	Ports []string
+}
+
+// ParseForward parses a forward definition of the format
+// "<namespace>/svc/<service> <ports...>" or "<namespace>/pod/<selector> <ports...>".
+// Every port is defined as "[local:]remote" like "15432:5432" or "6379".
+func ParseForward(definition string) (Forward, error) {
+	fields := strings.Fields(definition)
+	if len(fields) < 2 {
+		return Forward{}, fmt.Errorf("invalid port forward %q: expected <namespace>/<svc|pod>/<name|selector> <ports...>", definition)
+	}
+
+	parts := strings.SplitN(fields[0], "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return Forward{}, fmt.Errorf("invalid port forward target %q: expected <namespace>/<svc|pod>/<name|selector>", fields[0])
+	}
+	forward := Forward{Namespace: parts[0], Ports: fields[1:]}
+	switch parts[1] {
+	case "svc", "service":
+		forward.Service = parts[2]
+	case "pod", "pods":
+		if _, e := labels.Parse(parts[2]); e != nil {
+			return Forward{}, fmt.Errorf("invalid pod selector %q: %s", parts[2], e)
+		}
+		forward.Selector = parts[2]
+	default:
+		return Forward{}, fmt.Errorf("invalid port forward type %q: expected svc or pod", parts[1])
+	}
+
+	for _, port := range forward.Ports {
+		if _, _, e := SplitPort(port); e != nil {
+			return Forward{}, e
+		}
+	}
+	return forward, nil
+}
+
+// ParseForwards parses all given forward definitions.
+func ParseForwards(definitions []string) ([]Forward, error) {
+	var forwards []Forward
+	for _, definition := range definitions {
+		forward, e := ParseForward(definition)
+		if e != nil {
+			return nil, e
+		}
+		forwards = append(forwards, forward)
+	}
+	return forwards, nil
+}
+
+// SplitPort splits the port definition "[local:]remote" into the local and remote port.
+func SplitPort(port string) (local int, remote int, err error) {
+	localPart, remotePart, found := strings.Cut(port, ":")
+	if !found {
+		remotePart = localPart
+	}
+	if local, err = strconv.Atoi(localPart); err != nil || local < 0 || local > 65535 {
+		return 0, 0, fmt.Errorf("invalid local port in %q", port)
+	}
+	if remote, err = strconv.Atoi(remotePart); err != nil || remote <= 0 || remote > 65535 {
+		return 0, 0, fmt.Errorf("invalid remote port in %q", port)
+	}
+	return local, remote, nil
+}
+
+// String returns the target of the forward like "db/svc/postgres".
+func (f Forward) String() string {
+	if f.Service != "" {
+		return f.Namespace + "/svc/" + f.Service
+	}
+	return f.Namespace + "/pod/" + f.Selector
+}
+
+// HostName returns the dns name of a service forward like "postgres.db.pf.minikube".
+// Pod forwards have no host name.
+func (f Forward) HostName() string {
+	if f.Service == "" {
+		return ""
+	}
+	return f.Service + "." + f.Namespace + ".pf.minikube"
+}
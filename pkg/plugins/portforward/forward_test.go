@@ -0,0 +1,51 @@
+package portforward
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseForward(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       Forward
+		wantHost   string
+		wantErr    bool
+	}{
+		{"service", "db/svc/postgres 15432:5432", Forward{Namespace: "db", Service: "postgres", Ports: []string{"15432:5432"}}, "postgres.db.pf.minikube", false},
+		{"service long", "db/service/redis 6379 8080:80", Forward{Namespace: "db", Service: "redis", Ports: []string{"6379", "8080:80"}}, "redis.db.pf.minikube", false},
+		{"pod", "default/pod/app=web,tier=frontend 8080", Forward{Namespace: "default", Selector: "app=web,tier=frontend", Ports: []string{"8080"}}, "", false},
+		{"random local port", "db/svc/postgres :5432", Forward{}, "", true},
+		{"zero local port", "db/svc/postgres 0:5432", Forward{Namespace: "db", Service: "postgres", Ports: []string{"0:5432"}}, "postgres.db.pf.minikube", false},
+		{"no ports", "db/svc/postgres", Forward{}, "", true},
+		{"no namespace", "/svc/postgres 5432", Forward{}, "", true},
+		{"missing type", "db/postgres 5432", Forward{}, "", true},
+		{"unknown type", "db/deployment/postgres 5432", Forward{}, "", true},
+		{"invalid selector", "db/pod/app==(x 5432", Forward{}, "", true},
+		{"invalid port", "db/svc/postgres abc", Forward{}, "", true},
+		{"port out of range", "db/svc/postgres 70000", Forward{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := ParseForward(tt.definition)
+			if tt.wantErr {
+				assert.Error(t, e)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantHost, got.HostName())
+		})
+	}
+}
+
+func TestParseForwards(t *testing.T) {
+	forwards, e := ParseForwards([]string{"db/svc/postgres 5432", "default/pod/app=web 8080"})
+	assert.NoError(t, e)
+	assert.Equal(t, []string{"db/svc/postgres", "default/pod/app=web"}, []string{forwards[0].String(), forwards[1].String()})
+
+	_, e = ParseForwards([]string{"db/svc/postgres 5432", "invalid"})
+	assert.Error(t, e)
+}
@@ -0,0 +1,377 @@
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/plugins/coredns"
+	"github.com/qaware/minikube-support/pkg/utils"
+)
+
+const pluginName = "port-forward"
+
+// retryDelay is the time to wait before a lost port forward is established again.
+var retryDelay = 5 * time.Second
+
+// podCheckInterval is the interval in which the forwarded pod is checked for restarts.
+var podCheckInterval = 5 * time.Second
+
+// forwarder is the part of portforward.PortForwarder used by the plugin.
+type forwarder interface {
+	ForwardPorts() error
+}
+
+// forwarderFactory creates a new forwarder for the given pod and ports ("local:remote").
+type forwarderFactory func(pod *v1.Pod, ports []string, stop <-chan struct{}, ready chan struct{}) (forwarder, error)
+
+// forwardState is the current state of a single port forward.
+type forwardState struct {
+	forward Forward
+	pod     string
+	state   string
+}
+
+// portForward is a plugin that runs port forwards to services and pods in process. Every forward is
+// established again if the connection was lost or the pod was restarted.
+type portForward struct {
+	contextHandler    kubernetes.ContextHandler
+	dnsBackendManager coredns.Manager
+	definitions       *[]string
+	registerDns       *bool
+	newForwarder      forwarderFactory
+	messageChannel    chan *apis.MonitoringMessage
+	states            []*forwardState
+	stop              chan struct{}
+	running           sync.WaitGroup
+	mutex             sync.Mutex
+}
+
+// NewPortForwardPlugin initializes the plugin for the forwards defined by the given flag values.
+// If registerDns is set, every service forward gets the dns record "<svc>.<ns>.pf.minikube" pointing to 127.0.0.1.
+func NewPortForwardPlugin(handler kubernetes.ContextHandler, manager coredns.Manager, definitions *[]string, registerDns *bool) apis.StartStopPlugin {
+	p := &portForward{
+		contextHandler:    handler,
+		dnsBackendManager: manager,
+		definitions:       definitions,
+		registerDns:       registerDns,
+		mutex:             sync.Mutex{},
+	}
+	p.newForwarder = p.newPortForwarder
+	return p
+}
+
+func (p *portForward) String() string {
+	return pluginName
+}
+
+func (p *portForward) IsSingleRunnable() bool {
+	return true
+}
+
+// Start parses the forward definitions and starts one supervised port forward per definition.
+func (p *portForward) Start(messageChannel chan *apis.MonitoringMessage) (string, error) {
+	forwards, e := ParseForwards(*p.definitions)
+	if e != nil {
+		return "", e
+	}
+
+	p.mutex.Lock()
+	p.messageChannel = messageChannel
+	p.stop = make(chan struct{})
+	p.states = nil
+	for _, forward := range forwards {
+		state := &forwardState{forward: forward, state: "starting"}
+		p.states = append(p.states, state)
+		if *p.registerDns && forward.HostName() != "" {
+			if e := p.dnsBackendManager.AddHost(forward.HostName(), "127.0.0.1"); e != nil {
+				logrus.Warnf("Unable to add record for %s: %s", forward.HostName(), e)
+			}
+		}
+		p.running.Add(1)
+		go p.run(state, p.stop)
+	}
+	p.mutex.Unlock()
+
+	if len(forwards) == 0 {
+		messageChannel <- &apis.MonitoringMessage{Box: pluginName, Message: "No port forwards configured. Use --port-forward to define them."}
+	} else {
+		p.sendStates()
+	}
+	return pluginName, nil
+}
+
+// Stop stops all port forwards and removes their dns records.
+func (p *portForward) Stop() error {
+	p.mutex.Lock()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	p.mutex.Unlock()
+	p.running.Wait()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, state := range p.states {
+		if *p.registerDns && state.forward.HostName() != "" {
+			p.dnsBackendManager.RemoveHost(state.forward.HostName())
+		}
+	}
+	p.states = nil
+	return nil
+}
+
+// run keeps the forward running until the stop channel is closed.
+func (p *portForward) run(state *forwardState, stop chan struct{}) {
+	defer p.running.Done()
+	for {
+		e := p.forward(state, stop)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		logrus.Debugf("Port forward %s lost: %s", state.forward, e)
+		p.setState(state, "", fmt.Sprintf("reconnecting: %s", e))
+		select {
+		case <-stop:
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// forward establishes the port forward to a matching pod and blocks until the forward ends.
+// It ends if the stop channel is closed, the connection was lost or the pod is no longer running.
+func (p *portForward) forward(state *forwardState, stop chan struct{}) error {
+	clientSet, e := p.contextHandler.GetClientSet()
+	if e != nil {
+		return e
+	}
+	pod, ports, e := resolve(clientSet, state.forward)
+	if e != nil {
+		return e
+	}
+
+	forwardStop := make(chan struct{})
+	ready := make(chan struct{})
+	fw, e := p.newForwarder(pod, ports, forwardStop, ready)
+	if e != nil {
+		return e
+	}
+	done := make(chan error, 1)
+	go func() { done <- fw.ForwardPorts() }()
+
+	ticker := time.NewTicker(podCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ready:
+			p.setState(state, pod.Name, "forwarding "+strings.Join(ports, ", "))
+			ready = nil
+		case e := <-done:
+			if e == nil {
+				e = errors.New("port forward closed")
+			}
+			return e
+		case <-stop:
+			close(forwardStop)
+			<-done
+			return nil
+		case <-ticker.C:
+			if e := checkPod(clientSet, pod); e != nil {
+				close(forwardStop)
+				<-done
+				return e
+			}
+		}
+	}
+}
+
+// resolve finds a running pod for the given forward and translates the service ports into pod ports.
+func resolve(clientSet k8s.Interface, forward Forward) (*v1.Pod, []string, error) {
+	selector := forward.Selector
+	var service *v1.Service
+	if forward.Service != "" {
+		var e error
+		service, e = clientSet.CoreV1().Services(forward.Namespace).Get(context.Background(), forward.Service, metav1.GetOptions{})
+		if e != nil {
+			return nil, nil, fmt.Errorf("can not get service %s: %s", forward, e)
+		}
+		if len(service.Spec.Selector) == 0 {
+			return nil, nil, fmt.Errorf("service %s has no selector", forward)
+		}
+		selector = labels.SelectorFromSet(service.Spec.Selector).String()
+	}
+
+	pods, e := clientSet.CoreV1().Pods(forward.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if e != nil {
+		return nil, nil, fmt.Errorf("can not list pods for %s: %s", forward, e)
+	}
+	pod := selectPod(pods.Items)
+	if pod == nil {
+		return nil, nil, fmt.Errorf("no running pod found for %s", forward)
+	}
+
+	var ports []string
+	for _, port := range forward.Ports {
+		local, remote, e := SplitPort(port)
+		if e != nil {
+			return nil, nil, e
+		}
+		if service != nil {
+			if remote, e = targetPort(service, pod, remote); e != nil {
+				return nil, nil, e
+			}
+		}
+		ports = append(ports, strconv.Itoa(local)+":"+strconv.Itoa(remote))
+	}
+	return pod, ports, nil
+}
+
+// selectPod returns a running pod that is not terminating. Ready pods are preferred.
+func selectPod(pods []v1.Pod) *v1.Pod {
+	var result *v1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if isReady(pod) {
+			return pod
+		}
+		if result == nil {
+			result = pod
+		}
+	}
+	return result
+}
+
+func isReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// targetPort translates the given service port into the port of the pod.
+func targetPort(service *v1.Service, pod *v1.Pod, port int) (int, error) {
+	for _, servicePort := range service.Spec.Ports {
+		if int(servicePort.Port) != port {
+			continue
+		}
+		if servicePort.TargetPort.IntValue() > 0 {
+			return servicePort.TargetPort.IntValue(), nil
+		}
+		if servicePort.TargetPort.StrVal == "" {
+			return port, nil
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == servicePort.TargetPort.StrVal {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, servicePort.TargetPort.StrVal)
+	}
+	return 0, fmt.Errorf("service %s/%s has no port %d", service.Namespace, service.Name, port)
+}
+
+// checkPod returns an error if the given pod was deleted, replaced or is no longer running.
+func checkPod(clientSet k8s.Interface, pod *v1.Pod) error {
+	current, e := clientSet.CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+	if e != nil {
+		return fmt.Errorf("can not get pod %s: %s", pod.Name, e)
+	}
+	if current.UID != pod.UID || current.Status.Phase != v1.PodRunning || current.DeletionTimestamp != nil {
+		return fmt.Errorf("pod %s is no longer running", pod.Name)
+	}
+	return nil
+}
+
+// newPortForwarder creates a client-go port forwarder using the rest config of the context handler.
+func (p *portForward) newPortForwarder(pod *v1.Pod, ports []string, stop <-chan struct{}, ready chan struct{}) (forwarder, error) {
+	config, e := p.contextHandler.GetRestConfig()
+	if e != nil {
+		return nil, e
+	}
+	clientSet, e := p.contextHandler.GetClientSet()
+	if e != nil {
+		return nil, e
+	}
+	transport, upgrader, e := spdy.RoundTripperFor(config)
+	if e != nil {
+		return nil, fmt.Errorf("can not create port forward transport: %s", e)
+	}
+	url := clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+	return portforward.New(dialer, ports, stop, ready, io.Discard, io.Discard)
+}
+
+// setState updates the state of the forward and sends the new states to the box.
+func (p *portForward) setState(state *forwardState, pod string, message string) {
+	p.mutex.Lock()
+	state.pod = pod
+	state.state = message
+	p.mutex.Unlock()
+	p.sendStates()
+}
+
+// sendStates sends the states of all forwards to the box. Nothing is sent once the plugin was stopped.
+func (p *portForward) sendStates() {
+	p.mutex.Lock()
+	var lines []string
+	for _, state := range p.states {
+		pod := state.pod
+		if pod == "" {
+			pod = "-"
+		}
+		host := state.forward.HostName()
+		if host == "" || !*p.registerDns {
+			host = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%s\t %s\t %s\t %s\n", state.forward, host, pod, state.state))
+	}
+	messageChannel, stop := p.messageChannel, p.stop
+	p.mutex.Unlock()
+	if stop == nil {
+		return
+	}
+
+	sort.Strings(lines)
+	table, e := utils.FormatAsTable(lines, "Forward\t Hostname\t Pod\t State\n")
+	if e != nil {
+		table = e.Error()
+	}
+	select {
+	case messageChannel <- &apis.MonitoringMessage{Box: pluginName, Message: table}:
+	case <-stop:
+	}
+}
@@ -0,0 +1,231 @@
+package portforward
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+)
+
+func Test_resolve(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "db"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "postgres"},
+			Ports: []v1.ServicePort{
+				{Port: 5432, TargetPort: intstr.FromString("pg")},
+				{Port: 9187, TargetPort: intstr.FromInt32(9100)},
+				{Port: 8080},
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		forward   Forward
+		objects   []*v1.Pod
+		wantPod   string
+		wantPorts []string
+		wantErr   bool
+	}{
+		{"named target port", Forward{Namespace: "db", Service: "postgres", Ports: []string{"15432:5432"}}, []*v1.Pod{createPod("postgres-0", "db", "1", v1.PodRunning, true)}, "postgres-0", []string{"15432:5432"}, false},
+		{"numeric target port", Forward{Namespace: "db", Service: "postgres", Ports: []string{"9187"}}, []*v1.Pod{createPod("postgres-0", "db", "1", v1.PodRunning, true)}, "postgres-0", []string{"9187:9100"}, false},
+		{"no target port", Forward{Namespace: "db", Service: "postgres", Ports: []string{"8080"}}, []*v1.Pod{createPod("postgres-0", "db", "1", v1.PodRunning, true)}, "postgres-0", []string{"8080:8080"}, false},
+		{"unknown service port", Forward{Namespace: "db", Service: "postgres", Ports: []string{"1234"}}, []*v1.Pod{createPod("postgres-0", "db", "1", v1.PodRunning, true)}, "", nil, true},
+		{"prefer ready pod", Forward{Namespace: "db", Service: "postgres", Ports: []string{"5432"}}, []*v1.Pod{createPod("postgres-0", "db", "1", v1.PodRunning, false), createPod("postgres-1", "db", "2", v1.PodRunning, true)}, "postgres-1", []string{"5432:5432"}, false},
+		{"no running pod", Forward{Namespace: "db", Service: "postgres", Ports: []string{"5432"}}, []*v1.Pod{createPod("postgres-0", "db", "1", v1.PodPending, false)}, "", nil, true},
+		{"unknown service", Forward{Namespace: "db", Service: "mysql", Ports: []string{"3306"}}, nil, "", nil, true},
+		{"pod selector", Forward{Namespace: "db", Selector: "app=postgres", Ports: []string{"5432"}}, []*v1.Pod{createPod("postgres-0", "db", "1", v1.PodRunning, true)}, "postgres-0", []string{"5432:5432"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSet := k8sfake.NewClientset(service)
+			for _, pod := range tt.objects {
+				_, _ = clientSet.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+			}
+
+			pod, ports, e := resolve(clientSet, tt.forward)
+			if tt.wantErr {
+				assert.Error(t, e)
+				return
+			}
+			assert.NoError(t, e)
+			assert.Equal(t, tt.wantPod, pod.Name)
+			assert.Equal(t, tt.wantPorts, ports)
+		})
+	}
+}
+
+func Test_portForward_StartStop(t *testing.T) {
+	podCheckInterval = 10 * time.Millisecond
+	retryDelay = 10 * time.Millisecond
+	defer func() {
+		podCheckInterval = 5 * time.Second
+		retryDelay = 5 * time.Second
+	}()
+
+	clientSet := k8sfake.NewClientset(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "db"},
+		Spec:       v1.ServiceSpec{Selector: map[string]string{"app": "postgres"}, Ports: []v1.ServicePort{{Port: 5432}}},
+	}, createPod("postgres-0", "db", "1", v1.PodRunning, true))
+	manager := newTestManager(t)
+	definitions := []string{"db/svc/postgres 15432:5432"}
+	registerDns := true
+	p := NewPortForwardPlugin(fake.NewContextHandler(clientSet, nil), manager, &definitions, &registerDns).(*portForward)
+	forwarded := make(chan string, 10)
+	p.newForwarder = func(pod *v1.Pod, ports []string, stop <-chan struct{}, ready chan struct{}) (forwarder, error) {
+		forwarded <- pod.Name + "/" + string(pod.UID)
+		return &testForwarder{stop: stop, ready: ready}, nil
+	}
+
+	messages := make(chan *apis.MonitoringMessage, 100)
+	box, e := p.Start(messages)
+	assert.NoError(t, e)
+	assert.Equal(t, pluginName, box)
+	assert.Equal(t, []string{"postgres.db.pf.minikube"}, manager.addedHosts)
+	assert.Equal(t, "postgres-0/1", waitFor(t, forwarded))
+	waitForMessage(t, messages, "forwarding 15432:5432")
+
+	// the pod was restarted, so the forward has to be established again
+	_ = clientSet.CoreV1().Pods("db").Delete(context.Background(), "postgres-0", metav1.DeleteOptions{})
+	_, _ = clientSet.CoreV1().Pods("db").Create(context.Background(), createPod("postgres-0", "db", "2", v1.PodRunning, true), metav1.CreateOptions{})
+	waitForMessage(t, messages, "reconnecting: pod postgres-0 is no longer running")
+	assert.Equal(t, "postgres-0/2", waitFor(t, forwarded))
+	waitForMessage(t, messages, "forwarding 15432:5432")
+
+	assert.NoError(t, p.Stop())
+	assert.Equal(t, []string{"postgres.db.pf.minikube"}, manager.removedHosts)
+}
+
+func Test_portForward_Stop_unreadMessages(t *testing.T) {
+	clientSet := k8sfake.NewClientset(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "db"},
+		Spec:       v1.ServiceSpec{Selector: map[string]string{"app": "postgres"}, Ports: []v1.ServicePort{{Port: 5432}}},
+	}, createPod("postgres-0", "db", "1", v1.PodRunning, true))
+	definitions := []string{"db/svc/postgres 15432:5432"}
+	registerDns := false
+	p := NewPortForwardPlugin(fake.NewContextHandler(clientSet, nil), newTestManager(t), &definitions, &registerDns).(*portForward)
+	forwarded := make(chan string, 10)
+	p.newForwarder = func(pod *v1.Pod, ports []string, stop <-chan struct{}, ready chan struct{}) (forwarder, error) {
+		forwarded <- pod.Name
+		return &testForwarder{stop: stop, ready: ready}, nil
+	}
+
+	_, e := p.Start(make(chan *apis.MonitoringMessage, 1))
+	assert.NoError(t, e)
+	waitFor(t, forwarded)
+
+	stopped := make(chan error)
+	go func() { stopped <- p.Stop() }()
+	select {
+	case e := <-stopped:
+		assert.NoError(t, e)
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked by an unread message")
+	}
+}
+
+func Test_portForward_Start_noForwards(t *testing.T) {
+	registerDns := false
+	p := NewPortForwardPlugin(fake.NewContextHandler(nil, nil), newTestManager(t), &[]string{}, &registerDns)
+	messages := make(chan *apis.MonitoringMessage, 1)
+
+	_, e := p.Start(messages)
+	assert.NoError(t, e)
+	assert.Contains(t, (<-messages).Message, "No port forwards configured")
+	assert.NoError(t, p.Stop())
+}
+
+func Test_portForward_Start_invalidDefinition(t *testing.T) {
+	registerDns := false
+	p := NewPortForwardPlugin(fake.NewContextHandler(nil, nil), newTestManager(t), &[]string{"invalid"}, &registerDns)
+
+	_, e := p.Start(make(chan *apis.MonitoringMessage, 1))
+	assert.Error(t, e)
+}
+
+func waitFor(t *testing.T, ch chan string) string {
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for port forward")
+		return ""
+	}
+}
+
+func waitForMessage(t *testing.T, messages chan *apis.MonitoringMessage, content string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case message := <-messages:
+			if strings.Contains(message.Message, content) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for message %q", content)
+		}
+	}
+}
+
+func createPod(name string, namespace string, uid string, phase v1.PodPhase, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(uid), Labels: map[string]string{"app": "postgres"}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name:  "postgres",
+			Ports: []v1.ContainerPort{{Name: "pg", ContainerPort: 5432}},
+		}}},
+		Status: v1.PodStatus{Phase: phase, Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+	}
+}
+
+type testForwarder struct {
+	stop  <-chan struct{}
+	ready chan struct{}
+}
+
+func (f *testForwarder) ForwardPorts() error {
+	close(f.ready)
+	<-f.stop
+	return nil
+}
+
+type testManager struct {
+	t            *testing.T
+	addedHosts   []string
+	addedAlias   []string
+	removedHosts []string
+}
+
+func newTestManager(t *testing.T) *testManager {
+	return &testManager{t, make([]string, 0), make([]string, 0), make([]string, 0)}
+}
+
+func (m *testManager) AddHost(hostName string, ip string) error {
+	m.addedHosts = append(m.addedHosts, hostName)
+	assert.NotEmpty(m.t, ip)
+	return nil
+}
+
+func (m *testManager) AddAlias(hostName string, target string) error {
+	m.addedAlias = append(m.addedAlias, hostName)
+	assert.NotEmpty(m.t, target)
+	return nil
+}
+
+func (m *testManager) RemoveHost(hostName string) {
+	m.removedHosts = append(m.removedHosts, hostName)
+}
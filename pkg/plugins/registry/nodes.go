@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/plugins/ingress"
+	"github.com/qaware/minikube-support/pkg/sh"
+)
+
+const nodesPluginName = "registry-nodes"
+
+// nodeCheckInterval is the interval in which the configuration of the nodes is checked.
+var nodeCheckInterval = time.Minute
+
+// nodes is a plugin which restores the configuration of the minikube nodes for the registry. Minikube rewrites
+// the hosts file of a node when it restarts, so that the registry host can not be resolved anymore.
+type nodes struct {
+	registry *registry
+	stop     chan struct{}
+	running  sync.WaitGroup
+	mutex    sync.Mutex
+}
+
+// NewNodes creates the plugin which keeps the minikube nodes configured for the registry while it is installed.
+func NewNodes(handler kubernetes.ContextHandler, selector *ingress.Selector) apis.StartStopPlugin {
+	return &nodes{registry: NewRegistry(nil, handler, selector, nil).(*registry), mutex: sync.Mutex{}}
+}
+
+func (n *nodes) String() string {
+	return nodesPluginName
+}
+
+func (*nodes) IsSingleRunnable() bool {
+	return false
+}
+
+// Start checks the nodes now and after every check interval.
+func (n *nodes) Start(_ chan *apis.MonitoringMessage) (string, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.stop = make(chan struct{})
+	n.running.Add(1)
+	go n.poll(n.stop)
+	return nodesPluginName, nil
+}
+
+// Stop stops checking the nodes.
+func (n *nodes) Stop() error {
+	n.mutex.Lock()
+	if n.stop != nil {
+		close(n.stop)
+		n.stop = nil
+	}
+	n.mutex.Unlock()
+	n.running.Wait()
+	return nil
+}
+
+// poll restores the configuration of the nodes until the stop channel is closed.
+func (n *nodes) poll(stop chan struct{}) {
+	defer n.running.Done()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+			if e := n.restore(); e != nil {
+				logrus.Warnf("Can not restore the configuration of the cluster nodes for %s: %s", HostName, e)
+			}
+			timer.Reset(nodeCheckInterval)
+		}
+	}
+}
+
+// restore configures the nodes again if the registry is installed and a node can not resolve the registry host.
+func (n *nodes) restore() error {
+	profile, e := n.registry.contextHandler.GetMinikubeProfile()
+	if e != nil || profile == nil {
+		return e
+	}
+	clientSet, e := n.registry.contextHandler.GetClientSet()
+	if e != nil {
+		return fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	deployments, e := clientSet.AppsV1().Deployments(n.registry.namespace).List(n.registry.ctx, metav1.ListOptions{LabelSelector: "release=" + releaseName})
+	if e != nil {
+		return fmt.Errorf("can not list deployments: %s", e)
+	}
+	if len(deployments.Items) == 0 {
+		return nil
+	}
+
+	configured := true
+	for _, node := range profile.NodeNames() {
+		if _, e := sh.RunCmd("minikube", profile.Args("ssh", "-n", node, "--", fmt.Sprintf("grep -q ' %s$' /etc/hosts", HostName))...); e != nil {
+			configured = false
+		}
+	}
+	if configured {
+		return nil
+	}
+
+	controller, e := n.registry.ingress.Controller()
+	if e != nil {
+		return e
+	}
+	if e := n.registry.configureNodes(controller); e != nil {
+		return e
+	}
+	logrus.Infof("Restored the configuration of the cluster nodes for %s.", HostName)
+	return nil
+}
@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/plugins/ingress"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func Test_nodes_restore(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+
+	tests := []struct {
+		name         string
+		installed    bool
+		grepStatus   int
+		lastLogEntry string
+	}{
+		{"not installed", false, 1, ""},
+		{"configured", true, 0, ""},
+		{"restarted node", true, 1, "Restored the configuration of the cluster nodes for registry.minikube."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := test.NewGlobal()
+			caDir := t.TempDir()
+			t.Setenv(ca.DirEnv, caDir)
+			testutils.ClearTestProcessResponse()
+			testutils.MockWithoutResponse(tt.grepStatus, "minikube", "-p", "minikube", "ssh", "-n", "minikube", "--", "grep -q ' registry.minikube$' /etc/hosts")
+			for _, file := range caFiles {
+				testutils.MockWithoutResponse(0, "minikube", "-p", "minikube", "cp", filepath.Join(caDir, ca.CertFile), "minikube:"+file)
+			}
+			testutils.MockWithoutResponse(0, "minikube", "-p", "minikube", "ssh", "-n", "minikube", "--",
+				"sudo update-ca-certificates && (sudo sed -i '/ registry.minikube$/d' /etc/hosts; echo '10.96.10.10 registry.minikube' | sudo tee -a /etc/hosts)")
+
+			objects := []runtime.Object{controllerService("10.96.10.10")}
+			if tt.installed {
+				objects = append(objects, fake.AvailableDeployment("mks", "registry-docker-registry", map[string]string{"release": releaseName}))
+			}
+			handler := fake.NewContextHandler(k8sFake.NewClientset(objects...), nil)
+			handler.MiniKube = true
+
+			e := NewNodes(handler, ingress.NewSelector(nil, handler)).(*nodes).restore()
+
+			assert.NoError(t, e)
+			testutils.CheckLogEntry(t, hook, tt.lastLogEntry)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/minikube"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	"github.com/qaware/minikube-support/pkg/plugins/certmanager"
+	"github.com/qaware/minikube-support/pkg/plugins/ingress"
+	"github.com/qaware/minikube-support/pkg/sh"
+)
+
+const (
+	PluginName     = "registry"
+	HostName       = "registry.minikube"
+	releaseName    = "registry"
+	issuerName     = "ca-issuer"
+	tlsSecret      = "registry-tls"
+	repositoryName = "twuni"
+	repositoryURL  = "https://helm.twun.io"
+	chart          = repositoryName + "/docker-registry"
+)
+
+// waitTimeout is the time to wait for the ingress controller and cert-manager before the registry is installed.
+var waitTimeout = kubernetes.DefaultWaitTimeout
+
+// caFiles are the locations inside the node where the root certificate is stored so that
+// docker, containerd and the system trust store accept the certificate of the registry.
+var caFiles = []string{
+	"/etc/docker/certs.d/" + HostName + "/ca.crt",
+	"/etc/containerd/certs.d/" + HostName + "/ca.crt",
+	"/usr/local/share/ca-certificates/minikube-support.crt",
+}
+
+type registry struct {
+	manager        helm.Manager
+	contextHandler kubernetes.ContextHandler
+	ingress        *ingress.Selector
+	namespace      string
+	values         map[string]interface{}
+	overrides      *helm.Overrides
+	ctx            context.Context
+}
+
+// NewRegistry creates the plugin which installs a container registry into the cluster. It is reachable as
+// registry.minikube through the selected ingress controller using a certificate of the ca-issuer.
+func NewRegistry(manager helm.Manager, handler kubernetes.ContextHandler, selector *ingress.Selector, overrides *helm.Overrides) apis.InstallablePlugin {
+	return &registry{
+		manager:        manager,
+		contextHandler: handler,
+		ingress:        selector,
+		namespace:      "mks",
+		values:         map[string]interface{}{},
+		overrides:      overrides,
+		ctx:            context.Background(),
+	}
+}
+
+func (r *registry) String() string {
+	return PluginName
+}
+
+func (r *registry) Install() {
+	if e := r.manager.AddRepository(repositoryName, repositoryURL); e != nil {
+		logrus.Errorf("Unable to add twuni repository: %s", e)
+		return
+	}
+	r.Update()
+}
+
+func (r *registry) Update() {
+	if e := r.manager.UpdateRepository(); e != nil {
+		logrus.Errorf("Unable to update helm repositories %s", e)
+		return
+	}
+
+	controller, e := r.ingress.Controller()
+	if e != nil {
+		logrus.Errorf("Unable to select the ingress controller: %s", e)
+		return
+	}
+	values, e := r.Values()
+	if e != nil {
+		logrus.Errorf("Unable to determine the values of the registry: %s", e)
+		return
+	}
+
+	// the ingress of the registry is validated by the ingress controller and gets its certificate from cert-manager
+	if e := controller.Ready(r.contextHandler, waitTimeout); e != nil {
+		logrus.Errorf("Ingress controller is not ready: %s", e)
+		return
+	}
+	if e := certmanager.WaitUntilReady(r.contextHandler, r.namespace, waitTimeout); e != nil {
+		logrus.Errorf("Cert manager is not ready: %s", e)
+		return
+	}
+	if e := r.manager.Install(chart, "", releaseName, r.namespace, values, true); e != nil {
+		logrus.Errorf("Unable to install the registry: %s", e)
+		return
+	}
+
+	if e := r.configureNodes(controller); e != nil {
+		logrus.Errorf("Can not configure the cluster nodes to trust %s: %s", HostName, e)
+		return
+	}
+	logrus.Infof("Registry installed. Push images to %s/<image>.", HostName)
+}
+
+func (r *registry) Uninstall(_ bool) {
+	r.manager.Uninstall(releaseName, r.namespace, true)
+
+	if e := r.unconfigureNodes(); e != nil {
+		logrus.Errorf("Unable to remove the registry configuration from the cluster nodes: %s", e)
+		return
+	}
+	logrus.Info("Registry plugin successfully uninstalled.")
+}
+
+func (r *registry) Phase() apis.Phase {
+	return apis.CLUSTER_TOOLS_CONFIG
+}
+
+func (r *registry) Release() (string, string) {
+	return releaseName, r.namespace
+}
+
+// Ready checks if the registry is available.
+func (r *registry) Ready() error {
+	return kubernetes.NewWaiter(r.contextHandler, waitTimeout).Deployments(r.namespace, "release="+releaseName)
+}
+
+// Artifacts returns the docker-registry chart.
+func (r *registry) Artifacts() apis.Artifacts {
+	values, e := r.Values()
+	if e != nil {
+		logrus.Warnf("Unable to determine the values of the registry: %s", e)
+	}
+	return apis.Artifacts{Charts: []apis.ChartArtifact{{RepositoryName: repositoryName, RepositoryURL: repositoryURL, Chart: chart, Values: values}}}
+}
+
+// Values returns the default values merged with the values given by the user.
+func (r *registry) Values() (map[string]interface{}, error) {
+	controller, e := r.ingress.Controller()
+	if e != nil {
+		return nil, e
+	}
+	r.setValues(controller)
+	return r.overrides.Values(PluginName, r.values)
+}
+
+func (r *registry) setValues(controller ingress.Controller) {
+	r.values["ingress.enabled"] = "true"
+	r.values["ingress.className"] = controller.ClassName()
+	r.values["ingress.hosts"] = []string{HostName}
+	r.values["ingress.tls[0].secretName"] = tlsSecret
+	r.values["ingress.tls[0].hosts"] = []string{HostName}
+	r.values["ingress.annotations.cert-manager\\.io/cluster-issuer"] = issuerName
+	if controller.String() == ingress.KindNginx {
+		// image layers can be larger than the default body size of nginx
+		r.values["ingress.annotations.nginx\\.ingress\\.kubernetes\\.io/proxy-body-size"] = helm.StringValue("0")
+	}
+	r.values["persistence.enabled"] = "true"
+}
+
+// configureNodes copies the root certificate into every minikube node and resolves the registry host
+// to the ingress controller, so that the container runtime inside the node can push and pull images.
+func (r *registry) configureNodes(controller ingress.Controller) error {
+	profile, e := r.contextHandler.GetMinikubeProfile()
+	if e != nil {
+		return e
+	}
+	if profile == nil {
+		logrus.Warnf("Context is no minikube cluster. The container runtime of the nodes has to trust the CA for %s manually.", HostName)
+		return nil
+	}
+
+	caDir, e := ca.DefaultDir()
+	if e != nil {
+		return e
+	}
+	if _, e := ca.LoadOrCreate(caDir); e != nil {
+		return e
+	}
+	controllerIP, e := r.ingressControllerIP(controller)
+	if e != nil {
+		return e
+	}
+
+	var err *multierror.Error
+	for _, node := range profile.NodeNames() {
+		for _, file := range caFiles {
+			err = multierror.Append(err, runMinikube(profile, "cp", filepath.Join(caDir, ca.CertFile), node+":"+file))
+		}
+		err = multierror.Append(err, runMinikube(profile, "ssh", "-n", node, "--",
+			fmt.Sprintf("sudo update-ca-certificates && (sudo sed -i '/ %s$/d' /etc/hosts; echo '%s %s' | sudo tee -a /etc/hosts)", HostName, controllerIP, HostName)))
+	}
+	return err.ErrorOrNil()
+}
+
+// unconfigureNodes removes the files and the hosts entry added by configureNodes.
+func (r *registry) unconfigureNodes() error {
+	profile, e := r.contextHandler.GetMinikubeProfile()
+	if e != nil || profile == nil {
+		return e
+	}
+
+	var err *multierror.Error
+	for _, node := range profile.NodeNames() {
+		script := fmt.Sprintf("sudo sed -i '/ %s$/d' /etc/hosts", HostName)
+		for _, file := range caFiles {
+			script += " && sudo rm -f " + file
+		}
+		script += " && sudo update-ca-certificates --fresh"
+		err = multierror.Append(err, runMinikube(profile, "ssh", "-n", node, "--", script))
+	}
+	return err.ErrorOrNil()
+}
+
+// ingressControllerIP returns the cluster ip of the service of the ingress controller.
+func (r *registry) ingressControllerIP(controller ingress.Controller) (string, error) {
+	clientSet, e := r.contextHandler.GetClientSet()
+	if e != nil {
+		return "", fmt.Errorf("unable to get k8s client: %s", e)
+	}
+	service, e := clientSet.CoreV1().Services(ingress.Namespace).Get(r.ctx, controller.ServiceName(), metav1.GetOptions{})
+	if e != nil {
+		return "", fmt.Errorf("no ingress controller found in namespace %s: %s", ingress.Namespace, e)
+	}
+	if service.Spec.ClusterIP == "" || service.Spec.ClusterIP == "None" {
+		return "", fmt.Errorf("service %s/%s of the ingress controller has no cluster ip", ingress.Namespace, service.Name)
+	}
+	return service.Spec.ClusterIP, nil
+}
+
+func runMinikube(profile *minikube.Profile, args ...string) error {
+	output, e := sh.RunCmd("minikube", profile.Args(args...)...)
+	if e != nil {
+		return fmt.Errorf("minikube %s failed: %s\n%s", args[0], e, output)
+	}
+	return nil
+}
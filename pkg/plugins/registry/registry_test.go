@@ -0,0 +1,200 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+	"github.com/qaware/minikube-support/pkg/packagemanager/helm"
+	helmFake "github.com/qaware/minikube-support/pkg/packagemanager/helm/fake"
+	"github.com/qaware/minikube-support/pkg/plugins/ingress"
+	"github.com/qaware/minikube-support/pkg/testutils"
+)
+
+func Test_registry_Install(t *testing.T) {
+	hook := test.NewGlobal()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := fake.NewContextHandler(nil, nil)
+	helmManager := helmFake.NewMockManager(ctrl)
+	helmManager.EXPECT().AddRepository("twuni", "https://helm.twun.io").Return(errors.New("failed"))
+	NewRegistry(helmManager, handler, ingress.NewSelector(nil, handler), nil).Install()
+
+	testutils.CheckLogEntry(t, hook, "Unable to add twuni repository")
+}
+
+func Test_registry_Update(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	hook := test.NewGlobal()
+	logrus.SetLevel(logrus.DebugLevel)
+
+	waitTimeout = 50 * time.Millisecond
+	tests := []struct {
+		name         string
+		services     []*v1.Service
+		minikube     bool
+		ready        bool
+		lastLogEntry string
+	}{
+		{"ok", []*v1.Service{controllerService("10.96.10.10")}, true, true, "Registry installed. Push images to registry.minikube/<image>."},
+		{"no minikube", nil, false, true, "Registry installed."},
+		{"no ingress controller", nil, true, true, "Can not configure the cluster nodes to trust registry.minikube: no ingress controller found in namespace mks"},
+		{"not ready", nil, true, false, "Ingress controller is not ready: deployments app.kubernetes.io/instance=nginx-ingress in mks not ready within 50ms: no deployment found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			caDir := t.TempDir()
+			t.Setenv(ca.DirEnv, caDir)
+			testutils.ClearTestProcessResponse()
+			for _, file := range caFiles {
+				testutils.MockWithoutResponse(0, "minikube", "-p", "minikube", "cp", filepath.Join(caDir, ca.CertFile), "minikube:"+file)
+			}
+			testutils.MockWithoutResponse(0, "minikube", "-p", "minikube", "ssh", "-n", "minikube", "--",
+				"sudo update-ca-certificates && (sudo sed -i '/ registry.minikube$/d' /etc/hosts; echo '10.96.10.10 registry.minikube' | sudo tee -a /etc/hosts)")
+
+			var objects []runtime.Object
+			for _, service := range tt.services {
+				objects = append(objects, service)
+			}
+			var crds []runtime.Object
+			if tt.ready {
+				objects = append(objects,
+					fake.AvailableDeployment("mks", "nginx-ingress-controller", map[string]string{"app.kubernetes.io/instance": "nginx-ingress"}),
+					fake.ReadyEndpointSlice("mks", "nginx-ingress-ingress-nginx-controller-admission"),
+					fake.AvailableDeployment("mks", "cert-manager", map[string]string{"app.kubernetes.io/instance": "cert-manager"}),
+					fake.ReadyEndpointSlice("mks", "cert-manager-webhook"))
+				for _, crd := range []string{"certificates", "certificaterequests", "issuers", "clusterissuers"} {
+					crds = append(crds, fake.EstablishedCRD(crd+".cert-manager.io"))
+				}
+			}
+			handler := fake.NewContextHandler(k8sFake.NewClientset(objects...), fake.NewDynamicClient(nil, crds...))
+			handler.MiniKube = tt.minikube
+			helmManager := helmFake.NewMockManager(ctrl)
+			helmManager.EXPECT().UpdateRepository().Return(nil)
+			helmManager.EXPECT().Install("twuni/docker-registry", "", releaseName, "mks", gomock.Any(), true).MaxTimes(1).
+				Do(func(_ string, _ string, _ string, _ string, values map[string]interface{}, _ bool) {
+					ingressValues := values["ingress"].(map[string]interface{})
+					assert.Equal(t, []interface{}{HostName}, ingressValues["hosts"])
+					assert.Equal(t, issuerName, ingressValues["annotations"].(map[string]interface{})["cert-manager.io/cluster-issuer"])
+				})
+
+			NewRegistry(helmManager, handler, ingress.NewSelector(nil, handler), nil).Update()
+
+			testutils.CheckLogEntry(t, hook, tt.lastLogEntry)
+		})
+	}
+}
+
+func Test_registry_Update_failedRepoUpdate(t *testing.T) {
+	hook := test.NewGlobal()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := fake.NewContextHandler(nil, nil)
+	helmManager := helmFake.NewMockManager(ctrl)
+	helmManager.EXPECT().UpdateRepository().Return(errors.New("failed"))
+	NewRegistry(helmManager, handler, ingress.NewSelector(nil, handler), nil).Update()
+
+	testutils.CheckLogEntry(t, hook, "Unable to update helm repositories")
+}
+
+func Test_registry_Uninstall(t *testing.T) {
+	testutils.StartCommandLineTest()
+	defer testutils.StopCommandLineTest()
+	hook := test.NewGlobal()
+	logrus.SetLevel(logrus.DebugLevel)
+
+	tests := []struct {
+		name         string
+		sshStatus    int
+		lastLogEntry string
+	}{
+		{"ok", 0, "Registry plugin successfully uninstalled."},
+		{"ssh failed", 1, "Unable to remove the registry configuration from the cluster nodes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			testutils.ClearTestProcessResponse()
+			testutils.MockWithoutResponse(tt.sshStatus, "minikube", "-p", "minikube", "ssh", "-n", "minikube", "--",
+				fmt.Sprintf("sudo sed -i '/ registry.minikube$/d' /etc/hosts && sudo rm -f %s && sudo rm -f %s && sudo rm -f %s && sudo update-ca-certificates --fresh", caFiles[0], caFiles[1], caFiles[2]))
+
+			handler := fake.NewContextHandler(nil, nil)
+			handler.MiniKube = true
+			helmManager := helmFake.NewMockManager(ctrl)
+			helmManager.EXPECT().Uninstall(releaseName, "mks", true)
+
+			NewRegistry(helmManager, handler, ingress.NewSelector(nil, handler), nil).Uninstall(false)
+
+			testutils.CheckLogEntry(t, hook, tt.lastLogEntry)
+		})
+	}
+}
+
+func Test_registry_Values(t *testing.T) {
+	overrides := &helm.Overrides{Set: []string{"registry.persistence.enabled=false", "registry.ingress.annotations.nginx\\.ingress\\.kubernetes\\.io/proxy-body-size=1g"}}
+
+	handler := fake.NewContextHandler(nil, nil)
+	values, e := NewRegistry(nil, handler, ingress.NewSelector(nil, handler), overrides).(*registry).Values()
+
+	assert.NoError(t, e)
+	assert.Equal(t, map[string]interface{}{"enabled": false}, values["persistence"])
+	annotations := values["ingress"].(map[string]interface{})["annotations"].(map[string]interface{})
+	assert.Equal(t, "1g", annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+	assert.Equal(t, issuerName, annotations["cert-manager.io/cluster-issuer"])
+}
+
+func Test_registry_Values_nginx(t *testing.T) {
+	handler := fake.NewContextHandler(nil, nil)
+
+	values, e := NewRegistry(nil, handler, ingress.NewSelector(nil, handler), nil).(*registry).Values()
+
+	assert.NoError(t, e)
+	annotations := values["ingress"].(map[string]interface{})["annotations"].(map[string]interface{})
+	assert.Equal(t, "0", annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+}
+
+func Test_registry_Values_traefik(t *testing.T) {
+	kind := ingress.KindTraefik
+	handler := fake.NewContextHandler(nil, nil)
+
+	values, e := NewRegistry(nil, handler, ingress.NewSelector(&kind, handler), nil).(*registry).Values()
+
+	assert.NoError(t, e)
+	ingressValues := values["ingress"].(map[string]interface{})
+	assert.Equal(t, "traefik", ingressValues["className"])
+	assert.Equal(t, map[string]interface{}{"cert-manager.io/cluster-issuer": issuerName}, ingressValues["annotations"])
+}
+
+func controllerService(clusterIP string) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nginx-ingress-ingress-nginx-controller",
+			Namespace: "mks",
+			Labels:    map[string]string{"app.kubernetes.io/component": "controller"},
+		},
+		Spec: v1.ServiceSpec{ClusterIP: clusterIP},
+	}
+}
+
+func TestHelperProcess(t *testing.T) {
+	testutils.StandardHelperProcess(t)
+}
@@ -0,0 +1,84 @@
+package plugins
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+)
+
+// ReloadingPlugin restarts the wrapped plugin whenever the kube context or cluster
+// of its context handler changes. For example after `kubectl config use-context`.
+type ReloadingPlugin struct {
+	plugin         apis.StartStopPlugin
+	configWatcher  *kubernetes.ConfigWatcher
+	handler        kubernetes.ContextHandler
+	messageChannel chan *apis.MonitoringMessage
+	removeListener func()
+	mutex          sync.Mutex
+}
+
+// NewReloadingPlugin wraps the plugin, so that it will be restarted against the new cluster if the context
+// of the given handler changes.
+func NewReloadingPlugin(plugin apis.StartStopPlugin, configWatcher *kubernetes.ConfigWatcher, handler kubernetes.ContextHandler) *ReloadingPlugin {
+	return &ReloadingPlugin{
+		plugin:        plugin,
+		configWatcher: configWatcher,
+		handler:       handler,
+		mutex:         sync.Mutex{},
+	}
+}
+
+// String returns the name of the wrapped plugin.
+func (p *ReloadingPlugin) String() string {
+	return p.plugin.String()
+}
+
+func (p *ReloadingPlugin) IsSingleRunnable() bool {
+	return p.plugin.IsSingleRunnable()
+}
+
+// Start starts the wrapped plugin and begins to watch for context changes.
+func (p *ReloadingPlugin) Start(messageChannel chan *apis.MonitoringMessage) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.messageChannel = messageChannel
+	if p.removeListener == nil {
+		p.removeListener = p.configWatcher.AddListener(p.handler, p.restart)
+	}
+	return p.plugin.Start(messageChannel)
+}
+
+// Stop stops watching for context changes and stops the wrapped plugin.
+func (p *ReloadingPlugin) Stop() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.removeListener != nil {
+		p.removeListener()
+		p.removeListener = nil
+	}
+	return p.plugin.Stop()
+}
+
+// Health returns the health of the wrapped plugin.
+func (p *ReloadingPlugin) Health() error {
+	return apis.CheckHealth(p.plugin)
+}
+
+// restart stops the wrapped plugin and starts it again using the new context.
+func (p *ReloadingPlugin) restart() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	logrus.Infof("Restarting plugin %s for context %s", p.plugin, p.handler.GetContextName())
+	if e := p.plugin.Stop(); e != nil {
+		logrus.Warnf("Unable to terminate plugin %s: %s", p.plugin, e)
+	}
+	if _, e := p.plugin.Start(p.messageChannel); e != nil {
+		logrus.Errorf("Unable to restart plugin %s: %s", p.plugin, e)
+	}
+}
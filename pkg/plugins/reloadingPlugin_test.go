@@ -0,0 +1,58 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+)
+
+func TestReloadingPlugin(t *testing.T) {
+	configFile := ""
+	handler := fake.NewContextHandler(nil, nil)
+	plugin := &countingPlugin{}
+	p := NewReloadingPlugin(plugin, kubernetes.NewConfigWatcher(&configFile), handler)
+	messageChannel := make(chan *apis.MonitoringMessage, 1)
+
+	box, err := p.Start(messageChannel)
+	assert.NoError(t, err)
+	assert.Equal(t, "counting", box)
+	assert.NotNil(t, p.removeListener)
+
+	p.restart()
+	assert.Equal(t, 2, plugin.started)
+	assert.Equal(t, 1, plugin.stopped)
+	assert.Equal(t, messageChannel, plugin.messageChannel)
+
+	assert.NoError(t, p.Stop())
+	assert.Nil(t, p.removeListener)
+	assert.Equal(t, 2, plugin.stopped)
+}
+
+type countingPlugin struct {
+	started        int
+	stopped        int
+	messageChannel chan *apis.MonitoringMessage
+}
+
+func (c *countingPlugin) String() string {
+	return "counting"
+}
+
+func (c *countingPlugin) Start(messageChannel chan *apis.MonitoringMessage) (string, error) {
+	c.started++
+	c.messageChannel = messageChannel
+	return "counting", nil
+}
+
+func (c *countingPlugin) Stop() error {
+	c.stopped++
+	return nil
+}
+
+func (c *countingPlugin) IsSingleRunnable() bool {
+	return false
+}
@@ -0,0 +1,368 @@
+package trustbundle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8s "k8s.io/client-go/kubernetes"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+)
+
+const (
+	pluginName = "trust-bundle"
+	// ConfigMapName is the name of the config map containing the CA in every namespace.
+	ConfigMapName = "minikube-ca"
+	// PEMKey, JKSKey and PKCS12Key are the keys of the CA certificate in the different formats.
+	PEMKey    = "ca.crt"
+	JKSKey    = "truststore.jks"
+	PKCS12Key = "truststore.p12"
+)
+
+var managedByLabel = map[string]string{"app.kubernetes.io/managed-by": "minikube-support"}
+
+// reloadInterval is the time after which the CA is loaded again to publish a rotated or retired CA.
+var reloadInterval = time.Minute
+
+// bundle is the content of the config map.
+type bundle struct {
+	pem    string
+	jks    []byte
+	pkcs12 []byte
+}
+
+// trustBundle is a plugin that publishes the CA certificate, without its key, as config map into every
+// namespace or only into the selected ones. New namespaces get the config map as soon as they are created.
+type trustBundle struct {
+	contextHandler kubernetes.ContextHandler
+	informers      *kubernetes.Informers
+	namespaces     *[]string
+	messageChannel chan *apis.MonitoringMessage
+	clientSet      k8s.Interface
+	watch          *kubernetes.Watcher
+	bundle         *bundle
+	// distributed contains the PEM encoded certificates applied per namespace.
+	distributed map[string]string
+	stop        chan struct{}
+	running     sync.WaitGroup
+	mutex       sync.Mutex
+}
+
+// NewTrustBundle creates the plugin distributing the CA into the given namespaces. If there are none, the CA is
+// distributed into all namespaces.
+func NewTrustBundle(handler kubernetes.ContextHandler, informers *kubernetes.Informers, namespaces *[]string) apis.StartStopPlugin {
+	if informers == nil {
+		informers = kubernetes.NewInformers(kubernetes.DefaultResyncPeriod)
+	}
+	return &trustBundle{
+		contextHandler: handler,
+		informers:      informers,
+		namespaces:     namespaces,
+		distributed:    map[string]string{},
+		mutex:          sync.Mutex{},
+	}
+}
+
+func (t *trustBundle) String() string {
+	return pluginName
+}
+
+func (*trustBundle) IsSingleRunnable() bool {
+	return true
+}
+
+// Start loads the CA and starts watching the namespaces. The CA is loaded again every reloadInterval.
+func (t *trustBundle) Start(messageChannel chan *apis.MonitoringMessage) (string, error) {
+	b, e := loadBundle()
+	if e != nil {
+		return "", e
+	}
+	clientSet, e := t.contextHandler.GetClientSet()
+	if e != nil {
+		return "", fmt.Errorf("can not get clientSet: %s", e)
+	}
+
+	t.mutex.Lock()
+	t.messageChannel = messageChannel
+	t.bundle = b
+	t.clientSet = clientSet
+	t.mutex.Unlock()
+
+	t.watch, e = t.informers.Watch(namespaceAccessor{clientSet: clientSet}, &metav1.ListOptions{}, t)
+	if e != nil {
+		return "", fmt.Errorf("can not start watcher: %s", e)
+	}
+
+	t.mutex.Lock()
+	t.stop = make(chan struct{})
+	t.running.Add(1)
+	go t.reload(t.stop)
+	t.mutex.Unlock()
+	return pluginName, nil
+}
+
+// Stop stops watching the namespaces. The config maps are kept.
+func (t *trustBundle) Stop() error {
+	t.mutex.Lock()
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+	}
+	t.mutex.Unlock()
+	t.running.Wait()
+
+	if t.watch != nil {
+		t.watch.Stop()
+		t.watch = nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.distributed = map[string]string{}
+	return nil
+}
+
+// reload loads the CA after every reload interval until the stop channel is closed. If it has changed, it is
+// published into all namespaces containing the previous one.
+func (t *trustBundle) reload(stop chan struct{}) {
+	defer t.running.Done()
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		b, e := loadBundle()
+		if e != nil {
+			logrus.Warnf("Can not reload the CA for the trust bundle: %s", e)
+			continue
+		}
+		t.mutex.Lock()
+		changed := t.bundle.pem != b.pem
+		t.bundle = b
+		var namespaces []string
+		for name := range t.distributed {
+			namespaces = append(namespaces, name)
+		}
+		messageChannel := t.messageChannel
+		t.mutex.Unlock()
+		if !changed {
+			continue
+		}
+
+		for _, name := range namespaces {
+			if e := t.apply(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}); e != nil {
+				logrus.Warnf("Can not publish the reloaded CA: %s", e)
+			}
+		}
+		select {
+		case messageChannel <- t.status():
+		case <-stop:
+			return
+		}
+	}
+}
+
+// AddedEvent publishes the CA into the new namespace.
+func (t *trustBundle) AddedEvent(obj runtime.Object) error {
+	namespace, ok := obj.(*v1.Namespace)
+	if !ok {
+		return fmt.Errorf("can not handle non namespace object")
+	}
+	return t.apply(namespace)
+}
+
+// UpdatedEvent publishes the CA into the namespace if it is not already done.
+func (t *trustBundle) UpdatedEvent(obj runtime.Object) error {
+	return t.AddedEvent(obj)
+}
+
+// DeletedEvent forgets the deleted namespace.
+func (t *trustBundle) DeletedEvent(obj runtime.Object) error {
+	namespace, ok := obj.(*v1.Namespace)
+	if !ok {
+		return fmt.Errorf("can not handle non namespace object")
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.distributed, namespace.Name)
+	return nil
+}
+
+// Reconcile publishes the CA into all existing namespaces.
+func (t *trustBundle) Reconcile(objects []runtime.Object) error {
+	var failed []string
+	existing := map[string]bool{}
+	for _, obj := range objects {
+		namespace, ok := obj.(*v1.Namespace)
+		if !ok {
+			continue
+		}
+		existing[namespace.Name] = true
+		if e := t.apply(namespace); e != nil {
+			failed = append(failed, e.Error())
+		}
+	}
+
+	t.mutex.Lock()
+	for name := range t.distributed {
+		if !existing[name] {
+			delete(t.distributed, name)
+		}
+	}
+	t.mutex.Unlock()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// PostEvent sends the namespaces containing the CA to the box.
+func (t *trustBundle) PostEvent() error {
+	t.mutex.Lock()
+	messageChannel := t.messageChannel
+	t.mutex.Unlock()
+	messageChannel <- t.status()
+	return nil
+}
+
+// status returns the message listing the namespaces containing the CA.
+func (t *trustBundle) status() *apis.MonitoringMessage {
+	t.mutex.Lock()
+	var namespaces []string
+	for name := range t.distributed {
+		namespaces = append(namespaces, name)
+	}
+	t.mutex.Unlock()
+
+	sort.Strings(namespaces)
+	return &apis.MonitoringMessage{
+		Box:     pluginName,
+		Message: fmt.Sprintf("Config map %s is available in %d namespaces:\n%s", ConfigMapName, len(namespaces), strings.Join(namespaces, ", ")),
+	}
+}
+
+// selected checks if the CA should be published into the given namespace.
+func (t *trustBundle) selected(namespace string) bool {
+	if len(*t.namespaces) == 0 {
+		return true
+	}
+	for _, name := range *t.namespaces {
+		if name == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// apply creates or updates the config map in the given namespace.
+func (t *trustBundle) apply(namespace *v1.Namespace) error {
+	if !t.selected(namespace.Name) || namespace.Status.Phase == v1.NamespaceTerminating {
+		return nil
+	}
+	t.mutex.Lock()
+	b, clientSet, applied := t.bundle, t.clientSet, t.distributed[namespace.Name]
+	t.mutex.Unlock()
+	if applied == b.pem {
+		return nil
+	}
+
+	configMap := &v1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace.Name, Name: ConfigMapName, Labels: managedByLabel},
+		Data:       map[string]string{PEMKey: b.pem},
+		BinaryData: map[string][]byte{JKSKey: b.jks, PKCS12Key: b.pkcs12},
+	}
+	configMaps := clientSet.CoreV1().ConfigMaps(namespace.Name)
+	old, e := configMaps.Get(context.Background(), ConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(e) {
+		_, e = configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+	} else if e == nil && (old.Data[PEMKey] != b.pem || len(old.BinaryData[JKSKey]) == 0 || len(old.BinaryData[PKCS12Key]) == 0) {
+		configMap.ResourceVersion = old.ResourceVersion
+		_, e = configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{})
+	}
+	if e != nil {
+		return fmt.Errorf("can not apply config map %s/%s: %s", namespace.Name, ConfigMapName, e)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.distributed[namespace.Name] = b.pem
+	return nil
+}
+
+// loadBundle loads the CA certificate and the ones of not yet retired previous CAs and converts them
+// into all formats.
+func loadBundle() (*bundle, error) {
+	dir, e := ca.DefaultDir()
+	if e != nil {
+		return nil, e
+	}
+	certificates, e := ca.Bundle(dir)
+	if e != nil {
+		return nil, fmt.Errorf("unable to load the RootCA: %s", e)
+	}
+	return newBundle(ca.EncodeCertificatesPEM(certificates...))
+}
+
+// newBundle converts the given PEM encoded certificates into all formats.
+func newBundle(data []byte) (*bundle, error) {
+	certificates, e := ca.ParseCertificates(data)
+	if e != nil {
+		return nil, e
+	}
+	jks, e := ca.EncodeJKSTrustStore(certificates, ca.DefaultPassword)
+	if e != nil {
+		return nil, e
+	}
+	p12, e := ca.EncodePKCS12TrustStore(certificates, ca.DefaultPassword)
+	if e != nil {
+		return nil, e
+	}
+	return &bundle{pem: string(data), jks: jks, pkcs12: p12}, nil
+}
+
+// namespaceAccessor provides list and watch access to namespaces.
+type namespaceAccessor struct {
+	clientSet k8s.Interface
+}
+
+// Object returns an empty namespace.
+func (namespaceAccessor) Object() runtime.Object {
+	return &v1.Namespace{}
+}
+
+// Key identifies the namespaces.
+func (namespaceAccessor) Key() string {
+	return "namespaces"
+}
+
+// List returns a list of all namespaces.
+func (n namespaceAccessor) List(options metav1.ListOptions) (runtime.Object, error) {
+	namespaces, e := n.clientSet.CoreV1().Namespaces().List(context.Background(), options)
+	if e != nil {
+		return nil, fmt.Errorf("can not list namespaces: %s", e)
+	}
+	return namespaces, nil
+}
+
+// Watch starts the watch process for namespaces.
+func (n namespaceAccessor) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return n.clientSet.CoreV1().Namespaces().Watch(context.Background(), options)
+}
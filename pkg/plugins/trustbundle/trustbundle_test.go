@@ -0,0 +1,164 @@
+package trustbundle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/qaware/minikube-support/pkg/apis"
+	"github.com/qaware/minikube-support/pkg/ca"
+	"github.com/qaware/minikube-support/pkg/kubernetes"
+	"github.com/qaware/minikube-support/pkg/kubernetes/fake"
+)
+
+func Test_trustBundle_Reconcile(t *testing.T) {
+	authority, _ := ca.Generate()
+	b, e := newBundle(authority.CertificatePEM())
+	assert.NoError(t, e)
+
+	tests := []struct {
+		name          string
+		selected      []string
+		existing      []runtime.Object
+		wantConfigMap []string
+		wantUpdated   bool
+	}{
+		{"all namespaces", nil, nil, []string{"default", "app"}, false},
+		{"selected namespaces", []string{"app"}, nil, []string{"app"}, false},
+		{"outdated config map", []string{"app"}, []runtime.Object{&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: ConfigMapName},
+			Data:       map[string]string{PEMKey: "old"},
+		}}, []string{"app"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSet := k8sFake.NewClientset(tt.existing...)
+			plugin := NewTrustBundle(fake.NewContextHandler(clientSet, nil), nil, &tt.selected).(*trustBundle)
+			plugin.bundle = b
+			plugin.clientSet = clientSet
+			plugin.messageChannel = make(chan *apis.MonitoringMessage, 1)
+
+			e := plugin.Reconcile([]runtime.Object{namespace("default"), namespace("app"), terminating("old")})
+			assert.NoError(t, e)
+			assert.NoError(t, plugin.PostEvent())
+			assert.Contains(t, (<-plugin.messageChannel).Message, "is available in")
+
+			configMaps, _ := clientSet.CoreV1().ConfigMaps("").List(context.Background(), metav1.ListOptions{})
+			var namespaces []string
+			for _, configMap := range configMaps.Items {
+				namespaces = append(namespaces, configMap.Namespace)
+				assert.Equal(t, b.pem, configMap.Data[PEMKey])
+				assert.NotEmpty(t, configMap.BinaryData[JKSKey])
+				assert.NotEmpty(t, configMap.BinaryData[PKCS12Key])
+			}
+			assert.ElementsMatch(t, tt.wantConfigMap, namespaces)
+
+			updated := false
+			for _, action := range clientSet.Actions() {
+				updated = updated || action.GetVerb() == "update"
+			}
+			assert.Equal(t, tt.wantUpdated, updated)
+		})
+	}
+}
+
+func Test_newBundle(t *testing.T) {
+	authority, _ := ca.Generate()
+
+	b, e := newBundle(authority.CertificatePEM())
+	assert.NoError(t, e)
+	assert.Equal(t, string(authority.CertificatePEM()), b.pem)
+	certificates, e := pkcs12.DecodeTrustStore(b.pkcs12, ca.DefaultPassword)
+	assert.NoError(t, e)
+	assert.Equal(t, authority.Certificate.Raw, certificates[0].Raw)
+	assert.NotEmpty(t, b.jks)
+
+	_, e = newBundle([]byte("no certificate"))
+	assert.EqualError(t, e, "no certificate found")
+}
+
+func Test_trustBundle_Start(t *testing.T) {
+	t.Setenv(ca.DirEnv, t.TempDir())
+
+	clientSet := k8sFake.NewClientset(namespace("default"))
+	plugin := NewTrustBundle(fake.NewContextHandler(clientSet, nil), kubernetes.NewInformers(kubernetes.DefaultResyncPeriod), &[]string{})
+	messages := make(chan *apis.MonitoringMessage, 10)
+
+	box, e := plugin.Start(messages)
+	assert.NoError(t, e)
+	assert.Equal(t, "trust-bundle", box)
+	waitForMessage(t, messages, "available in 1 namespaces")
+
+	_, _ = clientSet.CoreV1().Namespaces().Create(context.Background(), namespace("app"), metav1.CreateOptions{})
+	waitForMessage(t, messages, "available in 2 namespaces")
+	_, e = clientSet.CoreV1().ConfigMaps("app").Get(context.Background(), ConfigMapName, metav1.GetOptions{})
+	assert.NoError(t, e)
+
+	assert.NoError(t, plugin.Stop())
+}
+
+func Test_trustBundle_Start_reload(t *testing.T) {
+	reloadInterval = 10 * time.Millisecond
+	defer func() { reloadInterval = time.Minute }()
+	dir := t.TempDir()
+	t.Setenv(ca.DirEnv, dir)
+
+	clientSet := k8sFake.NewClientset(namespace("default"))
+	plugin := NewTrustBundle(fake.NewContextHandler(clientSet, nil), kubernetes.NewInformers(kubernetes.DefaultResyncPeriod), &[]string{})
+	messages := make(chan *apis.MonitoringMessage, 10)
+
+	_, e := plugin.Start(messages)
+	assert.NoError(t, e)
+	waitForMessage(t, messages, "available in 1 namespaces")
+
+	_, _, e = ca.Rotate(dir)
+	assert.NoError(t, e)
+	assert.Eventually(t, func() bool {
+		configMap, e := clientSet.CoreV1().ConfigMaps("default").Get(context.Background(), ConfigMapName, metav1.GetOptions{})
+		return e == nil && strings.Count(configMap.Data[PEMKey], "BEGIN CERTIFICATE") == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, plugin.Stop())
+}
+
+func Test_trustBundle_Start_noCA(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(ca.DirEnv, dir)
+	_ = os.WriteFile(filepath.Join(dir, ca.CertFile), []byte("invalid"), 0600)
+
+	plugin := NewTrustBundle(fake.NewContextHandler(k8sFake.NewClientset(), nil), nil, &[]string{})
+	_, e := plugin.Start(make(chan *apis.MonitoringMessage, 1))
+	assert.ErrorContains(t, e, "unable to load the RootCA")
+}
+
+func waitForMessage(t *testing.T, messages chan *apis.MonitoringMessage, content string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case message := <-messages:
+			if strings.Contains(message.Message, content) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for message %q", content)
+		}
+	}
+}
+
+func namespace(name string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: v1.NamespaceStatus{Phase: v1.NamespaceActive}}
+}
+
+func terminating(name string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: v1.NamespaceStatus{Phase: v1.NamespaceTerminating}}
+}
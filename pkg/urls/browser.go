@@ -0,0 +1,26 @@
+package urls
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/qaware/minikube-support/pkg/sh"
+)
+
+// OpenBrowser opens the url in the default browser of the local os.
+func OpenBrowser(url string) error {
+	var output string
+	var e error
+	switch runtime.GOOS {
+	case "darwin":
+		output, e = sh.RunCmd("open", url)
+	case "windows":
+		output, e = sh.RunCmd("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		output, e = sh.RunCmd("xdg-open", url)
+	}
+	if e != nil {
+		return fmt.Errorf("can not open %s in the browser: %s\n%s", url, e, output)
+	}
+	return nil
+}
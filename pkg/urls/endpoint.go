@@ -0,0 +1,173 @@
+package urls
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/qaware/minikube-support/pkg/plugins/portforward"
+)
+
+// The types of the collected endpoints.
+const (
+	TypeIngress     = "Ingress"
+	TypeService     = "Service"
+	TypePortForward = "PortForward"
+)
+
+// The schemes of the endpoints. Only http and https endpoints are probed with a request.
+const (
+	SchemeHttp  = "http"
+	SchemeHttps = "https"
+	SchemeTcp   = "tcp"
+)
+
+// Endpoint is a host and port which is reachable from the local os.
+type Endpoint struct {
+	// Type is the type of the object which provides the endpoint.
+	Type string `json:"type"`
+	// Name is the name of the object like <namespace>/<name>.
+	Name   string `json:"name"`
+	Scheme string `json:"scheme"`
+	Host   string `json:"host"`
+	Port   int    `json:"port"`
+}
+
+// URL returns the url of the endpoint. The port is omitted if it is the default port of the scheme.
+func (e Endpoint) URL() string {
+	if (e.Scheme == SchemeHttp && e.Port == 80) || (e.Scheme == SchemeHttps && e.Port == 443) {
+		return e.Scheme + "://" + e.Host + "/"
+	}
+	url := e.Scheme + "://" + e.Address()
+	if e.Scheme != SchemeTcp {
+		url += "/"
+	}
+	return url
+}
+
+// Address returns the address of the endpoint as <host>:<port>.
+func (e Endpoint) Address() string {
+	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+}
+
+// Collect returns the endpoints of all ingresses with a host, all LoadBalancer services and the given port
+// forwards. If forwardDns is set, service forwards are addressed by their *.pf.minikube name.
+func Collect(clientSet kubernetes.Interface, forwards []portforward.Forward, forwardDns bool) ([]Endpoint, error) {
+	ctx := context.Background()
+	ingresses, e := clientSet.NetworkingV1().Ingresses(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if e != nil {
+		return nil, fmt.Errorf("can not list ingresses: %s", e)
+	}
+	services, e := clientSet.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if e != nil {
+		return nil, fmt.Errorf("can not list services: %s", e)
+	}
+
+	var endpoints []Endpoint
+	for _, ingress := range ingresses.Items {
+		endpoints = append(endpoints, ingressEndpoints(&ingress)...)
+	}
+	for _, service := range services.Items {
+		endpoints = append(endpoints, serviceEndpoints(&service)...)
+	}
+	for _, forward := range forwards {
+		endpoints = append(endpoints, forwardEndpoints(forward, forwardDns)...)
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].Type != endpoints[j].Type {
+			return endpoints[i].Type < endpoints[j].Type
+		}
+		if endpoints[i].Host != endpoints[j].Host {
+			return endpoints[i].Host < endpoints[j].Host
+		}
+		return endpoints[i].Port < endpoints[j].Port
+	})
+	return endpoints, nil
+}
+
+// ingressEndpoints returns an endpoint for every host of the ingress. Hosts listed in a tls section are served
+// with https, all others with http. Wildcard hosts are skipped as they can not be resolved.
+func ingressEndpoints(ingress *networkingV1.Ingress) []Endpoint {
+	tlsHosts := map[string]bool{}
+	for _, tls := range ingress.Spec.TLS {
+		for _, host := range tls.Hosts {
+			tlsHosts[host] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	var endpoints []Endpoint
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host == "" || strings.HasPrefix(rule.Host, "*") || seen[rule.Host] {
+			continue
+		}
+		seen[rule.Host] = true
+		endpoint := Endpoint{Type: TypeIngress, Name: ingress.Namespace + "/" + ingress.Name, Scheme: SchemeHttp, Host: rule.Host, Port: 80}
+		if tlsHosts[rule.Host] {
+			endpoint.Scheme = SchemeHttps
+			endpoint.Port = 443
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// serviceEndpoints returns an endpoint for every port of a LoadBalancer service using the name served by the
+// k8sdns plugin.
+func serviceEndpoints(service *v1.Service) []Endpoint {
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	var endpoints []Endpoint
+	for _, port := range service.Spec.Ports {
+		endpoints = append(endpoints, Endpoint{
+			Type:   TypeService,
+			Name:   service.Namespace + "/" + service.Name,
+			Scheme: scheme(port.AppProtocol, port.Name, int(port.Port)),
+			Host:   fmt.Sprintf("%s.%s.svc.minikube", service.Name, service.Namespace),
+			Port:   int(port.Port),
+		})
+	}
+	return endpoints
+}
+
+// forwardEndpoints returns an endpoint for every local port of the forward. Random local ports are skipped.
+func forwardEndpoints(forward portforward.Forward, forwardDns bool) []Endpoint {
+	host := "localhost"
+	if forwardDns && forward.HostName() != "" {
+		host = forward.HostName()
+	}
+	var endpoints []Endpoint
+	for _, port := range forward.Ports {
+		local, remote, e := portforward.SplitPort(port)
+		if e != nil || local == 0 {
+			continue
+		}
+		endpoints = append(endpoints, Endpoint{Type: TypePortForward, Name: forward.String(), Scheme: scheme(nil, "", remote), Host: host, Port: local})
+	}
+	return endpoints
+}
+
+// scheme guesses the scheme of a port by its application protocol, its name or its number.
+func scheme(appProtocol *string, name string, port int) string {
+	protocol := strings.ToLower(name)
+	if appProtocol != nil {
+		protocol = strings.ToLower(*appProtocol)
+	}
+	switch {
+	case strings.HasPrefix(protocol, SchemeHttps) || port == 443 || port == 8443:
+		return SchemeHttps
+	case strings.HasPrefix(protocol, SchemeHttp) || port == 80 || port == 8080:
+		return SchemeHttp
+	default:
+		return SchemeTcp
+	}
+}
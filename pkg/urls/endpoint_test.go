@@ -0,0 +1,85 @@
+package urls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/qaware/minikube-support/pkg/plugins/portforward"
+)
+
+func TestCollect(t *testing.T) {
+	https := "HTTPS"
+	clientSet := k8sFake.NewClientset(
+		&networkingV1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "web"},
+			Spec: networkingV1.IngressSpec{
+				TLS:   []networkingV1.IngressTLS{{Hosts: []string{"secure.minikube"}}},
+				Rules: []networkingV1.IngressRule{{Host: "web.minikube"}, {Host: "secure.minikube"}, {Host: "web.minikube"}, {Host: "*.minikube"}, {}},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "postgres"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, Ports: []v1.ServicePort{{Name: "sql", Port: 5432}, {Name: "admin", Port: 9000, AppProtocol: &https}}},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "internal"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, Ports: []v1.ServicePort{{Port: 80}}},
+		},
+	)
+	forwards := []portforward.Forward{
+		{Namespace: "db", Service: "redis", Ports: []string{"6379", "0:6380"}},
+		{Namespace: "app", Selector: "app=web", Ports: []string{"18080:8080"}},
+	}
+
+	tests := []struct {
+		name       string
+		forwardDns bool
+		want       []Endpoint
+	}{
+		{"localhost forwards", false, []Endpoint{
+			{Type: TypeIngress, Name: "app/web", Scheme: SchemeHttps, Host: "secure.minikube", Port: 443},
+			{Type: TypeIngress, Name: "app/web", Scheme: SchemeHttp, Host: "web.minikube", Port: 80},
+			{Type: TypePortForward, Name: "db/svc/redis", Scheme: SchemeTcp, Host: "localhost", Port: 6379},
+			{Type: TypePortForward, Name: "app/pod/app=web", Scheme: SchemeHttp, Host: "localhost", Port: 18080},
+			{Type: TypeService, Name: "db/postgres", Scheme: SchemeTcp, Host: "postgres.db.svc.minikube", Port: 5432},
+			{Type: TypeService, Name: "db/postgres", Scheme: SchemeHttps, Host: "postgres.db.svc.minikube", Port: 9000},
+		}},
+		{"forward dns", true, []Endpoint{
+			{Type: TypeIngress, Name: "app/web", Scheme: SchemeHttps, Host: "secure.minikube", Port: 443},
+			{Type: TypeIngress, Name: "app/web", Scheme: SchemeHttp, Host: "web.minikube", Port: 80},
+			{Type: TypePortForward, Name: "app/pod/app=web", Scheme: SchemeHttp, Host: "localhost", Port: 18080},
+			{Type: TypePortForward, Name: "db/svc/redis", Scheme: SchemeTcp, Host: "redis.db.pf.minikube", Port: 6379},
+			{Type: TypeService, Name: "db/postgres", Scheme: SchemeTcp, Host: "postgres.db.svc.minikube", Port: 5432},
+			{Type: TypeService, Name: "db/postgres", Scheme: SchemeHttps, Host: "postgres.db.svc.minikube", Port: 9000},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := Collect(clientSet, forwards, tt.forwardDns)
+			assert.NoError(t, e)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEndpoint_URL(t *testing.T) {
+	tests := []struct {
+		endpoint Endpoint
+		want     string
+	}{
+		{Endpoint{Scheme: SchemeHttps, Host: "web.minikube", Port: 443}, "https://web.minikube/"},
+		{Endpoint{Scheme: SchemeHttp, Host: "web.minikube", Port: 80}, "http://web.minikube/"},
+		{Endpoint{Scheme: SchemeHttps, Host: "web.minikube", Port: 8443}, "https://web.minikube:8443/"},
+		{Endpoint{Scheme: SchemeTcp, Host: "localhost", Port: 5432}, "tcp://localhost:5432"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.endpoint.URL())
+		})
+	}
+}
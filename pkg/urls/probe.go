@@ -0,0 +1,159 @@
+package urls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+)
+
+// DefaultTimeout is the default time to wait for each probe of an endpoint.
+const DefaultTimeout = 5 * time.Second
+
+// Result is the result of probing an endpoint.
+type Result struct {
+	Endpoint
+	// Addresses are the ip addresses the host resolves to.
+	Addresses []string `json:"addresses"`
+	// Connected is set if a tcp connection to the endpoint could be established.
+	Connected bool `json:"connected"`
+	// StatusCode is the http status code. It is 0 for tcp endpoints or if the request failed.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Latency is the time of the http request or the tcp connect for tcp endpoints.
+	Latency   time.Duration `json:"-"`
+	LatencyMs int64         `json:"latencyMs"`
+	// Error describes the first failed probe.
+	Error string `json:"error,omitempty"`
+}
+
+// Prober checks if endpoints are reachable. It resolves the host names through the resolver of the local os,
+// connects to the port and sends a request to http and https endpoints. Certificates are validated against
+// the system roots and the local CA.
+type Prober struct {
+	timeout    time.Duration
+	rootCAs    *x509.CertPool
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	dial       func(ctx context.Context, network string, address string) (net.Conn, error)
+}
+
+// NewProber creates a prober which trusts the given root certificates and waits up to timeout for each probe.
+func NewProber(rootCAs *x509.CertPool, timeout time.Duration) *Prober {
+	dialer := &net.Dialer{}
+	return &Prober{
+		timeout:    timeout,
+		rootCAs:    rootCAs,
+		lookupHost: net.DefaultResolver.LookupHost,
+		dial:       dialer.DialContext,
+	}
+}
+
+// LocalRootCAs returns the system roots including the local CA and the not yet retired previous CAs if the local CA
+// exists. The CA is not created if it is missing.
+func LocalRootCAs() (*x509.CertPool, error) {
+	pool, e := x509.SystemCertPool()
+	if e != nil {
+		pool = x509.NewCertPool()
+	}
+	dir, e := ca.DefaultDir()
+	if e != nil {
+		return nil, e
+	}
+	if !ca.Exists(dir) {
+		return pool, nil
+	}
+	certificates, e := ca.Bundle(dir)
+	if e != nil {
+		return nil, fmt.Errorf("can not load the local CA: %s", e)
+	}
+	for _, certificate := range certificates {
+		pool.AddCert(certificate)
+	}
+	return pool, nil
+}
+
+// Probe probes all endpoints in parallel and returns the results in the order of the endpoints.
+func (p *Prober) Probe(endpoints []Endpoint) []Result {
+	results := make([]Result, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint Endpoint) {
+			defer wg.Done()
+			results[i] = p.probe(endpoint)
+		}(i, endpoint)
+	}
+	wg.Wait()
+	return results
+}
+
+// probe resolves, connects and requests the endpoint. It stops at the first failed step.
+func (p *Prober) probe(endpoint Endpoint) Result {
+	result := Result{Endpoint: endpoint}
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	addresses, e := p.lookupHost(ctx, endpoint.Host)
+	if e != nil {
+		result.Error = fmt.Sprintf("dns: %s", e)
+		return result
+	}
+	result.Addresses = addresses
+
+	start := time.Now()
+	conn, e := p.dial(ctx, "tcp", endpoint.Address())
+	if e != nil {
+		result.Error = fmt.Sprintf("tcp: %s", e)
+		return result
+	}
+	result.setLatency(time.Since(start))
+	_ = conn.Close()
+	result.Connected = true
+
+	if endpoint.Scheme == SchemeTcp {
+		return result
+	}
+	start = time.Now()
+	statusCode, e := p.request(ctx, endpoint.URL())
+	if e != nil {
+		result.Error = fmt.Sprintf("%s: %s", endpoint.Scheme, e)
+		return result
+	}
+	result.setLatency(time.Since(start))
+	result.StatusCode = statusCode
+	return result
+}
+
+// request sends a GET request to the url and returns the status code. Redirects are not followed.
+func (p *Prober) request(ctx context.Context, url string) (int, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext:     p.dial,
+			TLSClientConfig: &tls.Config{RootCAs: p.rootCAs},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer client.CloseIdleConnections()
+	request, e := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if e != nil {
+		return 0, e
+	}
+	response, e := client.Do(request)
+	if e != nil {
+		return 0, e
+	}
+	_ = response.Body.Close()
+	return response.StatusCode, nil
+}
+
+func (r *Result) setLatency(latency time.Duration) {
+	r.Latency = latency
+	r.LatencyMs = latency.Milliseconds()
+}
@@ -0,0 +1,116 @@
+package urls
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qaware/minikube-support/pkg/ca"
+)
+
+func TestLocalRootCAs(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(ca.DirEnv, dir)
+	previous, e := ca.LoadOrCreate(dir)
+	require.NoError(t, e)
+	old, _ := previous.Issue([]string{"old.minikube"}, ca.DefaultValidity)
+	authority, _, e := ca.Rotate(dir)
+	require.NoError(t, e)
+	current, _ := authority.Issue([]string{"app.minikube"}, ca.DefaultValidity)
+
+	pool, e := LocalRootCAs()
+
+	require.NoError(t, e)
+	for _, leaf := range []*ca.Leaf{old, current} {
+		_, e := leaf.Certificate.Verify(x509.VerifyOptions{Roots: pool})
+		assert.NoError(t, e)
+	}
+}
+
+func TestProber_Probe(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(tlsServer.Certificate())
+	// the certificate of the test server is valid for example.com
+	secure := Endpoint{Type: TypeIngress, Name: "app/web", Scheme: SchemeHttps, Host: "example.com", Port: port(t, tlsServer.URL)}
+	plain := Endpoint{Type: TypeIngress, Name: "app/web", Scheme: SchemeHttp, Host: "web.minikube", Port: port(t, server.URL)}
+	tcp := Endpoint{Type: TypeService, Name: "db/postgres", Scheme: SchemeTcp, Host: "postgres.db.svc.minikube", Port: port(t, server.URL)}
+	closed := Endpoint{Type: TypeService, Name: "db/postgres", Scheme: SchemeTcp, Host: "postgres.db.svc.minikube", Port: 1}
+	unknown := Endpoint{Type: TypeIngress, Name: "app/other", Scheme: SchemeHttp, Host: "unknown.minikube", Port: 80}
+
+	tests := []struct {
+		name       string
+		rootCAs    *x509.CertPool
+		endpoint   Endpoint
+		connected  bool
+		statusCode int
+		err        string
+	}{
+		{"https", trusted, secure, true, http.StatusFound, ""},
+		{"untrusted", x509.NewCertPool(), secure, true, 0, "certificate signed by unknown authority"},
+		{"http", nil, plain, true, http.StatusFound, ""},
+		{"tcp", nil, tcp, true, 0, ""},
+		{"connection refused", nil, closed, false, 0, "tcp: connection refused"},
+		{"unknown host", nil, unknown, false, 0, "dns: no such host"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProber(tt.rootCAs, time.Second)
+			p.lookupHost = func(_ context.Context, host string) ([]string, error) {
+				if host == "unknown.minikube" {
+					return nil, errors.New("no such host")
+				}
+				return []string{"127.0.0.1"}, nil
+			}
+			p.dial = func(ctx context.Context, network string, address string) (net.Conn, error) {
+				_, port, _ := net.SplitHostPort(address)
+				if port == "1" {
+					return nil, errors.New("connection refused")
+				}
+				return (&net.Dialer{}).DialContext(ctx, network, "127.0.0.1:"+port)
+			}
+
+			results := p.Probe([]Endpoint{tt.endpoint})
+
+			assert.Len(t, results, 1)
+			assert.Equal(t, tt.endpoint, results[0].Endpoint)
+			assert.Equal(t, tt.connected, results[0].Connected)
+			assert.Equal(t, tt.statusCode, results[0].StatusCode)
+			if tt.err == "" {
+				assert.Empty(t, results[0].Error)
+			} else {
+				assert.Contains(t, results[0].Error, tt.err)
+			}
+		})
+	}
+}
+
+// port returns the port of the url of a test server.
+func port(t *testing.T, serverURL string) int {
+	parsed, e := url.Parse(serverURL)
+	assert.NoError(t, e)
+	p, e := strconv.Atoi(parsed.Port())
+	assert.NoError(t, e)
+	return p
+}
@@ -6,6 +6,8 @@ package utils
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -13,7 +15,7 @@ import (
 // Flatten takes a structure and turns into a flat map[string]string.
 //
 // Within the "thing" parameter, only primitive values are allowed. Structs are
-// not supported. Therefore, it can only be slices, maps, primitives, and
+// not supported. Therefore, it can only be slices, maps, primitives, nil, and
 // any combination of those together.
 //
 // See the tests for examples of what inputs are turned into.
@@ -39,8 +41,14 @@ func flatten(result map[string]string, prefix string, v reflect.Value) error {
 		} else {
 			result[prefix] = "false"
 		}
-	case reflect.Int:
-		result[prefix] = fmt.Sprintf("%d", v.Int())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		result[prefix] = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		result[prefix] = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		result[prefix] = strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Invalid:
+		result[prefix] = "null"
 	case reflect.Map:
 		return flattenMap(result, prefix, v)
 	case reflect.Slice:
@@ -64,7 +72,9 @@ func flattenMap(result map[string]string, prefix string, v reflect.Value) error
 			panic(fmt.Sprintf("%s: map key is not string: %s", prefix, k))
 		}
 
-		errors = multierror.Append(errors, flatten(result, fmt.Sprintf("%s.%s", prefix, k.String()), v.MapIndex(k)))
+		// dots within nested keys are escaped, so that they are not split into paths again
+		key := strings.ReplaceAll(k.String(), ".", "\\.")
+		errors = multierror.Append(errors, flatten(result, fmt.Sprintf("%s.%s", prefix, key), v.MapIndex(k)))
 	}
 	return errors.ErrorOrNil()
 }
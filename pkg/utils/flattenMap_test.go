@@ -77,6 +77,36 @@ func Test1Flatten(t *testing.T) {
 			},
 			wantErr: false,
 		},
+
+		{
+			Input: map[string]interface{}{
+				"controller": map[string]interface{}{
+					"replicaCount": int64(2),
+					"cpu":          0.5,
+					"port":         uint16(80),
+					"affinity":     nil,
+					"annotations": map[string]interface{}{
+						"prometheus.io/scrape": "true",
+					},
+				},
+			},
+			Output: map[string]string{
+				"controller.replicaCount":                       "2",
+				"controller.cpu":                                "0.5",
+				"controller.port":                               "80",
+				"controller.affinity":                           "null",
+				"controller.annotations.prometheus\\.io/scrape": "true",
+			},
+			wantErr: false,
+		},
+
+		{
+			Input: map[string]interface{}{
+				"foo": struct{}{},
+			},
+			Output:  map[string]string{},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range cases {